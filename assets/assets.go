@@ -0,0 +1,88 @@
+// Package assets embeds the game's content definitions, fonts, and tilesets so
+// the binary runs from any directory, and lets a directory on disk override
+// individual files.
+package assets
+
+import (
+	"cmp"
+	"embed"
+	"errors"
+	"io/fs"
+	"os"
+	"slices"
+)
+
+// embedded holds the bundled assets, addressed by slash separated paths such
+// as "fonts/Go-Mono.ttf".
+//
+//go:embed content fonts tilesets
+var embedded embed.FS
+
+// FS returns the bundled assets. When overrideDir is not empty, files found
+// in overrideDir take precedence over the embedded ones, so players and
+// modders can replace a font or tileset without rebuilding.
+func FS(overrideDir string) fs.FS {
+	if overrideDir == "" {
+		return embedded
+	}
+
+	return overlayFS{override: os.DirFS(overrideDir), base: embedded}
+}
+
+// overlayFS serves files from override when they exist there, and from base
+// otherwise.
+type overlayFS struct {
+	override fs.FS
+	base     fs.FS
+}
+
+// Open opens name from the override file system, falling back to the base
+// file system if the override does not have it.
+func (overlay overlayFS) Open(name string) (fs.File, error) {
+	file, err := overlay.override.Open(name)
+	if err == nil {
+		return file, nil
+	}
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	return overlay.base.Open(name)
+}
+
+// ReadDir lists the directory name in both file systems merged, so a
+// directory the override only partly replaces still lists the base files.
+// Where both have an entry of the same name, the override's is returned.
+func (overlay overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	overrides, overrideErr := fs.ReadDir(overlay.override, name)
+	if overrideErr != nil && !errors.Is(overrideErr, fs.ErrNotExist) {
+		return nil, overrideErr
+	}
+
+	bases, baseErr := fs.ReadDir(overlay.base, name)
+	if baseErr != nil && !errors.Is(baseErr, fs.ErrNotExist) {
+		return nil, baseErr
+	}
+
+	if overrideErr != nil && baseErr != nil {
+		return nil, baseErr
+	}
+
+	entries := slices.Clone(overrides)
+	for _, entry := range bases {
+		overridden := slices.ContainsFunc(overrides, func(override fs.DirEntry) bool {
+			return override.Name() == entry.Name()
+		})
+
+		if !overridden {
+			entries = append(entries, entry)
+		}
+	}
+
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return cmp.Compare(a.Name(), b.Name())
+	})
+
+	return entries, nil
+}
@@ -0,0 +1,97 @@
+package assets
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFS(t *testing.T) {
+	t.Run("serves embedded assets", func(t *testing.T) {
+		for _, name := range []string{"content/tiles.json", "fonts/Go-Mono.ttf", "tilesets/neon/tileset.json", "tilesets/neon/neon.png"} {
+			if _, err := fs.Stat(FS(""), name); err != nil {
+				t.Errorf("want %s embedded, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("override directory wins", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(dir, "tilesets", "neon"), 0o755); err != nil {
+			t.Fatalf("failed to create override dir: %v", err)
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, "tilesets", "neon", "tileset.json"), []byte("override"), 0o644); err != nil {
+			t.Fatalf("failed to write override: %v", err)
+		}
+
+		file, err := FS(dir).Open("tilesets/neon/tileset.json")
+		if err != nil {
+			t.Fatalf("failed to open overridden file: %v", err)
+		}
+
+		defer func() {
+			_ = file.Close()
+		}()
+
+		data, err := io.ReadAll(file)
+		if err != nil {
+			t.Fatalf("failed to read overridden file: %v", err)
+		}
+
+		if string(data) != "override" {
+			t.Errorf("want override contents, got %q", data)
+		}
+	})
+
+	t.Run("lists overridden and embedded files together", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(dir, "content"), 0o755); err != nil {
+			t.Fatalf("failed to create override dir: %v", err)
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, "content", "tiles.json"), []byte("override"), 0o644); err != nil {
+			t.Fatalf("failed to write override: %v", err)
+		}
+
+		want, err := fs.ReadDir(FS(""), "content")
+		if err != nil {
+			t.Fatalf("failed to list embedded content: %v", err)
+		}
+
+		got, err := fs.ReadDir(FS(dir), "content")
+		if err != nil {
+			t.Fatalf("failed to list content through overlay: %v", err)
+		}
+
+		if len(got) != len(want) {
+			t.Fatalf("want %d entries, got %d", len(want), len(got))
+		}
+
+		for i, entry := range got {
+			if entry.Name() != want[i].Name() {
+				t.Errorf("want entry %d named %s, got %s", i, want[i].Name(), entry.Name())
+			}
+		}
+
+		data, err := fs.ReadFile(FS(dir), "content/tiles.json")
+		if err != nil || string(data) != "override" {
+			t.Errorf("want the overridden tiles.json read, got %q, %v", data, err)
+		}
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		if _, err := fs.ReadDir(FS(t.TempDir()), "sounds"); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("want %v, got %v", fs.ErrNotExist, err)
+		}
+	})
+
+	t.Run("falls back to embedded", func(t *testing.T) {
+		if _, err := fs.Stat(FS(t.TempDir()), "fonts/Go-Mono.ttf"); err != nil {
+			t.Errorf("want embedded font through overlay, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+// Command controlsdoc regenerates the controls tables in the README from the
+// default keymap so the documentation cannot drift from the game.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/theantichris/sprawlrunner/internal/game"
+)
+
+const (
+	startMarker = "<!-- controls:start -->"
+	endMarker   = "<!-- controls:end -->"
+)
+
+// main rewrites the section of the README between the controls markers.
+func main() {
+	readmePath := flag.String("readme", "README.md", "path to the README to update")
+	flag.Parse()
+
+	if err := run(*readmePath); err != nil {
+		fmt.Fprintf(os.Stderr, "controlsdoc: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run replaces the generated controls section of the file at readmePath.
+func run(readmePath string) error {
+	data, err := os.ReadFile(readmePath)
+	if err != nil {
+		return err
+	}
+
+	readme := string(data)
+
+	start := strings.Index(readme, startMarker)
+	end := strings.Index(readme, endMarker)
+	if start < 0 || end < start {
+		return fmt.Errorf("%s: missing %s and %s markers", readmePath, startMarker, endMarker)
+	}
+
+	controls := game.DefaultKeymap().ControlsMarkdown()
+	updated := readme[:start+len(startMarker)] + "\n\n" + controls + "\n" + readme[end:]
+
+	return os.WriteFile(readmePath, []byte(updated), 0o644)
+}
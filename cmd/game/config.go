@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/charmbracelet/log"
+	"github.com/theantichris/sprawlrunner/internal/game"
+)
+
+// errInvalidConfig is returned when a flag or config file value is out of
+// range.
+var errInvalidConfig = errors.New("invalid configuration")
+
+// renderer modes accepted by --renderer.
+const (
+	rendererGlyphs = "glyphs"
+	rendererTiles  = "tiles"
+)
+
+// Config holds the command line options. Each option can also be set in the
+// JSON config file; flags given on the command line win over the file.
+type Config struct {
+	Seed             int64   `json:"seed"`              // Seed seeds the random number generator; 0 picks one at random.
+	Font             string  `json:"font"`              // Font is a TrueType file to use instead of the embedded font.
+	FontSize         float64 `json:"font_size"`         // FontSize overrides the font size setting when not 0.
+	Width            int     `json:"width"`             // Width is the initial window width in pixels.
+	Height           int     `json:"height"`            // Height is the initial window height in pixels.
+	Fullscreen       bool    `json:"fullscreen"`        // Fullscreen starts the game in fullscreen.
+	LogLevel         string  `json:"log_level"`         // LogLevel is the minimum level logged.
+	LogFile          string  `json:"log_file"`          // LogFile receives logs instead of standard error.
+	Renderer         string  `json:"renderer"`          // Renderer is "glyphs" or "tiles"; empty uses the settings file.
+	Telemetry        string  `json:"telemetry"`         // Telemetry receives game events as JSON lines when set.
+	Wizard           bool    `json:"wizard"`            // Wizard enables the debug console; the run is flagged in the scores.
+	SnapshotInterval int     `json:"snapshot_interval"` // SnapshotInterval is the turns between turn history snapshots in wizard mode.
+	Record           string  `json:"record"`            // Record is where the session's replay is written; empty uses the config directory.
+	ReplaySpeed      float64 `json:"replay_speed"`      // ReplaySpeed is the playback speed of --replay in steps per second.
+	Generator        string  `json:"generator"`         // Generator names the level generator; empty uses rooms.
+
+	ConfigPath   string `json:"-"` // ConfigPath is the config file that was loaded, if any.
+	Version      bool   `json:"-"` // Version prints build information and exits.
+	Replay       string `json:"-"` // Replay plays back a replay file instead of taking input.
+	VerifyReplay string `json:"-"` // VerifyReplay checks a replay file without opening a window and exits.
+}
+
+// DefaultConfig returns the options used when neither flags nor a config
+// file set them.
+func DefaultConfig() Config {
+	return Config{
+		Width:            initWidth,
+		Height:           initHeight,
+		LogLevel:         "info",
+		ReplaySpeed:      game.DefaultReplaySpeed,
+		SnapshotInterval: game.DefaultSnapshotInterval,
+	}
+}
+
+// ParseConfig reads options from args, which excludes the program name.
+// When --config is given, or defaultPath names an existing file, options are
+// first loaded from that JSON file and then overridden by the flags. Returns
+// flag.ErrHelp when help was requested.
+func ParseConfig(args []string, defaultPath string, output io.Writer) (Config, error) {
+	config := DefaultConfig()
+
+	flags := newFlagSet(&config, output)
+	if err := flags.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	path, required := config.ConfigPath, true
+	if path == "" {
+		path, required = defaultPath, false
+	}
+
+	if path != "" {
+		// Parse again on top of the file so flags win
+		fileConfig, err := LoadConfigFile(path, required)
+		if err != nil {
+			return Config{}, err
+		}
+
+		config = fileConfig
+		if err := newFlagSet(&config, io.Discard).Parse(args); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return config, nil
+}
+
+// newFlagSet defines the command line flags, storing their values in config.
+func newFlagSet(config *Config, output io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(windowTitle, flag.ContinueOnError)
+	flags.SetOutput(output)
+
+	flags.Int64Var(&config.Seed, "seed", config.Seed, "random `seed`; 0 picks one at random")
+	flags.StringVar(&config.Font, "font", config.Font, "TrueType font `file` to use instead of the embedded font")
+	flags.Float64Var(&config.FontSize, "font-size", config.FontSize, "font size in `points`; 0 uses settings.json")
+	flags.IntVar(&config.Width, "width", config.Width, "initial window width in `pixels`")
+	flags.IntVar(&config.Height, "height", config.Height, "initial window height in `pixels`")
+	flags.BoolVar(&config.Fullscreen, "fullscreen", config.Fullscreen, "start in fullscreen")
+	flags.StringVar(&config.LogLevel, "log-level", config.LogLevel, "minimum log `level`: debug, info, warn, or error")
+	flags.StringVar(&config.LogFile, "log-file", config.LogFile, "write logs to `file` instead of standard error")
+	flags.StringVar(&config.ConfigPath, "config", config.ConfigPath, "JSON config `file` providing defaults for these flags")
+	flags.StringVar(&config.Renderer, "renderer", config.Renderer, "draw with `mode` glyphs or tiles; empty uses settings.json")
+	flags.StringVar(&config.Telemetry, "telemetry", config.Telemetry, "append game events as JSON lines to `file`")
+	flags.BoolVar(&config.Wizard, "wizard", config.Wizard, "enable wizard mode with the debug console; runs are flagged in the scores")
+	flags.IntVar(&config.SnapshotInterval, "snapshot-interval", config.SnapshotInterval, "take a turn history snapshot every `turns` turns in wizard mode")
+	flags.StringVar(&config.Record, "record", config.Record, "write the session's replay to `file` instead of the replays folder")
+	flags.StringVar(&config.Replay, "replay", config.Replay, "play back the replay `file` instead of playing")
+	flags.Float64Var(&config.ReplaySpeed, "replay-speed", config.ReplaySpeed, "replay playback speed in `steps` per second")
+	flags.StringVar(&config.VerifyReplay, "verify-replay", config.VerifyReplay, "check that the replay `file` ends in its recorded state, then exit")
+	flags.StringVar(&config.Generator, "generator", config.Generator, "lay out levels with `name` rooms, caves, or office")
+	flags.BoolVar(&config.Version, "version", config.Version, "print version information and exit")
+
+	return flags
+}
+
+// LoadConfigFile reads options from the JSON file at path on top of the
+// defaults. A missing file is only an error when required is true.
+func LoadConfigFile(path string, required bool) (Config, error) {
+	config := DefaultConfig()
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		return config, nil
+	}
+
+	if err != nil {
+		return Config{}, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&config); err != nil {
+		return Config{}, fmt.Errorf("%w: %s: %v", errInvalidConfig, path, err)
+	}
+
+	config.ConfigPath = path
+
+	return config, nil
+}
+
+// Validate checks every option and explains each one that is wrong.
+func (config Config) Validate() error {
+	var problems []error
+
+	if config.FontSize < 0 {
+		problems = append(problems, fmt.Errorf("%w: font size must not be negative, got %g", errInvalidConfig, config.FontSize))
+	}
+
+	if config.Width < 320 {
+		problems = append(problems, fmt.Errorf("%w: width must be at least 320 pixels, got %d", errInvalidConfig, config.Width))
+	}
+
+	if config.Height < 240 {
+		problems = append(problems, fmt.Errorf("%w: height must be at least 240 pixels, got %d", errInvalidConfig, config.Height))
+	}
+
+	if _, err := log.ParseLevel(config.LogLevel); err != nil {
+		problems = append(problems, fmt.Errorf("%w: log level must be debug, info, warn, or error, got %q", errInvalidConfig, config.LogLevel))
+	}
+
+	if config.SnapshotInterval < 1 {
+		problems = append(problems, fmt.Errorf("%w: snapshot interval must be at least 1 turn, got %d", errInvalidConfig, config.SnapshotInterval))
+	}
+
+	if config.ReplaySpeed <= 0 {
+		problems = append(problems, fmt.Errorf("%w: replay speed must be positive, got %g", errInvalidConfig, config.ReplaySpeed))
+	}
+
+	if config.Replay != "" && config.VerifyReplay != "" {
+		problems = append(problems, fmt.Errorf("%w: --replay and --verify-replay cannot be used together", errInvalidConfig))
+	}
+
+	if _, err := game.ParseGenerator(config.Generator); err != nil {
+		problems = append(problems, fmt.Errorf("%w: %v", errInvalidConfig, err))
+	}
+
+	switch config.Renderer {
+	case "", rendererGlyphs, rendererTiles:
+	default:
+		problems = append(problems, fmt.Errorf("%w: renderer must be %s or %s, got %q", errInvalidConfig, rendererGlyphs, rendererTiles, config.Renderer))
+	}
+
+	return errors.Join(problems...)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	t.Run("defaults without flags or file", func(t *testing.T) {
+		config, err := ParseConfig(nil, "", io.Discard)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if config != DefaultConfig() {
+			t.Errorf("want default config, got %+v", config)
+		}
+	})
+
+	t.Run("flags override defaults", func(t *testing.T) {
+		config, err := ParseConfig([]string{"--seed", "42", "--width", "800", "--renderer", "tiles", "--fullscreen", "--wizard"}, "", io.Discard)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if config.Seed != 42 || config.Width != 800 || config.Renderer != rendererTiles || !config.Fullscreen || !config.Wizard {
+			t.Errorf("want flags applied, got %+v", config)
+		}
+	})
+
+	t.Run("flags override config file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		writeFile(t, path, `{"width": 1024, "height": 768, "log_level": "debug"}`)
+
+		config, err := ParseConfig([]string{"--config", path, "--width", "640"}, "", io.Discard)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if config.Width != 640 {
+			t.Errorf("want width 640 from flag, got %d", config.Width)
+		}
+
+		if config.Height != 768 || config.LogLevel != "debug" {
+			t.Errorf("want height and log level from file, got %+v", config)
+		}
+	})
+
+	t.Run("missing default file is ignored", func(t *testing.T) {
+		_, err := ParseConfig(nil, filepath.Join(t.TempDir(), "config.json"), io.Discard)
+		if err != nil {
+			t.Errorf("want no error, got %v", err)
+		}
+	})
+
+	t.Run("missing explicit file is an error", func(t *testing.T) {
+		_, err := ParseConfig([]string{"--config", filepath.Join(t.TempDir(), "config.json")}, "", io.Discard)
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("want %v, got %v", os.ErrNotExist, err)
+		}
+	})
+
+	t.Run("help", func(t *testing.T) {
+		_, err := ParseConfig([]string{"--help"}, "", io.Discard)
+		if !errors.Is(err, flag.ErrHelp) {
+			t.Errorf("want %v, got %v", flag.ErrHelp, err)
+		}
+	})
+
+	tests := []struct {
+		name string
+		args []string
+		file string
+	}{
+		{name: "small window", args: []string{"--width", "100"}},
+		{name: "negative font size", args: []string{"--font-size", "-4"}},
+		{name: "unknown log level", args: []string{"--log-level", "loud"}},
+		{name: "unknown renderer", args: []string{"--renderer", "vulkan"}},
+		{name: "unknown generator", args: []string{"--generator", "maze"}},
+		{name: "zero replay speed", args: []string{"--replay-speed", "0"}},
+		{name: "zero snapshot interval", args: []string{"--snapshot-interval", "0"}},
+		{name: "replay and verify", args: []string{"--replay", "a.json", "--verify-replay", "a.json"}},
+		{name: "unknown file field", file: `{"colour": "red"}`},
+		{name: "malformed file", file: `{"width": "wide"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := tt.args
+			if tt.file != "" {
+				path := filepath.Join(t.TempDir(), "config.json")
+				writeFile(t, path, tt.file)
+				args = append(args, "--config", path)
+			}
+
+			_, err := ParseConfig(args, "", io.Discard)
+			if !errors.Is(err, errInvalidConfig) {
+				t.Errorf("want %v, got %v", errInvalidConfig, err)
+			}
+		})
+	}
+}
+
+// writeFile writes contents to path, failing the test on error.
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"io/fs"
+	"path/filepath"
+
+	"github.com/theantichris/sprawlrunner/internal/game"
+)
+
+const (
+	contentDir = "content"
+	modsDir    = "mods"
+)
+
+// loadContent reads the content definitions from assetFS, then the mods in
+// configDir, which add to or override them. Without a config directory no
+// mods are loaded.
+func loadContent(assetFS fs.FS, configDir string) (*game.Content, error) {
+	modsPath := ""
+	if configDir != "" {
+		modsPath = filepath.Join(configDir, windowTitle, modsDir)
+	}
+
+	return game.LoadContent(assetFS, contentDir, modsPath)
+}
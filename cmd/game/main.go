@@ -1,48 +1,318 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/theantichris/sprawlrunner/assets"
 	"github.com/theantichris/sprawlrunner/internal/game"
 )
 
 const (
 	initWidth    = 1280
 	initHeight   = 800
-	fontFacePath = "assets/fonts/Go-Mono.ttf"
-	fontSize     = 16
+	fontFacePath = "fonts/Go-Mono.ttf"
 	windowTitle  = "sprawlrunner"
+	keymapFile   = "keymap.json"
+	settingsFile = "settings.json"
+	configFile   = "config.json"
+	morgueDir    = "morgue"
+	scoresFile   = "scores.json"
+	saveFile     = "save.json"
+	dumpDir      = "dumps"
+	replaysDir   = "replays"
+	keepReplays  = 20
 )
 
-// main is the entry point for the Sprawlrunner game binary. It initializes
-// the logger and runs the game, exiting with an error if something goes wrong.
+// version is set at build time by goreleaser with -X main.version.
+var version = "dev"
+
+// main is the entry point for the Sprawlrunner game binary. It parses the
+// command line, initializes the logger and runs the game, exiting with an
+// error if something goes wrong.
 func main() {
-	logger := log.NewWithOptions(os.Stderr, log.Options{
+	// Without a config directory only flags and defaults are used
+	configDir, _ := os.UserConfigDir()
+
+	defaultConfigPath := ""
+	if configDir != "" {
+		defaultConfigPath = filepath.Join(configDir, windowTitle, configFile)
+	}
+
+	config, err := ParseConfig(os.Args[1:], defaultConfigPath, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", windowTitle, err)
+		os.Exit(2)
+	}
+
+	if config.Version {
+		fmt.Println(versionString())
+		return
+	}
+
+	if config.VerifyReplay != "" {
+		if err := verifyReplay(config.VerifyReplay, configDir, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", windowTitle, err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	logger, closeLog, err := newLogger(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", windowTitle, err)
+		os.Exit(1)
+	}
+
+	err = run(config, configDir, logger)
+	if err != nil {
+		logger.Errorf("sprawl runner exited with error: %v", err)
+
+		if config.LogFile != "" {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", windowTitle, err)
+		}
+	}
+
+	closeLog()
+
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// newLogger creates the JSON logger described by config. The returned
+// function closes the log file, if one was opened.
+func newLogger(config Config) (*log.Logger, func(), error) {
+	level, err := log.ParseLevel(config.LogLevel)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var output io.Writer = os.Stderr
+	closeLog := func() {}
+
+	if config.LogFile != "" {
+		file, err := os.OpenFile(config.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		output = file
+		closeLog = func() {
+			_ = file.Close()
+		}
+	}
+
+	logger := log.NewWithOptions(output, log.Options{
 		Formatter:       log.JSONFormatter,
 		ReportCaller:    true,
 		ReportTimestamp: true,
-		Level:           log.DebugLevel,
+		Level:           level,
 	})
 
-	if err := run(); err != nil {
-		logger.Fatalf("sprawl runner exited with error: %v", err)
+	return logger, closeLog, nil
+}
+
+// versionString describes the build: the version injected by goreleaser,
+// the commit when known, the Go version, and the platform.
+func versionString() string {
+	revision := ""
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" && len(setting.Value) >= 7 {
+				revision = " " + setting.Value[:7]
+			}
+		}
 	}
+
+	return fmt.Sprintf("%s %s%s (%s %s/%s)", windowTitle, version, revision, runtime.Version(), runtime.GOOS, runtime.GOARCH)
 }
 
-// run initializes the terminal screen, creates a new game, and enters the
-// main event loop. It returns an error if initialization or game execution fails.
-func run() error {
+// run creates a new game from config, loads the user's key bindings and
+// settings from configDir, and enters the main event loop. It returns an
+// error if initialization or game execution fails.
+func run(config Config, configDir string, logger *log.Logger) error {
 	g := game.NewGame()
-	renderer, err := game.NewEbitenRenderer(g, fontFacePath, fontSize)
+
+	if config.Seed != 0 {
+		g.SetSeed(config.Seed)
+	}
+
+	// Key bindings and settings are optional; without a config directory the
+	// defaults are used
+	if configDir != "" {
+		keymapPath := filepath.Join(configDir, windowTitle, keymapFile)
+
+		keymap, err := game.LoadKeymapFile(keymapPath)
+		if err != nil {
+			return err
+		}
+
+		g.Keymap = keymap
+		g.KeymapPath = keymapPath
+
+		settingsPath := filepath.Join(configDir, windowTitle, settingsFile)
+
+		settings, err := game.LoadSettingsFile(settingsPath)
+		if err != nil {
+			return err
+		}
+
+		g.Settings = settings
+		g.SettingsPath = settingsPath
+		g.SavePath = filepath.Join(configDir, windowTitle, saveFile)
+		g.MorgueDir = filepath.Join(configDir, windowTitle, morgueDir)
+		g.DumpDir = filepath.Join(configDir, windowTitle, dumpDir)
+
+		scoresPath := filepath.Join(configDir, windowTitle, scoresFile)
+
+		scores, err := game.LoadScoreTable(scoresPath)
+		if err != nil {
+			return err
+		}
+
+		g.Scores = scores
+		g.ScoresPath = scoresPath
+	}
+
+	g.Wizard = config.Wizard
+	g.Generator = game.Generator(config.Generator)
+
+	if config.Wizard {
+		g.EnableHistory(config.SnapshotInterval)
+	}
+
+	// Command line options win over settings.json
+	if config.FontSize != 0 {
+		g.Settings.FontSize = config.FontSize
+	}
+
+	if config.Renderer != "" {
+		g.Settings.GraphicalTiles = config.Renderer == rendererTiles
+	}
+
+	if err := g.Settings.Validate(); err != nil {
+		return err
+	}
+
+	// Assets are embedded; files in the optional override directory win
+	assetFS := assets.FS(g.Settings.AssetsDir)
+
+	content, err := loadContent(assetFS, configDir)
+	if err != nil {
+		return err
+	}
+
+	g.SetContent(content)
+
+	// A replay plays on a game of its own, keeping the keymap and settings;
+	// otherwise the session is recorded so it can be replayed
+	var player *game.ReplayPlayer
+
+	if config.Replay != "" {
+		replay, err := game.LoadReplay(config.Replay)
+		if err != nil {
+			return err
+		}
+
+		if replay.Version != version {
+			logger.Warn("replay was recorded by another version", "replay", replay.Version, "version", version)
+		}
+
+		player = game.NewReplayPlayer(replay, config.ReplaySpeed)
+		player.Game.Settings = g.Settings
+		player.Game.SetContent(content)
+		g = player.Game
+	} else if recordPath := recordingPath(config, configDir, g.Seed, time.Now()); recordPath != "" {
+		if err := g.StartRecording(version); err != nil {
+			return err
+		}
+
+		defer func() {
+			if err := g.FinishRecording(recordPath); err != nil {
+				logger.Error("writing the replay failed", "err", err)
+				return
+			}
+
+			if config.Record == "" {
+				if err := pruneReplays(filepath.Dir(recordPath), keepReplays); err != nil {
+					logger.Error("removing old replays failed", "err", err)
+				}
+			}
+		}()
+	}
+
+	logger.Info("starting", "version", version, "seed", g.Seed, "config", config.ConfigPath)
+
+	g.Events.Subscribe(func(event game.Event) {
+		logger.Debug("event", "kind", event.Kind, "turn", event.Turn, "x", event.X, "y", event.Y)
+	})
+
+	if config.Telemetry != "" {
+		file, err := os.OpenFile(config.Telemetry, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			_ = file.Close()
+		}()
+
+		telemetry := game.NewTelemetry(file)
+		g.Events.Subscribe(telemetry.Record)
+
+		defer func() {
+			if err := telemetry.Err(); err != nil {
+				logger.Error("telemetry write failed", "err", err)
+			}
+		}()
+	}
+
+	// Screen reader mode narrates each turn on standard output
+	g.Narrator = os.Stdout
+
+	var renderer *game.EbitenRenderer
+
+	if config.Font != "" {
+		renderer, err = game.NewEbitenRenderer(g, config.Font, g.Settings.FontSize)
+	} else {
+		renderer, err = game.NewEbitenRendererFromFS(g, assetFS, fontFacePath, g.Settings.FontSize)
+	}
+
 	if err != nil {
 		return err
 	}
 
-	ebiten.SetWindowSize(initWidth, initHeight)
+	tileset, err := game.LoadTileset(assetFS, g.Settings.Tileset)
+	if err != nil {
+		return err
+	}
+
+	renderer.SetTileset(tileset)
+
+	if player != nil {
+		renderer.SetReplay(player)
+	}
+
+	ebiten.SetWindowSize(config.Width, config.Height)
 	ebiten.SetWindowTitle(windowTitle)
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	ebiten.SetFullscreen(config.Fullscreen)
 
 	return ebiten.RunGame(renderer)
 }
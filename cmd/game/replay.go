@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/theantichris/sprawlrunner/assets"
+	"github.com/theantichris/sprawlrunner/internal/game"
+)
+
+const (
+	// replayPrefix starts the name of every replay recorded to the replays
+	// folder.
+	replayPrefix = "replay-"
+
+	// replayExt ends the name of every replay recorded to the replays folder.
+	replayExt = ".json"
+)
+
+// recordingPath returns where the session's replay is written: --record when
+// given, otherwise a file in the replays folder of the config directory named
+// for the time the session started and its seed, so earlier sessions are not
+// overwritten. Empty disables recording.
+func recordingPath(config Config, configDir string, seed int64, now time.Time) string {
+	if config.Record != "" {
+		return config.Record
+	}
+
+	if configDir == "" {
+		return ""
+	}
+
+	name := fmt.Sprintf("%s%s-%d%s", replayPrefix, now.Format("20060102-150405"), seed, replayExt)
+
+	return filepath.Join(configDir, windowTitle, replaysDir, name)
+}
+
+// pruneReplays removes the oldest recorded replays in dir until at most keep
+// are left. Only files named by recordingPath are counted, so replays saved
+// there by hand are kept.
+func pruneReplays(dir string, keep int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && strings.HasPrefix(name, replayPrefix) && strings.HasSuffix(name, replayExt) {
+			names = append(names, name)
+		}
+	}
+
+	// Names start with the time, so they sort oldest first
+	slices.Sort(names)
+
+	var errs []error
+	for _, name := range names[:max(len(names)-keep, 0)] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// verifyReplay plays the replay at path back without a window and reports to
+// output whether it ended in its recorded state. The replay is played with
+// the content a session started from configDir would load.
+func verifyReplay(path, configDir string, output io.Writer) error {
+	replay, err := game.LoadReplay(path)
+	if err != nil {
+		return err
+	}
+
+	settings := game.DefaultSettings()
+	if configDir != "" {
+		settings, err = game.LoadSettingsFile(filepath.Join(configDir, windowTitle, settingsFile))
+		if err != nil {
+			return err
+		}
+	}
+
+	content, err := loadContent(assets.FS(settings.AssetsDir), configDir)
+	if err != nil {
+		return err
+	}
+
+	if replay.Version != version {
+		fmt.Fprintf(output, "%s: recorded by version %s, this is %s\n", path, replay.Version, version)
+	}
+
+	if err := game.VerifyReplay(replay, content); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	fmt.Fprintf(output, "%s: %d steps, state matches\n", path, len(replay.Steps))
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/theantichris/sprawlrunner/assets"
+	"github.com/theantichris/sprawlrunner/internal/game"
+)
+
+func TestRecordingPath(t *testing.T) {
+	now := time.Date(2026, 10, 18, 19, 2, 14, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		record    string
+		configDir string
+		want      string
+	}{
+		{name: "flag wins", record: "session.json", configDir: "config", want: "session.json"},
+		{name: "config directory", configDir: "config", want: filepath.Join("config", windowTitle, replaysDir, "replay-20261018-190214-42.json")},
+		{name: "disabled", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recordingPath(Config{Record: tt.record}, tt.configDir, 42, now); got != tt.want {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPruneReplays(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2026, 10, 18, 19, 0, 0, 0, time.UTC)
+
+	var paths []string
+	for i := range 5 {
+		name := filepath.Base(recordingPath(Config{}, "config", int64(i), start.Add(time.Duration(i)*time.Minute)))
+		paths = append(paths, filepath.Join(dir, name))
+	}
+
+	paths = append(paths, filepath.Join(dir, "keep-me.json"))
+	for _, path := range paths {
+		if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	if err := pruneReplays(dir, 3); err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	for i, path := range paths {
+		_, err := os.Stat(path)
+		if removed := errors.Is(err, fs.ErrNotExist); removed != (i < 2) {
+			t.Errorf("%s: want removed %t, got %t", filepath.Base(path), i < 2, removed)
+		}
+	}
+}
+
+func TestVerifyReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "replay.json")
+
+	content, err := loadContent(assets.FS(""), "")
+	if err != nil {
+		t.Fatalf("failed to load content: %v", err)
+	}
+
+	g := game.NewGame()
+	g.SetContent(content)
+	if err := g.StartRecording(version); err != nil {
+		t.Fatalf("failed to start recording: %v", err)
+	}
+
+	g.HandleCommand(game.CommandStartGame)
+	g.HandleCommand(game.CommandMoveRight)
+
+	if err := g.FinishRecording(path); err != nil {
+		t.Fatalf("failed to write replay: %v", err)
+	}
+
+	if err := verifyReplay(path, "", io.Discard); err != nil {
+		t.Errorf("want replay verified, got %v", err)
+	}
+
+	replay, err := game.LoadReplay(path)
+	if err != nil {
+		t.Fatalf("failed to load replay: %v", err)
+	}
+
+	replay.Hash = "tampered"
+	if err := replay.SaveFile(path); err != nil {
+		t.Fatalf("failed to write replay: %v", err)
+	}
+
+	if err := verifyReplay(path, "", io.Discard); !errors.Is(err, game.ErrReplayMismatch) {
+		t.Errorf("want %v, got %v", game.ErrReplayMismatch, err)
+	}
+}
@@ -3,8 +3,10 @@ module github.com/theantichris/sprawlrunner
 go 1.25.4
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/charmbracelet/log v0.4.2
 	github.com/hajimehoshi/ebiten/v2 v2.9.5
+	github.com/yuin/gopher-lua v1.1.2
 )
 
 require (
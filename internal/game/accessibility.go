@@ -0,0 +1,139 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// fontSizeStep is how many points the font grows or shrinks per command.
+const fontSizeStep = 2
+
+// importantHint marks important messages when glyph hints are on, so they
+// do not rely on the warning color alone.
+const importantHint = "! "
+
+// EntityMarker is a shape drawn on a creature's tile when glyph hints are on,
+// so hostile and friendly creatures differ by more than color.
+type EntityMarker int
+
+const (
+	MarkerNone     EntityMarker = iota // MarkerNone draws nothing.
+	MarkerHostile                      // MarkerHostile brackets the tile on both sides.
+	MarkerFriendly                     // MarkerFriendly underlines the tile.
+)
+
+// AdjustFontSize changes the font size by delta points, staying within the
+// allowed range. The renderer picks up the new size on its next update.
+func (game *Game) AdjustFontSize(delta float64) {
+	size := min(max(game.Settings.FontSize+delta, minFontSize), maxFontSize)
+	if size == game.Settings.FontSize {
+		return
+	}
+
+	game.Settings.FontSize = size
+	game.AddMessage(fmt.Sprintf("Font size %g.", size))
+}
+
+// MessageText returns the text to show for message, prefixed with a marker
+// when it is important and glyph hints are on.
+func (game *Game) MessageText(message Message) string {
+	if message.Important && game.Settings.GlyphHints {
+		return importantHint + message.Text
+	}
+
+	return message.Text
+}
+
+// EntityName returns the name to describe entity by, followed by whether it
+// is hostile or friendly when glyph hints are on.
+func (game *Game) EntityName(entity Entity) string {
+	if !game.Settings.GlyphHints {
+		return entity.Name
+	}
+
+	switch {
+	case entity.Hostile():
+		return entity.Name + " (hostile)"
+	case entity.Friendly():
+		return entity.Name + " (friendly)"
+	default:
+		return entity.Name
+	}
+}
+
+// EntityMarker returns the marker to draw on entity's tile: brackets for a
+// hostile creature and an underline for a friendly one when glyph hints are
+// on, and none otherwise.
+func (game *Game) EntityMarker(entity Entity) EntityMarker {
+	if !game.Settings.GlyphHints {
+		return MarkerNone
+	}
+
+	switch {
+	case entity.Hostile():
+		return MarkerHostile
+	case entity.Friendly():
+		return MarkerFriendly
+	default:
+		return MarkerNone
+	}
+}
+
+// TurnSummary describes the current turn in plain text for screen readers:
+// the turn, the player's health and position, the directions that are open,
+// and the messages added during the last turn.
+func (game *Game) TurnSummary() string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "Turn %d. Health %d. You are at %d,%d.",
+		game.TurnCount, game.Player.Health, game.Player.X, game.Player.Y)
+
+	var open []string
+	for _, command := range Commands() {
+		dx, dy, ok := command.moveDelta()
+		if !ok {
+			continue
+		}
+
+		x, y := game.Player.X+dx, game.Player.Y+dy
+		if game.InBounds(x, y) && game.Tiles[y][x].Walkable {
+			open = append(open, command.Description())
+		}
+	}
+
+	if len(open) == 0 {
+		builder.WriteString(" No way out.")
+	} else {
+		fmt.Fprintf(&builder, " Open: %s.", strings.Join(open, ", "))
+	}
+
+	// Messages added since the previous summary carry the previous turn number
+	for _, message := range game.Messages {
+		if message.Turn != game.TurnCount-1 {
+			continue
+		}
+
+		if message.Important {
+			builder.WriteString(" Warning:")
+		}
+
+		builder.WriteString(" " + message.Text)
+	}
+
+	return builder.String()
+}
+
+// narrateTurn writes the turn summary to Narrator when screen reader mode is
+// on.
+func (game *Game) narrateTurn() {
+	game.narrate(game.TurnSummary())
+}
+
+// narrate writes text as one line to Narrator when screen reader mode is on.
+func (game *Game) narrate(text string) {
+	if !game.Settings.ScreenReader || game.Narrator == nil {
+		return
+	}
+
+	_, _ = fmt.Fprintln(game.Narrator, text)
+}
@@ -0,0 +1,196 @@
+package game
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestAdjustFontSize(t *testing.T) {
+	tests := []struct {
+		name  string
+		start float64
+		delta float64
+		want  float64
+	}{
+		{name: "grows", start: 16, delta: fontSizeStep, want: 18},
+		{name: "shrinks", start: 16, delta: -fontSizeStep, want: 14},
+		{name: "stops at maximum", start: maxFontSize - 1, delta: fontSizeStep, want: maxFontSize},
+		{name: "stops at minimum", start: minFontSize, delta: -fontSizeStep, want: minFontSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := NewGame()
+			game.Settings.FontSize = tt.start
+
+			game.AdjustFontSize(tt.delta)
+
+			if game.Settings.FontSize != tt.want {
+				t.Errorf("want font size %g, got %g", tt.want, game.Settings.FontSize)
+			}
+		})
+	}
+}
+
+func TestHandleCommandFontSize(t *testing.T) {
+	game := NewGame()
+	game.StartGame()
+
+	game.HandleCommand(CommandFontLarger)
+
+	if game.Settings.FontSize != DefaultSettings().FontSize+fontSizeStep {
+		t.Errorf("want font size %g, got %g", DefaultSettings().FontSize+fontSizeStep, game.Settings.FontSize)
+	}
+}
+
+func TestMessageText(t *testing.T) {
+	tests := []struct {
+		name       string
+		glyphHints bool
+		message    Message
+		want       string
+	}{
+		{name: "important with hints", glyphHints: true, message: Message{Text: "Alarm!", Important: true}, want: "! Alarm!"},
+		{name: "important without hints", message: Message{Text: "Alarm!", Important: true}, want: "Alarm!"},
+		{name: "ordinary with hints", glyphHints: true, message: Message{Text: "Hello."}, want: "Hello."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := NewGame()
+			game.Settings.GlyphHints = tt.glyphHints
+
+			if got := game.MessageText(tt.message); got != tt.want {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTurnSummary(t *testing.T) {
+	game := NewGame()
+	game.AddImportantMessage("Alarm!")
+	game.Tick()
+
+	summary := game.TurnSummary()
+
+	for _, want := range []string{"Turn 1.", "Health 100.", "at 17,9.", "Open: up, down", "Warning: Alarm!"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("want summary containing %q, got %q", want, summary)
+		}
+	}
+
+	game.Tick()
+
+	if strings.Contains(game.TurnSummary(), "Alarm!") {
+		t.Errorf("want old messages left out, got %q", game.TurnSummary())
+	}
+}
+
+func TestNarration(t *testing.T) {
+	tests := []struct {
+		name         string
+		screenReader bool
+		want         int
+	}{
+		{name: "screen reader on", screenReader: true, want: 1},
+		{name: "screen reader off", screenReader: false, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+
+			game := NewGame()
+			game.Narrator = &output
+			game.Settings.ScreenReader = tt.screenReader
+
+			game.MovePlayer(1, 0)
+
+			if got := strings.Count(output.String(), "\n"); got != tt.want {
+				t.Errorf("want %d summaries, got %d: %q", tt.want, got, output.String())
+			}
+		})
+	}
+}
+
+func TestNarrateDeath(t *testing.T) {
+	var output bytes.Buffer
+
+	game := NewGame()
+	game.Narrator = &output
+	game.Settings.ScreenReader = true
+	game.State = StatePlaying
+
+	game.DamagePlayer(game.Player.Health, "a stray bullet")
+	game.Tick()
+
+	want := fmt.Sprintf("You die. Killed by a stray bullet on turn %d.", game.TurnCount)
+	if !strings.Contains(output.String(), want) {
+		t.Errorf("want narration containing %q, got %q", want, output.String())
+	}
+}
+
+func TestEntityName(t *testing.T) {
+	tests := []struct {
+		name       string
+		entity     Entity
+		glyphHints bool
+		want       string
+	}{
+		{name: "hostile", entity: Entity{Kind: EntityMonster, Name: "ganger", Color: RoleHostile}, glyphHints: true, want: "ganger (hostile)"},
+		{name: "friendly", entity: Entity{Kind: EntityMonster, Name: "fixer", Color: RoleFriendly, Disposition: DispositionFriendly}, glyphHints: true, want: "fixer (friendly)"},
+		{name: "item", entity: Entity{Kind: EntityItem, Name: "medkit", Color: RoleFriendly}, glyphHints: true, want: "medkit"},
+		{name: "disposition not color", entity: Entity{Kind: EntityMonster, Name: "fixer", Color: RoleHostile, Disposition: DispositionFriendly}, glyphHints: true, want: "fixer (friendly)"},
+		{name: "hints off", entity: Entity{Kind: EntityMonster, Name: "ganger", Color: RoleHostile}, want: "ganger"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := NewGame()
+			game.Settings.GlyphHints = tt.glyphHints
+
+			if got := game.EntityName(tt.entity); got != tt.want {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("examine", func(t *testing.T) {
+		game := NewGame()
+		game.Settings.GlyphHints = true
+		game.SpawnEntity(Entity{Kind: EntityMonster, Name: "ganger", Glyph: 'g', Color: RoleHostile, X: 13, Y: 9})
+
+		if got := game.Describe(13, 9); got != "You see a ganger (hostile)." {
+			t.Errorf("want the ganger described as hostile, got %q", got)
+		}
+	})
+}
+
+func TestEntityMarker(t *testing.T) {
+	tests := []struct {
+		name       string
+		entity     Entity
+		glyphHints bool
+		want       EntityMarker
+	}{
+		{name: "hostile", entity: Entity{Kind: EntityMonster, Name: "ganger", Color: RoleHostile}, glyphHints: true, want: MarkerHostile},
+		{name: "friendly", entity: Entity{Kind: EntityMonster, Name: "fixer", Color: RoleFriendly, Disposition: DispositionFriendly}, glyphHints: true, want: MarkerFriendly},
+		{name: "item", entity: Entity{Kind: EntityItem, Name: "medkit", Color: RoleFriendly}, glyphHints: true, want: MarkerNone},
+		{name: "disposition not color", entity: Entity{Kind: EntityMonster, Name: "ganger", Color: RoleFriendly}, glyphHints: true, want: MarkerHostile},
+		{name: "hints off", entity: Entity{Kind: EntityMonster, Name: "ganger", Color: RoleHostile}, want: MarkerNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := NewGame()
+			game.Settings.GlyphHints = tt.glyphHints
+
+			if got := game.EntityMarker(tt.entity); got != tt.want {
+				t.Errorf("want marker %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic writes data to path so that readers see either the old
+// file or the complete new one, never a partial write. The data goes to a
+// temporary file in the same directory, which is synced and then renamed
+// over path.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	// Remove the temporary file if anything fails before the rename
+	defer func() {
+		_ = os.Remove(file.Name())
+	}()
+
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(file.Name(), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
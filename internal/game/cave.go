@@ -0,0 +1,200 @@
+package game
+
+const (
+	// caveFillChance is the chance each cell starts as wall before the
+	// automaton smooths the noise into caves.
+	caveFillChance = 0.45
+
+	// caveSmoothPasses is how many times the automaton rule is applied.
+	caveSmoothPasses = 5
+
+	// caveMinPocket is the smallest pocket joined to the main cave; smaller
+	// pockets are filled in.
+	caveMinPocket = 8
+
+	// caveMinOpen is the share of the map the main cave must cover before
+	// it is accepted.
+	caveMinOpen = 0.3
+
+	// caveAttempts is how many times the automaton is rerun looking for a
+	// large enough cave before the largest one found is used.
+	caveAttempts = 10
+)
+
+// generateCaves grows sewers and collapsed tunnels with a cellular
+// automaton. The largest connected region is kept, pockets big enough to
+// matter are joined to it with corridors, and the rest are filled in. The
+// player starts on a random floor tile of the cave.
+func (game *Game) generateCaves(width, height int) {
+	var regions [][]Point
+	main := -1
+
+	for range caveAttempts {
+		open := caveNoise(game, width, height)
+		for range caveSmoothPasses {
+			open = smoothCave(open)
+		}
+
+		attempt := caveRegions(open)
+		largest := largestRegion(attempt)
+
+		if largest >= 0 && (main < 0 || len(attempt[largest]) > len(regions[main])) {
+			regions, main = attempt, largest
+		}
+
+		if main >= 0 && float64(len(regions[main])) >= caveMinOpen*float64(width*height) {
+			break
+		}
+	}
+
+	// A map too small to hold a cave falls back to rooms
+	if main < 0 {
+		game.generateRooms(width, height)
+		return
+	}
+
+	cave := regions[main]
+
+	for i, region := range regions {
+		if i != main && len(region) < caveMinPocket {
+			continue
+		}
+
+		for _, p := range region {
+			game.Tiles[p.Y][p.X] = game.Content.Tile(TileFloor)
+		}
+
+		if i == main {
+			continue
+		}
+
+		from, to := closestPoints(region, cave)
+		game.CreateCorridor(from.X, from.Y, to.X, to.Y)
+		cave = append(cave, region...)
+	}
+
+	start := cave[game.rng.IntN(len(cave))]
+	game.Player.X = start.X
+	game.Player.Y = start.Y
+}
+
+// caveNoise returns a grid of random open cells, indexed [y][x], with a
+// solid border.
+func caveNoise(game *Game, width, height int) [][]bool {
+	open := make([][]bool, height)
+
+	for y := range height {
+		open[y] = make([]bool, width)
+
+		for x := 1; x < width-1; x++ {
+			open[y][x] = y > 0 && y < height-1 && game.rng.Float64() >= caveFillChance
+		}
+	}
+
+	return open
+}
+
+// smoothCave applies one step of the automaton: a cell becomes wall when
+// more than four of its eight neighbors are walls and open when fewer than
+// four are. Cells off the grid count as walls, so the border stays solid.
+func smoothCave(open [][]bool) [][]bool {
+	smoothed := make([][]bool, len(open))
+
+	for y, row := range open {
+		smoothed[y] = make([]bool, len(row))
+
+		for x := range row {
+			walls := 0
+
+			for _, offset := range neighborOffsets {
+				nx, ny := x+offset.X, y+offset.Y
+				if ny < 0 || ny >= len(open) || nx < 0 || nx >= len(row) || !open[ny][nx] {
+					walls++
+				}
+			}
+
+			onBorder := y == 0 || y == len(open)-1 || x == 0 || x == len(row)-1
+
+			switch {
+			case onBorder || walls > 4:
+				smoothed[y][x] = false
+			case walls < 4:
+				smoothed[y][x] = true
+			default:
+				smoothed[y][x] = open[y][x]
+			}
+		}
+	}
+
+	return smoothed
+}
+
+// caveRegions flood fills the open cells into regions connected the way
+// the player moves, in the order they are first met scanning row by row.
+func caveRegions(open [][]bool) [][]Point {
+	seen := make([][]bool, len(open))
+	for y, row := range open {
+		seen[y] = make([]bool, len(row))
+	}
+
+	var regions [][]Point
+
+	for y, row := range open {
+		for x := range row {
+			if !open[y][x] || seen[y][x] {
+				continue
+			}
+
+			seen[y][x] = true
+			region := []Point{{X: x, Y: y}}
+
+			for i := 0; i < len(region); i++ {
+				for _, offset := range neighborOffsets {
+					nx, ny := region[i].X+offset.X, region[i].Y+offset.Y
+					if ny < 0 || ny >= len(open) || nx < 0 || nx >= len(row) || !open[ny][nx] || seen[ny][nx] {
+						continue
+					}
+
+					seen[ny][nx] = true
+					region = append(region, Point{X: nx, Y: ny})
+				}
+			}
+
+			regions = append(regions, region)
+		}
+	}
+
+	return regions
+}
+
+// largestRegion returns the index of the biggest region, or -1 if there
+// are none. Ties go to the first.
+func largestRegion(regions [][]Point) int {
+	largest := -1
+
+	for i, region := range regions {
+		if largest < 0 || len(region) > len(regions[largest]) {
+			largest = i
+		}
+	}
+
+	return largest
+}
+
+// closestPoints returns the cell of from and the cell of to nearest each
+// other, measured in steps along a corridor.
+func closestPoints(from, to []Point) (Point, Point) {
+	bestFrom, bestTo := from[0], to[0]
+	best := -1
+
+	for _, a := range from {
+		for _, b := range to {
+			distance := max(a.X-b.X, b.X-a.X) + max(a.Y-b.Y, b.Y-a.Y)
+			if best < 0 || distance < best {
+				bestFrom, bestTo, best = a, b, distance
+			}
+		}
+	}
+
+	return bestFrom, bestTo
+}
@@ -0,0 +1,174 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+// caveGame returns a game whose level was laid out by the cave generator
+// from seed.
+func caveGame(seed int64) *Game {
+	game := NewGame()
+	game.SetSeed(seed)
+	game.Generator = GeneratorCaves
+	game.SetContent(game.Content)
+
+	return game
+}
+
+// reachable counts the walkable tiles reachable from (x, y).
+func reachable(game *Game, x, y int) int {
+	seen := map[Point]bool{{X: x, Y: y}: true}
+	queue := []Point{{X: x, Y: y}}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, offset := range neighborOffsets {
+			next := Point{X: current.X + offset.X, Y: current.Y + offset.Y}
+			if seen[next] || !game.InBounds(next.X, next.Y) || !game.Tiles[next.Y][next.X].Walkable {
+				continue
+			}
+
+			seen[next] = true
+			queue = append(queue, next)
+		}
+	}
+
+	return len(seen)
+}
+
+func TestGenerateCaves(t *testing.T) {
+	for seed := range int64(20) {
+		game := caveGame(seed)
+
+		if !game.Tiles[game.Player.Y][game.Player.X].Walkable {
+			t.Fatalf("seed %d: want the player on a floor tile", seed)
+		}
+
+		floors := 0
+		for y, row := range game.Tiles {
+			for x, tile := range row {
+				if !tile.Walkable {
+					continue
+				}
+
+				floors++
+
+				if x == 0 || y == 0 || x == game.Width-1 || y == game.Height-1 {
+					t.Fatalf("seed %d: want a solid border, got floor at %d,%d", seed, x, y)
+				}
+			}
+		}
+
+		if got := reachable(game, game.Player.X, game.Player.Y); got != floors {
+			t.Errorf("seed %d: want all %d floor tiles reachable, got %d", seed, floors, got)
+		}
+
+		if float64(floors) < caveMinOpen*float64(game.Width*game.Height) {
+			t.Errorf("seed %d: want at least %.0f%% of the map open, got %d tiles", seed, caveMinOpen*100, floors)
+		}
+	}
+
+	t.Run("same seed, same caves", func(t *testing.T) {
+		first, second := caveGame(9), caveGame(9)
+
+		for y := range first.Tiles {
+			for x := range first.Tiles[y] {
+				if first.Tiles[y][x] != second.Tiles[y][x] {
+					t.Fatalf("want the same level, got different tiles at %d,%d", x, y)
+				}
+			}
+		}
+	})
+}
+
+func TestSmoothCave(t *testing.T) {
+	// A lone wall in open space opens up, and a lone floor in rock closes
+	open := [][]bool{
+		{false, false, false, false, false, false, false},
+		{false, true, true, true, false, false, false},
+		{false, true, false, true, false, false, false},
+		{false, true, true, true, false, true, false},
+		{false, false, false, false, false, false, false},
+	}
+
+	smoothed := smoothCave(open)
+
+	if !smoothed[2][2] {
+		t.Error("want the lone wall opened")
+	}
+
+	if smoothed[3][5] {
+		t.Error("want the lone floor closed")
+	}
+
+	for x := range smoothed[0] {
+		if smoothed[0][x] || smoothed[4][x] {
+			t.Fatal("want the border kept solid")
+		}
+	}
+}
+
+func TestCaveRegions(t *testing.T) {
+	open := [][]bool{
+		{true, true, false, false},
+		{false, false, true, false},
+		{false, false, false, false},
+		{true, false, false, true},
+	}
+
+	regions := caveRegions(open)
+
+	// The top three touch diagonally; the bottom corners stand alone
+	if len(regions) != 3 || len(regions[0]) != 3 {
+		t.Fatalf("want 3 regions, the first of 3 cells, got %v", regions)
+	}
+
+	if got := largestRegion(regions); got != 0 {
+		t.Errorf("want the first region largest, got %d", got)
+	}
+
+	if got := largestRegion(nil); got != -1 {
+		t.Errorf("want -1 without regions, got %d", got)
+	}
+}
+
+func TestParseGenerator(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Generator
+		wantErr error
+	}{
+		{name: "", want: GeneratorRooms},
+		{name: "caves", want: GeneratorCaves},
+		{name: "maze", wantErr: ErrUnknownGenerator},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGenerator(tt.name)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want %v, got %v", tt.wantErr, err)
+			}
+
+			if got != tt.want {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestReplayCaves(t *testing.T) {
+	game := caveGame(11)
+	replay := recordSession(t, game)
+
+	if replay.Generator != GeneratorCaves {
+		t.Fatalf("want the generator recorded, got %q", replay.Generator)
+	}
+
+	if err := VerifyReplay(replay, nil); err != nil {
+		t.Errorf("want replay on caves verified, got %v", err)
+	}
+}
@@ -1,10 +1,213 @@
 package game
 
-import "image/color"
+import (
+	"fmt"
+	"image/color"
+	"slices"
+)
+
+// ColorRole names what a color is used for, so rendering code asks for
+// "wall" or "warning" and the active palette decides the actual color.
+type ColorRole int
+
+const (
+	// RoleBackground is the screen background.
+	RoleBackground ColorRole = iota
+
+	// RoleText is ordinary interface text.
+	RoleText
+
+	// RoleTextDim is secondary text such as unselected menu entries.
+	RoleTextDim
+
+	// RoleAccent highlights headings, separators, and selections.
+	RoleAccent
+
+	// RoleWarning marks prompts and messages that need attention.
+	RoleWarning
+
+	// RoleWall is wall terrain.
+	RoleWall
 
-var (
-	colorBlack  = color.Black
-	colorGray   = color.Gray{Y: 192}
-	colorYellow = color.RGBA{R: 255, G: 255, B: 0, A: 255}
-	colorWhite  = color.White
+	// RoleFloor is floor terrain.
+	RoleFloor
+
+	// RolePlayer is the player character.
+	RolePlayer
+
+	// RoleHostile is creatures that attack the player.
+	RoleHostile
+
+	// RoleFriendly is creatures that help or ignore the player.
+	RoleFriendly
+
+	// roleCount is the number of roles; keep it last.
+	roleCount
 )
+
+// colorRoleNames maps the names used in content definitions to roles.
+var colorRoleNames = map[string]ColorRole{
+	"text":     RoleText,
+	"text_dim": RoleTextDim,
+	"accent":   RoleAccent,
+	"warning":  RoleWarning,
+	"wall":     RoleWall,
+	"floor":    RoleFloor,
+	"player":   RolePlayer,
+	"hostile":  RoleHostile,
+	"friendly": RoleFriendly,
+}
+
+// ParseColorRole returns the role with the given name, such as "hostile".
+func ParseColorRole(name string) (ColorRole, bool) {
+	role, ok := colorRoleNames[name]
+
+	return role, ok
+}
+
+// DefaultTheme is the palette used when no theme is configured.
+const DefaultTheme = "neon"
+
+// Palette assigns a color to every role.
+type Palette [roleCount]color.Color
+
+// palettes holds the built in palettes by theme name.
+var palettes = map[string]Palette{
+	// Neon cyberpunk: cyan and magenta on black
+	"neon": {
+		RoleBackground: color.RGBA{R: 5, G: 5, B: 15, A: 255},
+		RoleText:       color.RGBA{R: 225, G: 230, B: 255, A: 255},
+		RoleTextDim:    color.RGBA{R: 120, G: 125, B: 160, A: 255},
+		RoleAccent:     color.RGBA{R: 0, G: 255, B: 230, A: 255},
+		RoleWarning:    color.RGBA{R: 255, G: 60, B: 200, A: 255},
+		RoleWall:       color.RGBA{R: 140, G: 70, B: 210, A: 255},
+		RoleFloor:      color.RGBA{R: 70, G: 80, B: 115, A: 255},
+		RolePlayer:     color.RGBA{R: 0, G: 255, B: 230, A: 255},
+		RoleHostile:    color.RGBA{R: 255, G: 50, B: 80, A: 255},
+		RoleFriendly:   color.RGBA{R: 90, G: 255, B: 120, A: 255},
+	},
+
+	// Monochrome green phosphor: roles differ only by brightness
+	"phosphor": {
+		RoleBackground: color.RGBA{R: 0, G: 12, B: 0, A: 255},
+		RoleText:       color.RGBA{R: 60, G: 255, B: 60, A: 255},
+		RoleTextDim:    color.RGBA{R: 25, G: 130, B: 25, A: 255},
+		RoleAccent:     color.RGBA{R: 160, G: 255, B: 160, A: 255},
+		RoleWarning:    color.RGBA{R: 220, G: 255, B: 220, A: 255},
+		RoleWall:       color.RGBA{R: 40, G: 190, B: 40, A: 255},
+		RoleFloor:      color.RGBA{R: 20, G: 95, B: 20, A: 255},
+		RolePlayer:     color.RGBA{R: 200, G: 255, B: 200, A: 255},
+		RoleHostile:    color.RGBA{R: 235, G: 255, B: 235, A: 255},
+		RoleFriendly:   color.RGBA{R: 110, G: 230, B: 110, A: 255},
+	},
+
+	// Amber terminal: warm monochrome
+	"amber": {
+		RoleBackground: color.RGBA{R: 15, G: 8, B: 0, A: 255},
+		RoleText:       color.RGBA{R: 255, G: 176, B: 0, A: 255},
+		RoleTextDim:    color.RGBA{R: 150, G: 100, B: 0, A: 255},
+		RoleAccent:     color.RGBA{R: 255, G: 215, B: 110, A: 255},
+		RoleWarning:    color.RGBA{R: 255, G: 240, B: 200, A: 255},
+		RoleWall:       color.RGBA{R: 210, G: 140, B: 0, A: 255},
+		RoleFloor:      color.RGBA{R: 110, G: 70, B: 0, A: 255},
+		RolePlayer:     color.RGBA{R: 255, G: 230, B: 150, A: 255},
+		RoleHostile:    color.RGBA{R: 255, G: 250, B: 235, A: 255},
+		RoleFriendly:   color.RGBA{R: 230, G: 160, B: 40, A: 255},
+	},
+
+	// Protanopia: no reds; hostiles are orange and friendlies blue, which
+	// differ in brightness as well as hue, with a pale purple accent
+	"protanopia": {
+		RoleBackground: color.RGBA{R: 5, G: 5, B: 15, A: 255},
+		RoleText:       color.RGBA{R: 230, G: 230, B: 230, A: 255},
+		RoleTextDim:    color.RGBA{R: 130, G: 130, B: 140, A: 255},
+		RoleAccent:     color.RGBA{R: 204, G: 121, B: 167, A: 255},
+		RoleWarning:    color.RGBA{R: 240, G: 228, B: 66, A: 255},
+		RoleWall:       color.RGBA{R: 0, G: 114, B: 178, A: 255},
+		RoleFloor:      color.RGBA{R: 90, G: 90, B: 100, A: 255},
+		RolePlayer:     color.White,
+		RoleHostile:    color.RGBA{R: 230, G: 159, B: 0, A: 255},
+		RoleFriendly:   color.RGBA{R: 86, G: 180, B: 233, A: 255},
+	},
+
+	// Deuteranopia: no greens; uses the same blue and orange split with a
+	// darker hostile so it stands apart from warnings
+	"deuteranopia": {
+		RoleBackground: color.RGBA{R: 5, G: 5, B: 15, A: 255},
+		RoleText:       color.RGBA{R: 230, G: 230, B: 230, A: 255},
+		RoleTextDim:    color.RGBA{R: 130, G: 130, B: 140, A: 255},
+		RoleAccent:     color.RGBA{R: 204, G: 121, B: 167, A: 255},
+		RoleWarning:    color.RGBA{R: 240, G: 228, B: 66, A: 255},
+		RoleWall:       color.RGBA{R: 0, G: 114, B: 178, A: 255},
+		RoleFloor:      color.RGBA{R: 90, G: 90, B: 100, A: 255},
+		RolePlayer:     color.White,
+		RoleHostile:    color.RGBA{R: 213, G: 94, B: 0, A: 255},
+		RoleFriendly:   color.RGBA{R: 86, G: 180, B: 233, A: 255},
+	},
+
+	// Tritanopia: no blues; hostiles are red and friendlies teal
+	"tritanopia": {
+		RoleBackground: color.RGBA{R: 10, G: 5, B: 5, A: 255},
+		RoleText:       color.RGBA{R: 235, G: 235, B: 235, A: 255},
+		RoleTextDim:    color.RGBA{R: 140, G: 130, B: 130, A: 255},
+		RoleAccent:     color.RGBA{R: 255, G: 120, B: 150, A: 255},
+		RoleWarning:    color.RGBA{R: 255, G: 255, B: 255, A: 255},
+		RoleWall:       color.RGBA{R: 200, G: 80, B: 90, A: 255},
+		RoleFloor:      color.RGBA{R: 100, G: 95, B: 95, A: 255},
+		RolePlayer:     color.RGBA{R: 255, G: 200, B: 210, A: 255},
+		RoleHostile:    color.RGBA{R: 220, G: 20, B: 40, A: 255},
+		RoleFriendly:   color.RGBA{R: 0, G: 160, B: 150, A: 255},
+	},
+
+	// High contrast: pure colors on black for low vision
+	"high-contrast": {
+		RoleBackground: color.Black,
+		RoleText:       color.White,
+		RoleTextDim:    color.RGBA{R: 200, G: 200, B: 200, A: 255},
+		RoleAccent:     color.RGBA{R: 255, G: 255, B: 0, A: 255},
+		RoleWarning:    color.RGBA{R: 255, G: 80, B: 80, A: 255},
+		RoleWall:       color.White,
+		RoleFloor:      color.RGBA{R: 150, G: 150, B: 150, A: 255},
+		RolePlayer:     color.RGBA{R: 255, G: 255, B: 0, A: 255},
+		RoleHostile:    color.RGBA{R: 255, G: 0, B: 0, A: 255},
+		RoleFriendly:   color.RGBA{R: 0, G: 255, B: 0, A: 255},
+	},
+}
+
+// ThemeNames returns the names of the built in palettes in sorted order.
+func ThemeNames() []string {
+	names := make([]string, 0, len(palettes))
+	for name := range palettes {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// LookupPalette returns the palette for a theme name.
+func LookupPalette(theme string) (Palette, error) {
+	palette, ok := palettes[theme]
+	if !ok {
+		return Palette{}, fmt.Errorf("%w: %q, want one of %v", ErrUnknownTheme, theme, ThemeNames())
+	}
+
+	return palette, nil
+}
+
+// Color returns the palette's color for role.
+func (palette Palette) Color(role ColorRole) color.Color {
+	return palette[role]
+}
+
+// Palette returns the palette for the configured theme, or the default
+// palette if the theme is unknown.
+func (game *Game) Palette() Palette {
+	palette, err := LookupPalette(game.Settings.Theme)
+	if err != nil {
+		return palettes[DefaultTheme]
+	}
+
+	return palette
+}
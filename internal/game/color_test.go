@@ -0,0 +1,64 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLookupPalette(t *testing.T) {
+	for _, name := range ThemeNames() {
+		t.Run(name, func(t *testing.T) {
+			palette, err := LookupPalette(name)
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			for role := range roleCount {
+				if palette.Color(role) == nil {
+					t.Errorf("want a color for role %d, got nil", role)
+				}
+			}
+
+			// Creatures must not share a color with each other or with the
+			// other highlights
+			for _, role := range []ColorRole{RoleHostile, RoleFriendly} {
+				for _, other := range []ColorRole{RoleAccent, RoleWarning, RolePlayer, RoleHostile, RoleFriendly} {
+					if role != other && palette.Color(role) == palette.Color(other) {
+						t.Errorf("want roles %d and %d distinct, got %v for both", role, other, palette.Color(role))
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("unknown theme", func(t *testing.T) {
+		_, err := LookupPalette("sepia")
+
+		if !errors.Is(err, ErrUnknownTheme) {
+			t.Errorf("want %v, got %v", ErrUnknownTheme, err)
+		}
+	})
+}
+
+func TestGamePalette(t *testing.T) {
+	game := NewGame()
+
+	tests := []struct {
+		name  string
+		theme string
+		want  string
+	}{
+		{name: "configured theme", theme: "amber", want: "amber"},
+		{name: "unknown theme falls back", theme: "sepia", want: DefaultTheme},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game.Settings.Theme = tt.theme
+
+			if got := game.Palette(); got != palettes[tt.want] {
+				t.Errorf("want %s palette, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package game
+
+import "fmt"
+
+// Command is an action requested by the user, independent of the physical
+// input that produced it.
+type Command int
+
+const (
+	// CommandNone represents the absence of a command.
+	CommandNone Command = iota
+
+	// CommandMoveUp moves the player up one tile.
+	CommandMoveUp
+
+	// CommandMoveDown moves the player down one tile.
+	CommandMoveDown
+
+	// CommandMoveLeft moves the player left one tile.
+	CommandMoveLeft
+
+	// CommandMoveRight moves the player right one tile.
+	CommandMoveRight
+
+	// CommandMoveUpLeft moves the player diagonally up and left.
+	CommandMoveUpLeft
+
+	// CommandMoveUpRight moves the player diagonally up and right.
+	CommandMoveUpRight
+
+	// CommandMoveDownLeft moves the player diagonally down and left.
+	CommandMoveDownLeft
+
+	// CommandMoveDownRight moves the player diagonally down and right.
+	CommandMoveDownRight
+
+	// CommandQuit leaves the game from the title screen, or opens the pause
+	// menu at save and quit while playing.
+	CommandQuit
+
+	// CommandConfirm answers yes to a prompt or selects a menu entry.
+	CommandConfirm
+
+	// CommandCancel answers no to a prompt or leaves a menu.
+	CommandCancel
+
+	// CommandStartGame starts a new run from the title screen.
+	CommandStartGame
+
+	// CommandKeyBindings opens the key binding screen.
+	CommandKeyBindings
+
+	// CommandMenuUp moves a menu selection up.
+	CommandMenuUp
+
+	// CommandMenuDown moves a menu selection down.
+	CommandMenuDown
+
+	// CommandCommandMenu opens a list of commands that can be picked without
+	// knowing their keys, used by gamepads.
+	CommandCommandMenu
+
+	// CommandToggleTiles switches between font glyphs and graphical tiles.
+	CommandToggleTiles
+
+	// CommandFontLarger increases the font size.
+	CommandFontLarger
+
+	// CommandFontSmaller decreases the font size.
+	CommandFontSmaller
+
+	// CommandMorgue writes a character dump for the run in progress.
+	CommandMorgue
+
+	// CommandScores opens the high score table.
+	CommandScores
+
+	// CommandPause opens the pause menu.
+	CommandPause
+
+	// CommandTextSubmit enters the text typed into a text field.
+	CommandTextSubmit
+
+	// CommandTextCancel leaves a text field without entering it.
+	CommandTextCancel
+
+	// CommandTextDelete deletes the last character typed into a text field.
+	CommandTextDelete
+
+	// CommandHelp opens the help screen.
+	CommandHelp
+
+	// CommandConsole opens the wizard console when wizard mode is on.
+	CommandConsole
+
+	// CommandReplayPause pauses or resumes replay playback.
+	CommandReplayPause
+
+	// CommandReplayStep pauses replay playback and plays the next step.
+	CommandReplayStep
+
+	// CommandReplayFaster doubles the replay playback speed.
+	CommandReplayFaster
+
+	// CommandReplaySlower halves the replay playback speed.
+	CommandReplaySlower
+
+	// CommandRewind restores the previous turn history snapshot.
+	CommandRewind
+
+	// CommandFastForward restores the next turn history snapshot.
+	CommandFastForward
+
+	// CommandUseItem uses the item the player is standing on.
+	CommandUseItem
+)
+
+// InputContext is a bit set describing where a command can be issued. Key
+// bindings only conflict when their commands share a context.
+type InputContext int
+
+const (
+	// ContextTitle is active on the title screen.
+	ContextTitle InputContext = 1 << iota
+
+	// ContextPlaying is active while exploring the map.
+	ContextPlaying
+
+	// ContextPrompt is active while a yes/no prompt is shown.
+	ContextPrompt
+
+	// ContextMenu is active while a menu screen is shown.
+	ContextMenu
+
+	// ContextText is active while typing into a text field, where letter
+	// keys type instead of issuing commands.
+	ContextText
+
+	// ContextReplay is active while a replay is played back, where keys
+	// control playback instead of the game.
+	ContextReplay
+)
+
+// commandInfo describes how a command is named, documented, and grouped.
+type commandInfo struct {
+	name        string       // name identifies the command in config files
+	description string       // description is shown in docs and menus
+	group       string       // group is the heading the command is listed under
+	contexts    InputContext // contexts is where the command can be issued
+}
+
+// commandInfos holds the metadata for every command, indexed by Command.
+var commandInfos = []commandInfo{
+	CommandNone:          {name: "none"},
+	CommandMoveUp:        {name: "move_up", description: "up", group: "Movement", contexts: ContextPlaying},
+	CommandMoveDown:      {name: "move_down", description: "down", group: "Movement", contexts: ContextPlaying},
+	CommandMoveLeft:      {name: "move_left", description: "left", group: "Movement", contexts: ContextPlaying},
+	CommandMoveRight:     {name: "move_right", description: "right", group: "Movement", contexts: ContextPlaying},
+	CommandMoveUpLeft:    {name: "move_up_left", description: "up left", group: "Movement", contexts: ContextPlaying},
+	CommandMoveUpRight:   {name: "move_up_right", description: "up right", group: "Movement", contexts: ContextPlaying},
+	CommandMoveDownLeft:  {name: "move_down_left", description: "down left", group: "Movement", contexts: ContextPlaying},
+	CommandMoveDownRight: {name: "move_down_right", description: "down right", group: "Movement", contexts: ContextPlaying},
+	CommandQuit:          {name: "quit", description: "Quit", group: "Interface", contexts: ContextTitle | ContextPlaying | ContextReplay},
+	CommandConfirm:       {name: "confirm", description: "Confirm", group: "Interface", contexts: ContextTitle | ContextPrompt | ContextMenu},
+	CommandCancel:        {name: "cancel", description: "Cancel", group: "Interface", contexts: ContextPrompt | ContextMenu},
+	CommandStartGame:     {name: "start_game", description: "Start game", group: "Interface", contexts: ContextTitle},
+	CommandKeyBindings:   {name: "key_bindings", description: "Key bindings", group: "Interface", contexts: ContextTitle},
+	CommandMenuUp:        {name: "menu_up", description: "Menu up", group: "Interface", contexts: ContextTitle | ContextMenu},
+	CommandMenuDown:      {name: "menu_down", description: "Menu down", group: "Interface", contexts: ContextTitle | ContextMenu},
+	CommandCommandMenu:   {name: "command_menu", description: "Command menu", group: "Interface", contexts: ContextPlaying},
+	CommandToggleTiles:   {name: "toggle_tiles", description: "Toggle graphical tiles", group: "Interface", contexts: ContextTitle | ContextPlaying},
+	CommandFontLarger:    {name: "font_larger", description: "Larger font", group: "Interface", contexts: ContextTitle | ContextPlaying},
+	CommandFontSmaller:   {name: "font_smaller", description: "Smaller font", group: "Interface", contexts: ContextTitle | ContextPlaying},
+	CommandMorgue:        {name: "morgue", description: "Character dump", group: "Interface", contexts: ContextPlaying},
+	CommandScores:        {name: "scores", description: "High scores", group: "Interface", contexts: ContextTitle},
+	CommandPause:         {name: "pause", description: "Pause menu", group: "Interface", contexts: ContextPlaying},
+	CommandTextSubmit:    {name: "text_submit", description: "Enter text", group: "Interface", contexts: ContextText},
+	CommandTextCancel:    {name: "text_cancel", description: "Cancel text", group: "Interface", contexts: ContextText},
+	CommandTextDelete:    {name: "text_delete", description: "Delete character", group: "Interface", contexts: ContextText},
+	CommandHelp:          {name: "help", description: "Help", group: "Interface", contexts: ContextTitle | ContextPlaying},
+	CommandConsole:       {name: "console", description: "Wizard console", group: "Interface", contexts: ContextPlaying},
+	CommandReplayPause:   {name: "replay_pause", description: "Pause or resume", group: "Replay", contexts: ContextReplay},
+	CommandReplayStep:    {name: "replay_step", description: "Next step", group: "Replay", contexts: ContextReplay},
+	CommandReplayFaster:  {name: "replay_faster", description: "Faster", group: "Replay", contexts: ContextReplay},
+	CommandReplaySlower:  {name: "replay_slower", description: "Slower", group: "Replay", contexts: ContextReplay},
+	CommandRewind:        {name: "rewind", description: "Rewind turn history", group: "Interface", contexts: ContextPlaying},
+	CommandFastForward:   {name: "fast_forward", description: "Fast-forward turn history", group: "Interface", contexts: ContextPlaying},
+	CommandUseItem:       {name: "use_item", description: "Use item", group: "Actions", contexts: ContextPlaying},
+}
+
+// Commands returns every bindable command in display order.
+func Commands() []Command {
+	commands := make([]Command, 0, len(commandInfos)-1)
+
+	for command := range commandInfos {
+		if Command(command) == CommandNone {
+			continue
+		}
+
+		commands = append(commands, Command(command))
+	}
+
+	return commands
+}
+
+// ParseCommand returns the command with the given config file name.
+func ParseCommand(name string) (Command, error) {
+	for command, info := range commandInfos {
+		if info.name == name {
+			return Command(command), nil
+		}
+	}
+
+	return CommandNone, fmt.Errorf("%w: %q", ErrUnknownCommand, name)
+}
+
+// String returns the config file name of the command.
+func (command Command) String() string {
+	return command.info().name
+}
+
+// Description returns a short human readable description of the command.
+func (command Command) Description() string {
+	return command.info().description
+}
+
+// Group returns the heading the command is listed under in docs and menus.
+func (command Command) Group() string {
+	return command.info().group
+}
+
+// Contexts returns the input contexts in which the command can be issued.
+func (command Command) Contexts() InputContext {
+	return command.info().contexts
+}
+
+// info returns the metadata for the command, or the metadata for CommandNone
+// if the command is out of range.
+func (command Command) info() commandInfo {
+	if command < 0 || int(command) >= len(commandInfos) {
+		return commandInfos[CommandNone]
+	}
+
+	return commandInfos[command]
+}
+
+// Repeats reports whether the command repeats while its key is held.
+func (command Command) Repeats() bool {
+	_, _, ok := command.moveDelta()
+
+	return ok
+}
+
+// moveDelta returns the movement offset for a movement command. ok is false
+// for commands that do not move the player.
+func (command Command) moveDelta() (dx, dy int, ok bool) {
+	switch command {
+	case CommandMoveUp:
+		return 0, -1, true
+	case CommandMoveDown:
+		return 0, 1, true
+	case CommandMoveLeft:
+		return -1, 0, true
+	case CommandMoveRight:
+		return 1, 0, true
+	case CommandMoveUpLeft:
+		return -1, -1, true
+	case CommandMoveUpRight:
+		return 1, -1, true
+	case CommandMoveDownLeft:
+		return -1, 1, true
+	case CommandMoveDownRight:
+		return 1, 1, true
+	}
+
+	return 0, 0, false
+}
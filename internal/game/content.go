@@ -0,0 +1,676 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	// TileFloor is the ID of the tile rooms and corridors are carved from.
+	TileFloor = "floor"
+
+	// TileWall is the ID of the tile levels are filled with before carving.
+	TileWall = "wall"
+
+	// maxEssence is the essence a runner starts with; no single implant may
+	// cost more.
+	maxEssence = 6.0
+)
+
+// contentID is the form of definition IDs: lowercase words joined by
+// underscores, so they can be typed in the wizard console.
+var contentID = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+
+// cyberwareSlots lists the body locations cyberware can be installed in.
+var cyberwareSlots = []string{"head", "eyes", "ears", "torso", "arms", "legs", "nervous"}
+
+// TileDef defines a kind of terrain.
+type TileDef struct {
+	ID       string `json:"id" toml:"id"`                       // ID names the tile in other definitions.
+	Name     string `json:"name" toml:"name"`                   // Name is shown when the tile is examined.
+	Glyph    string `json:"glyph" toml:"glyph"`                 // Glyph is the single character used to render the tile.
+	Color    string `json:"color" toml:"color"`                 // Color is the name of the palette role, such as "wall".
+	Walkable bool   `json:"walkable" toml:"walkable"`           // Walkable indicates whether entities can move onto the tile.
+	OnEnter  string `json:"on_enter,omitempty" toml:"on_enter"` // OnEnter is a Lua script run when the player steps onto the tile.
+}
+
+// MonsterDef defines a creature that can be spawned on a level.
+type MonsterDef struct {
+	ID          string   `json:"id" toml:"id"`                             // ID names the monster in other definitions.
+	Name        string   `json:"name" toml:"name"`                         // Name is shown when the monster is examined.
+	Glyph       string   `json:"glyph" toml:"glyph"`                       // Glyph is the single character used to render the monster.
+	Color       string   `json:"color,omitempty" toml:"color"`             // Color is the name of the palette role; defaults to the disposition's.
+	Disposition string   `json:"disposition,omitempty" toml:"disposition"` // Disposition is "hostile" or "friendly"; defaults to "hostile".
+	Health      int      `json:"health" toml:"health"`                     // Health is the monster's starting health.
+	Damage      int      `json:"damage" toml:"damage"`                     // Damage is the harm done by one attack.
+	Depth       int      `json:"depth" toml:"depth"`                       // Depth is the shallowest level the monster appears on.
+	Description string   `json:"description,omitempty" toml:"description"` // Description is flavor text for the help and examine screens.
+	Tags        []string `json:"tags,omitempty" toml:"tags"`               // Tags group monsters for generators, such as "corporate".
+	OnHit       string   `json:"on_hit,omitempty" toml:"on_hit"`           // OnHit is a Lua script run when the player attacks the monster.
+}
+
+// ItemDef defines an object that can lie on the map and be picked up.
+type ItemDef struct {
+	ID          string   `json:"id" toml:"id"`                             // ID names the item in other definitions.
+	Name        string   `json:"name" toml:"name"`                         // Name is shown when the item is examined.
+	Glyph       string   `json:"glyph" toml:"glyph"`                       // Glyph is the single character used to render the item.
+	Color       string   `json:"color,omitempty" toml:"color"`             // Color is the name of the palette role; defaults to "friendly".
+	Value       int      `json:"value" toml:"value"`                       // Value is the item's price in nuyen.
+	Description string   `json:"description,omitempty" toml:"description"` // Description is flavor text for the help and examine screens.
+	Tags        []string `json:"tags,omitempty" toml:"tags"`               // Tags group items for generators, such as "medical".
+	OnUse       string   `json:"on_use,omitempty" toml:"on_use"`           // OnUse is a Lua script run when the player steps onto the item, using it up.
+}
+
+// CyberwareDef defines an implant a runner can have installed.
+type CyberwareDef struct {
+	ID          string  `json:"id" toml:"id"`                             // ID names the implant in other definitions.
+	Name        string  `json:"name" toml:"name"`                         // Name is shown in menus.
+	Slot        string  `json:"slot" toml:"slot"`                         // Slot is the body location, one of cyberwareSlots.
+	Essence     float64 `json:"essence" toml:"essence"`                   // Essence is how much of the runner's humanity the implant costs.
+	Value       int     `json:"value" toml:"value"`                       // Value is the implant's price in nuyen.
+	Description string  `json:"description,omitempty" toml:"description"` // Description is flavor text for menus.
+}
+
+// SpellDef defines a spell a runner can cast.
+type SpellDef struct {
+	ID          string `json:"id" toml:"id"`                             // ID names the spell in other definitions.
+	Name        string `json:"name" toml:"name"`                         // Name is shown in menus.
+	Drain       int    `json:"drain" toml:"drain"`                       // Drain is the health lost by casting the spell.
+	Range       int    `json:"range" toml:"range"`                       // Range is how far the spell reaches in tiles; 0 is self only.
+	Description string `json:"description,omitempty" toml:"description"` // Description is flavor text for menus.
+}
+
+// QuestDef defines a script run when something happens in the game, such as
+// entering a level.
+type QuestDef struct {
+	ID      string    `json:"id" toml:"id"`                   // ID names the quest in other definitions.
+	Name    string    `json:"name" toml:"name"`               // Name is shown in script errors.
+	Trigger EventKind `json:"trigger" toml:"trigger"`         // Trigger is the kind of event that runs the script.
+	Script  string    `json:"script" toml:"script"`           // Script is the Lua script to run.
+	Repeat  bool      `json:"repeat,omitempty" toml:"repeat"` // Repeat runs the script on every trigger instead of only the first.
+}
+
+// ContentFile is one definition file. Every section is optional, so a mod
+// can ship a single file with only the definitions it adds or changes.
+type ContentFile struct {
+	Tiles     []TileDef      `json:"tiles,omitempty" toml:"tiles"`         // Tiles are terrain definitions.
+	Monsters  []MonsterDef   `json:"monsters,omitempty" toml:"monsters"`   // Monsters are creature definitions.
+	Items     []ItemDef      `json:"items,omitempty" toml:"items"`         // Items are object definitions.
+	Cyberware []CyberwareDef `json:"cyberware,omitempty" toml:"cyberware"` // Cyberware are implant definitions.
+	Spells    []SpellDef     `json:"spells,omitempty" toml:"spells"`       // Spells are spell definitions.
+	Quests    []QuestDef     `json:"quests,omitempty" toml:"quests"`       // Quests are event triggered script definitions.
+	Prefabs   []PrefabDef    `json:"prefabs,omitempty" toml:"prefabs"`     // Prefabs are hand-authored map chunks.
+}
+
+// Content holds every definition the game knows, keyed by ID. Later
+// definitions replace earlier ones with the same ID, which is how mods
+// override the defaults.
+type Content struct {
+	Tiles     map[string]TileDef      // Tiles are terrain definitions.
+	Monsters  map[string]MonsterDef   // Monsters are creature definitions.
+	Items     map[string]ItemDef      // Items are object definitions.
+	Cyberware map[string]CyberwareDef // Cyberware are implant definitions.
+	Spells    map[string]SpellDef     // Spells are spell definitions.
+	Quests    map[string]QuestDef     // Quests are event triggered script definitions.
+	Prefabs   map[string]PrefabDef    // Prefabs are hand-authored map chunks.
+}
+
+// NewContent creates content holding only the built-in floor and wall tiles,
+// which every level needs.
+func NewContent() *Content {
+	return &Content{
+		Tiles: map[string]TileDef{
+			TileFloor: {ID: TileFloor, Name: FloorTile.Name, Glyph: string(FloorTile.Glyph), Color: "floor", Walkable: true},
+			TileWall:  {ID: TileWall, Name: WallTile.Name, Glyph: string(WallTile.Glyph), Color: "wall"},
+		},
+		Monsters:  map[string]MonsterDef{},
+		Items:     map[string]ItemDef{},
+		Cyberware: map[string]CyberwareDef{},
+		Spells:    map[string]SpellDef{},
+		Quests:    map[string]QuestDef{},
+		Prefabs:   map[string]PrefabDef{},
+	}
+}
+
+// LoadContent reads the definitions in dir of fsys, then every mod in
+// modsDir, and validates the result. modsDir may be empty or missing.
+func LoadContent(fsys fs.FS, dir, modsDir string) (*Content, error) {
+	content := NewContent()
+
+	if err := content.LoadDir(fsys, dir); err != nil {
+		return nil, err
+	}
+
+	if modsDir != "" {
+		if err := content.LoadMods(modsDir); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := content.Validate(); err != nil {
+		return nil, err
+	}
+
+	return content, nil
+}
+
+// LoadDir adds every .json and .toml file in dir of fsys, in name order.
+// Errors name the file they came from.
+func (content *Content) LoadDir(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrContentNotFound, err)
+	}
+
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".json" && ext != ".toml") {
+			continue
+		}
+
+		name := path.Join(dir, entry.Name())
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrContentNotFound, err)
+		}
+
+		file, err := DecodeContentFile(name, data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		content.Add(file)
+	}
+
+	return nil
+}
+
+// LoadMods adds the definitions in each subdirectory of modsDir, in name
+// order, so a mod can override the defaults and mods sorted before it. A
+// missing modsDir is not an error.
+func (content *Content) LoadMods(modsDir string) error {
+	entries, err := os.ReadDir(modsDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrContentNotFound, err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		if err := content.LoadDir(os.DirFS(filepath.Join(modsDir, entry.Name())), "."); err != nil {
+			return fmt.Errorf("mod %q: %w", entry.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// Add merges the definitions in file, replacing any with the same ID.
+func (content *Content) Add(file ContentFile) {
+	for _, def := range file.Tiles {
+		content.Tiles[def.ID] = def
+	}
+
+	for _, def := range file.Monsters {
+		content.Monsters[def.ID] = def
+	}
+
+	for _, def := range file.Items {
+		content.Items[def.ID] = def
+	}
+
+	for _, def := range file.Cyberware {
+		content.Cyberware[def.ID] = def
+	}
+
+	for _, def := range file.Spells {
+		content.Spells[def.ID] = def
+	}
+
+	for _, def := range file.Quests {
+		content.Quests[def.ID] = def
+	}
+
+	for _, def := range file.Prefabs {
+		content.Prefabs[def.ID] = def
+	}
+}
+
+// Validate checks the merged definitions: the floor and wall tiles must be
+// walkable and solid, no two tiles may share a glyph, since saves store maps
+// as glyphs, and every prefab must resolve and be connected.
+func (content *Content) Validate() error {
+	if floor, ok := content.Tiles[TileFloor]; !ok || !floor.Walkable {
+		return fmt.Errorf("%w: a walkable %q tile is required", ErrContentInvalid, TileFloor)
+	}
+
+	if wall, ok := content.Tiles[TileWall]; !ok || wall.Walkable {
+		return fmt.Errorf("%w: a solid %q tile is required", ErrContentInvalid, TileWall)
+	}
+
+	glyphs := map[string]string{}
+	for id, def := range content.Tiles {
+		if other, taken := glyphs[def.Glyph]; taken {
+			first, second := min(id, other), max(id, other)
+			return fmt.Errorf("%w: tiles %q and %q share the glyph %q", ErrContentInvalid, first, second, def.Glyph)
+		}
+
+		glyphs[def.Glyph] = id
+	}
+
+	// Prefabs refer to tiles, items, and monsters that may come from other
+	// files, so they are resolved once everything is loaded
+	for _, id := range slices.Sorted(maps.Keys(content.Prefabs)) {
+		if _, err := content.Prefab(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Tile returns the tile defined as id. The built-in floor and wall are used
+// when content is nil or lacks them, so maps can always be carved.
+func (content *Content) Tile(id string) Tile {
+	if content != nil {
+		if def, ok := content.Tiles[id]; ok {
+			return def.Tile()
+		}
+	}
+
+	if id == TileFloor {
+		return FloorTile
+	}
+
+	return WallTile
+}
+
+// TileForGlyph returns the tile drawn with glyph.
+func (content *Content) TileForGlyph(glyph rune) (Tile, bool) {
+	def, ok := content.tileDef(glyph)
+	if !ok {
+		return Tile{}, false
+	}
+
+	return def.Tile(), true
+}
+
+// tileDef returns the definition of the tile drawn with glyph. Glyphs are
+// unique among tiles, so the glyph identifies the definition.
+func (content *Content) tileDef(glyph rune) (TileDef, bool) {
+	if content == nil {
+		content = NewContent()
+	}
+
+	for _, def := range content.Tiles {
+		if def.Glyph == string(glyph) {
+			return def, true
+		}
+	}
+
+	return TileDef{}, false
+}
+
+// Monster returns the monster whose ID or name, ignoring case, is name.
+func (content *Content) Monster(name string) (MonsterDef, bool) {
+	if def, ok := content.Monsters[name]; ok {
+		return def, true
+	}
+
+	for _, def := range content.Monsters {
+		if strings.EqualFold(def.Name, name) {
+			return def, true
+		}
+	}
+
+	return MonsterDef{}, false
+}
+
+// Item returns the item whose ID or name, ignoring case, is name.
+func (content *Content) Item(name string) (ItemDef, bool) {
+	if def, ok := content.Items[name]; ok {
+		return def, true
+	}
+
+	for _, def := range content.Items {
+		if strings.EqualFold(def.Name, name) {
+			return def, true
+		}
+	}
+
+	return ItemDef{}, false
+}
+
+// Tile converts the definition to a map tile.
+func (def TileDef) Tile() Tile {
+	color, _ := ParseColorRole(def.Color)
+	glyph, _ := utf8.DecodeRuneInString(def.Glyph)
+
+	return Tile{Name: def.Name, Glyph: glyph, Color: color, Walkable: def.Walkable}
+}
+
+// Entity creates the monster at (x, y).
+func (def MonsterDef) Entity(x, y int) Entity {
+	glyph, _ := utf8.DecodeRuneInString(def.Glyph)
+
+	disposition, color := DispositionHostile, RoleHostile
+	if Disposition(def.Disposition) == DispositionFriendly {
+		disposition, color = DispositionFriendly, RoleFriendly
+	}
+
+	if role, ok := ParseColorRole(def.Color); ok {
+		color = role
+	}
+
+	return Entity{Kind: EntityMonster, ID: def.ID, Name: def.Name, Glyph: glyph, Color: color, Disposition: disposition, X: x, Y: y}
+}
+
+// Entity creates the item at (x, y).
+func (def ItemDef) Entity(x, y int) Entity {
+	glyph, _ := utf8.DecodeRuneInString(def.Glyph)
+
+	color := RoleFriendly
+	if role, ok := ParseColorRole(def.Color); ok {
+		color = role
+	}
+
+	return Entity{Kind: EntityItem, ID: def.ID, Name: def.Name, Glyph: glyph, Color: color, X: x, Y: y}
+}
+
+// DecodeContentFile parses a definition file, choosing JSON or TOML by the
+// extension of name, and validates each definition in it. Unknown fields are
+// rejected so typos are caught, and errors give the line they were found on.
+func DecodeContentFile(name string, data []byte) (ContentFile, error) {
+	var file ContentFile
+
+	switch path.Ext(name) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+
+		if err := decoder.Decode(&file); err != nil {
+			return ContentFile{}, fmt.Errorf("%w: %s", ErrContentParseFailed, describeJSONError(data, err))
+		}
+	case ".toml":
+		metadata, err := toml.Decode(string(data), &file)
+		if err != nil {
+			var parseErr toml.ParseError
+			if errors.As(err, &parseErr) {
+				return ContentFile{}, fmt.Errorf("%w: line %d: %s", ErrContentParseFailed, parseErr.Position.Line, parseErr.Message)
+			}
+
+			return ContentFile{}, fmt.Errorf("%w: %v", ErrContentParseFailed, err)
+		}
+
+		if undecoded := metadata.Undecoded(); len(undecoded) > 0 {
+			return ContentFile{}, fmt.Errorf("%w: unknown field %q", ErrContentParseFailed, undecoded[0].String())
+		}
+	default:
+		return ContentFile{}, fmt.Errorf("%w: want a .json or .toml file", ErrContentParseFailed)
+	}
+
+	if err := file.Validate(); err != nil {
+		return ContentFile{}, err
+	}
+
+	return file, nil
+}
+
+// describeJSONError rewrites a JSON decoding error with the line it was
+// found on.
+func describeJSONError(data []byte, err error) string {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Sprintf("line %d: %v", lineAt(data, syntaxErr.Offset), syntaxErr)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return fmt.Sprintf("line %d: %s must be %s, not %s", lineAt(data, typeErr.Offset), typeErr.Field, typeErr.Type, typeErr.Value)
+	}
+
+	return strings.TrimPrefix(err.Error(), "json: ")
+}
+
+// lineAt returns the line number of a byte offset into data.
+func lineAt(data []byte, offset int64) int {
+	offset = min(max(offset, 0), int64(len(data)))
+
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+// Validate checks each definition in the file on its own. IDs must be
+// unique within the file; overriding happens across files.
+func (file ContentFile) Validate() error {
+	if err := validateDefs("tile", file.Tiles, TileDef.validate); err != nil {
+		return err
+	}
+
+	if err := validateDefs("monster", file.Monsters, MonsterDef.validate); err != nil {
+		return err
+	}
+
+	if err := validateDefs("item", file.Items, ItemDef.validate); err != nil {
+		return err
+	}
+
+	if err := validateDefs("cyberware", file.Cyberware, CyberwareDef.validate); err != nil {
+		return err
+	}
+
+	if err := validateDefs("spell", file.Spells, SpellDef.validate); err != nil {
+		return err
+	}
+
+	if err := validateDefs("quest", file.Quests, QuestDef.validate); err != nil {
+		return err
+	}
+
+	return validateDefs("prefab", file.Prefabs, PrefabDef.validate)
+}
+
+// validateDefs checks the ID of every definition in defs and then runs
+// validate on it. kind names the section in errors.
+func validateDefs[T interface{ id() string }](kind string, defs []T, validate func(T) error) error {
+	seen := map[string]bool{}
+
+	for i, def := range defs {
+		id := def.id()
+
+		if id == "" {
+			return fmt.Errorf("%w: %s %d: id is required", ErrContentInvalid, kind, i+1)
+		}
+
+		if !contentID.MatchString(id) {
+			return fmt.Errorf("%w: %s %q: id must be lowercase letters and digits joined by underscores", ErrContentInvalid, kind, id)
+		}
+
+		if seen[id] {
+			return fmt.Errorf("%w: %s %q is defined twice", ErrContentInvalid, kind, id)
+		}
+
+		seen[id] = true
+
+		if err := validate(def); err != nil {
+			return fmt.Errorf("%w: %s %q: %w", ErrContentInvalid, kind, id, err)
+		}
+	}
+
+	return nil
+}
+
+// validateLook checks the fields that control how a definition is drawn.
+// An empty color is allowed when optional is true.
+func validateLook(name, glyph, color string, optional bool) error {
+	if name == "" {
+		return errors.New("name is required")
+	}
+
+	if utf8.RuneCountInString(glyph) != 1 {
+		return fmt.Errorf("glyph %q must be a single character", glyph)
+	}
+
+	if color == "" && optional {
+		return nil
+	}
+
+	if _, ok := ParseColorRole(color); !ok {
+		return fmt.Errorf("unknown color %q", color)
+	}
+
+	return nil
+}
+
+// id returns the tile's ID.
+func (def TileDef) id() string {
+	return def.ID
+}
+
+// validate checks the tile's fields.
+func (def TileDef) validate() error {
+	if err := validateLook(def.Name, def.Glyph, def.Color, false); err != nil {
+		return err
+	}
+
+	return validateScript("on_enter", def.OnEnter)
+}
+
+// id returns the monster's ID.
+func (def MonsterDef) id() string {
+	return def.ID
+}
+
+// validate checks the monster's fields.
+func (def MonsterDef) validate() error {
+	if err := validateLook(def.Name, def.Glyph, def.Color, true); err != nil {
+		return err
+	}
+
+	switch Disposition(def.Disposition) {
+	case "", DispositionHostile, DispositionFriendly:
+	default:
+		return fmt.Errorf("unknown disposition %q", def.Disposition)
+	}
+
+	if def.Health <= 0 {
+		return errors.New("health must be positive")
+	}
+
+	if def.Damage < 0 {
+		return errors.New("damage must not be negative")
+	}
+
+	if def.Depth < 1 {
+		return errors.New("depth must be at least 1")
+	}
+
+	return validateScript("on_hit", def.OnHit)
+}
+
+// id returns the item's ID.
+func (def ItemDef) id() string {
+	return def.ID
+}
+
+// validate checks the item's fields.
+func (def ItemDef) validate() error {
+	if err := validateLook(def.Name, def.Glyph, def.Color, true); err != nil {
+		return err
+	}
+
+	if def.Value < 0 {
+		return errors.New("value must not be negative")
+	}
+
+	return validateScript("on_use", def.OnUse)
+}
+
+// id returns the implant's ID.
+func (def CyberwareDef) id() string {
+	return def.ID
+}
+
+// validate checks the implant's fields.
+func (def CyberwareDef) validate() error {
+	if def.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if !slices.Contains(cyberwareSlots, def.Slot) {
+		return fmt.Errorf("unknown slot %q, want one of %s", def.Slot, strings.Join(cyberwareSlots, ", "))
+	}
+
+	if def.Essence <= 0 || def.Essence > maxEssence {
+		return fmt.Errorf("essence must be above 0 and at most %g", maxEssence)
+	}
+
+	if def.Value < 0 {
+		return errors.New("value must not be negative")
+	}
+
+	return nil
+}
+
+// id returns the spell's ID.
+func (def SpellDef) id() string {
+	return def.ID
+}
+
+// validate checks the spell's fields.
+func (def SpellDef) validate() error {
+	if def.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if def.Drain < 0 {
+		return errors.New("drain must not be negative")
+	}
+
+	if def.Range < 0 {
+		return errors.New("range must not be negative")
+	}
+
+	return nil
+}
+
+// id returns the quest's ID.
+func (def QuestDef) id() string {
+	return def.ID
+}
+
+// validate checks the quest's fields.
+func (def QuestDef) validate() error {
+	if def.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if !slices.Contains(eventKinds, def.Trigger) {
+		return fmt.Errorf("unknown trigger %q", def.Trigger)
+	}
+
+	if def.Script == "" {
+		return errors.New("script is required")
+	}
+
+	return validateScript("script", def.Script)
+}
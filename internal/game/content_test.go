@@ -0,0 +1,301 @@
+package game
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadContent(t *testing.T) {
+	t.Run("loads bundled content", func(t *testing.T) {
+		content, err := LoadContent(os.DirFS("../../assets"), "content", "")
+		if err != nil {
+			t.Fatalf("failed to load content: %v", err)
+		}
+
+		if content.Tile(TileFloor) != FloorTile || content.Tile(TileWall) != WallTile {
+			t.Errorf("want bundled floor and wall to match the built-in tiles, got %+v and %+v", content.Tile(TileFloor), content.Tile(TileWall))
+		}
+
+		for name, count := range map[string]int{
+			"monsters":  len(content.Monsters),
+			"items":     len(content.Items),
+			"cyberware": len(content.Cyberware),
+			"spells":    len(content.Spells),
+		} {
+			if count == 0 {
+				t.Errorf("want bundled %s, got none", name)
+			}
+		}
+	})
+
+	t.Run("mods add and override definitions", func(t *testing.T) {
+		base := fstest.MapFS{
+			"content/monsters.json": {Data: []byte(`{"monsters": [{"id": "ganger", "name": "ganger", "glyph": "g", "health": 10, "depth": 1}]}`)},
+		}
+
+		modsDir := t.TempDir()
+		writeMod(t, modsDir, "a-gangs", "monsters.toml", `
+[[monsters]]
+id = "ganger"
+name = "chrome ganger"
+glyph = "G"
+health = 20
+depth = 2
+
+[[items]]
+id = "chain"
+name = "chain"
+glyph = "~"
+value = 5
+`)
+		writeMod(t, modsDir, "b-sewers", "tiles.json", `{"tiles": [{"id": "sludge", "name": "sludge", "glyph": "~", "color": "floor", "walkable": true}]}`)
+
+		content, err := LoadContent(base, "content", modsDir)
+		if err != nil {
+			t.Fatalf("failed to load content: %v", err)
+		}
+
+		if got := content.Monsters["ganger"].Name; got != "chrome ganger" {
+			t.Errorf("want the mod's ganger, got %q", got)
+		}
+
+		if _, ok := content.Items["chain"]; !ok {
+			t.Error("want the mod's chain item, got none")
+		}
+
+		if tile, ok := content.TileForGlyph('~'); !ok || tile.Name != "sludge" {
+			t.Errorf("want sludge tile for ~, got %+v", tile)
+		}
+	})
+
+	t.Run("missing mods directory", func(t *testing.T) {
+		if _, err := LoadContent(fstest.MapFS{"content/x.json": {Data: []byte(`{}`)}}, "content", filepath.Join(t.TempDir(), "mods")); err != nil {
+			t.Errorf("want no error, got %v", err)
+		}
+	})
+
+	t.Run("bad mod names the mod and file", func(t *testing.T) {
+		modsDir := t.TempDir()
+		writeMod(t, modsDir, "broken", "items.json", `{"items": [{"id": "brick", "name": "brick", "glyph": "o", "value": -1}]}`)
+
+		_, err := LoadContent(fstest.MapFS{"content/x.json": {Data: []byte(`{}`)}}, "content", modsDir)
+		if !errors.Is(err, ErrContentInvalid) {
+			t.Fatalf("want %v, got %v", ErrContentInvalid, err)
+		}
+
+		want := `mod "broken": items.json: content definition is invalid: item "brick": value must not be negative`
+		if err.Error() != want {
+			t.Errorf("want %q, got %q", want, err.Error())
+		}
+	})
+
+	t.Run("tiles share a glyph", func(t *testing.T) {
+		base := fstest.MapFS{
+			"content/tiles.json": {Data: []byte(`{"tiles": [{"id": "grate", "name": "grate", "glyph": "#", "color": "wall"}]}`)},
+		}
+
+		if _, err := LoadContent(base, "content", ""); !errors.Is(err, ErrContentInvalid) {
+			t.Errorf("want %v, got %v", ErrContentInvalid, err)
+		}
+	})
+
+	t.Run("walkable wall", func(t *testing.T) {
+		base := fstest.MapFS{
+			"content/tiles.json": {Data: []byte(`{"tiles": [{"id": "wall", "name": "wall", "glyph": "#", "color": "wall", "walkable": true}]}`)},
+		}
+
+		if _, err := LoadContent(base, "content", ""); !errors.Is(err, ErrContentInvalid) {
+			t.Errorf("want %v, got %v", ErrContentInvalid, err)
+		}
+	})
+}
+
+// writeMod writes a definition file into a mod directory under modsDir.
+func writeMod(t *testing.T, modsDir, mod, name, data string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Join(modsDir, mod), 0o755); err != nil {
+		t.Fatalf("failed to create mod dir: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(modsDir, mod, name), []byte(data), 0o644); err != nil {
+		t.Fatalf("failed to write mod file: %v", err)
+	}
+}
+
+func TestDecodeContentFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		data    string
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "json syntax error",
+			file:    "a.json",
+			data:    "{\n  \"items\": [\n    {\"id\": \"x\",}\n  ]\n}",
+			wantErr: ErrContentParseFailed,
+			wantMsg: "line 3",
+		},
+		{
+			name:    "json wrong type",
+			file:    "a.json",
+			data:    "{\"monsters\": [\n{\"id\": \"x\", \"health\": \"lots\"}]}",
+			wantErr: ErrContentParseFailed,
+			wantMsg: "line 2: monsters.0.health must be int, not string",
+		},
+		{
+			name:    "json unknown field",
+			file:    "a.json",
+			data:    `{"items": [{"id": "x", "nmae": "typo"}]}`,
+			wantErr: ErrContentParseFailed,
+			wantMsg: `unknown field "nmae"`,
+		},
+		{
+			name:    "toml syntax error",
+			file:    "a.toml",
+			data:    "[[items]]\nid = \"x\"\nname = \n",
+			wantErr: ErrContentParseFailed,
+			wantMsg: "line 3",
+		},
+		{
+			name:    "toml unknown field",
+			file:    "a.toml",
+			data:    "[[spells]]\nid = \"x\"\nname = \"x\"\ndrian = 1\n",
+			wantErr: ErrContentParseFailed,
+			wantMsg: `unknown field "spells.drian"`,
+		},
+		{
+			name:    "unsupported extension",
+			file:    "a.yaml",
+			data:    "items: []",
+			wantErr: ErrContentParseFailed,
+		},
+		{
+			name:    "missing id",
+			file:    "a.json",
+			data:    `{"spells": [{"name": "heal"}]}`,
+			wantErr: ErrContentInvalid,
+			wantMsg: "spell 1: id is required",
+		},
+		{
+			name:    "bad id",
+			file:    "a.json",
+			data:    `{"spells": [{"id": "Big Heal", "name": "heal"}]}`,
+			wantErr: ErrContentInvalid,
+			wantMsg: `spell "Big Heal": id must be`,
+		},
+		{
+			name:    "duplicate id",
+			file:    "a.json",
+			data:    `{"spells": [{"id": "heal", "name": "heal"}, {"id": "heal", "name": "heal"}]}`,
+			wantErr: ErrContentInvalid,
+			wantMsg: `spell "heal" is defined twice`,
+		},
+		{
+			name:    "long glyph",
+			file:    "a.json",
+			data:    `{"items": [{"id": "x", "name": "x", "glyph": "xx"}]}`,
+			wantErr: ErrContentInvalid,
+			wantMsg: `glyph "xx" must be a single character`,
+		},
+		{
+			name:    "unknown color",
+			file:    "a.json",
+			data:    `{"tiles": [{"id": "x", "name": "x", "glyph": "x", "color": "plaid"}]}`,
+			wantErr: ErrContentInvalid,
+			wantMsg: `unknown color "plaid"`,
+		},
+		{
+			name:    "monster without health",
+			file:    "a.json",
+			data:    `{"monsters": [{"id": "x", "name": "x", "glyph": "x", "depth": 1}]}`,
+			wantErr: ErrContentInvalid,
+			wantMsg: "health must be positive",
+		},
+		{
+			name:    "unknown disposition",
+			file:    "a.json",
+			data:    `{"monsters": [{"id": "x", "name": "x", "glyph": "x", "disposition": "grumpy", "health": 1, "depth": 1}]}`,
+			wantErr: ErrContentInvalid,
+			wantMsg: `unknown disposition "grumpy"`,
+		},
+		{
+			name:    "unknown cyberware slot",
+			file:    "a.json",
+			data:    `{"cyberware": [{"id": "x", "name": "x", "slot": "tail", "essence": 1}]}`,
+			wantErr: ErrContentInvalid,
+			wantMsg: `unknown slot "tail"`,
+		},
+		{
+			name:    "too much essence",
+			file:    "a.json",
+			data:    `{"cyberware": [{"id": "x", "name": "x", "slot": "arms", "essence": 7}]}`,
+			wantErr: ErrContentInvalid,
+			wantMsg: "essence must be above 0",
+		},
+		{
+			name: "valid toml",
+			file: "a.toml",
+			data: "[[cyberware]]\nid = \"x\"\nname = \"x\"\nslot = \"eyes\"\nessence = 0.2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeContentFile(tt.file, []byte(tt.data))
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want %v, got %v", tt.wantErr, err)
+			}
+
+			if err != nil && !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("want error containing %q, got %q", tt.wantMsg, err.Error())
+			}
+		})
+	}
+}
+
+func TestContentEntities(t *testing.T) {
+	content := NewContent()
+	content.Add(ContentFile{
+		Monsters: []MonsterDef{
+			{ID: "office_worker", Name: "office worker", Glyph: "w", Disposition: "friendly", Health: 5, Depth: 1},
+			{ID: "ganger", Name: "ganger", Glyph: "g", Color: "friendly", Health: 5, Depth: 1},
+		},
+		Items: []ItemDef{{ID: "medkit", Name: "medkit", Glyph: "+"}},
+	})
+
+	def, ok := content.Monster("Office Worker")
+	if !ok {
+		t.Fatal("want monster found by name, got none")
+	}
+
+	worker := def.Entity(3, 4)
+	if worker.Kind != EntityMonster || worker.ID != "office_worker" || worker.Glyph != 'w' || worker.Color != RoleFriendly || worker.X != 3 || worker.Y != 4 {
+		t.Errorf("want friendly office worker at 3,4, got %+v", worker)
+	}
+
+	if !worker.Friendly() || worker.Hostile() {
+		t.Errorf("want office worker friendly, got %q", worker.Disposition)
+	}
+
+	def, _ = content.Monster("ganger")
+	if ganger := def.Entity(0, 0); !ganger.Hostile() || ganger.Color != RoleFriendly {
+		t.Errorf("want a hostile ganger drawn in the friendly color, got %+v", ganger)
+	}
+
+	item, ok := content.Item("medkit")
+	if !ok {
+		t.Fatal("want item found by id, got none")
+	}
+
+	if medkit := item.Entity(0, 0); medkit.Kind != EntityItem || medkit.Color != RoleFriendly {
+		t.Errorf("want friendly item, got %+v", medkit)
+	}
+}
@@ -55,3 +55,42 @@ func TestCalculateViewportBounds(t *testing.T) {
 		}
 	})
 }
+
+func TestScreenToTile(t *testing.T) {
+	game := NewGame()
+	game.CameraX = 30
+	game.CameraY = 12
+
+	renderer, err := NewEbitenRenderer(game, fontGoMono, 16.0)
+	if err != nil {
+		t.Fatalf("failed to create renderer: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		pixelX int
+		pixelY int
+		wantX  int
+		wantY  int
+		wantOK bool
+	}{
+		{name: "viewport origin", pixelX: 0, pixelY: 0, wantX: 2, wantY: 2, wantOK: true},
+		{name: "inside tile", pixelX: 16*3 + 5, pixelY: 16*4 + 15, wantX: 5, wantY: 6, wantOK: true},
+		{name: "stats panel", pixelX: 16 * 60, pixelY: 0, wantOK: false},
+		{name: "message log", pixelX: 0, pixelY: 16 * 21, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y, ok := renderer.ScreenToTile(tt.pixelX, tt.pixelY)
+
+			if ok != tt.wantOK {
+				t.Fatalf("want ok %v, got %v", tt.wantOK, ok)
+			}
+
+			if ok && (x != tt.wantX || y != tt.wantY) {
+				t.Errorf("want tile (%d,%d), got (%d,%d)", tt.wantX, tt.wantY, x, y)
+			}
+		})
+	}
+}
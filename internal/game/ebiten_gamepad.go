@@ -0,0 +1,86 @@
+package game
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// gamepadButtons maps standard layout face buttons to logical inputs.
+var gamepadButtons = map[ebiten.StandardGamepadButton]GamepadInput{
+	ebiten.StandardGamepadButtonRightBottom: GamepadAccept,
+	ebiten.StandardGamepadButtonRightRight:  GamepadBack,
+	ebiten.StandardGamepadButtonCenterRight: GamepadStart,
+}
+
+// gamepadCommands returns the commands issued by connected gamepads this
+// frame in the given context. Directions from the d-pad or left stick trigger
+// when they change and repeat while held, like movement keys.
+func (renderer *EbitenRenderer) gamepadCommands(context InputContext) []Command {
+	if !renderer.game.Settings.Gamepad.Enabled {
+		renderer.gamepadDirection = GamepadNone
+		return nil
+	}
+
+	var inputs []GamepadInput
+
+	direction := GamepadNone
+	renderer.gamepadIDs = ebiten.AppendGamepadIDs(renderer.gamepadIDs[:0])
+
+	for _, id := range renderer.gamepadIDs {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+
+		for button, input := range gamepadButtons {
+			if inpututil.IsStandardGamepadButtonJustPressed(id, button) {
+				inputs = append(inputs, input)
+			}
+		}
+
+		// The d-pad wins over the stick, and the first gamepad held wins
+		if direction == GamepadNone {
+			direction = DpadDirection(
+				ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButtonLeftTop),
+				ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButtonLeftBottom),
+				ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButtonLeftLeft),
+				ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButtonLeftRight),
+			)
+		}
+
+		if direction == GamepadNone {
+			direction = StickDirection(
+				ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal),
+				ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickVertical),
+				renderer.game.Settings.Gamepad.Deadzone,
+			)
+		}
+	}
+
+	if direction != renderer.gamepadDirection {
+		renderer.gamepadDirection = direction
+		renderer.gamepadHeldTicks = 0
+	}
+
+	if direction != GamepadNone {
+		renderer.gamepadHeldTicks++
+
+		frame := time.Second / time.Duration(ebiten.TPS())
+		held := time.Duration(renderer.gamepadHeldTicks) * frame
+		repeat := renderer.game.Settings.KeyRepeat
+
+		if renderer.gamepadHeldTicks == 1 || (!renderer.repeatSuppressed && repeat.Fires(held, frame)) {
+			inputs = append(inputs, direction)
+		}
+	}
+
+	var commands []Command
+	for _, input := range inputs {
+		if command := GamepadCommand(context, input); command != CommandNone {
+			commands = append(commands, command)
+		}
+	}
+
+	return commands
+}
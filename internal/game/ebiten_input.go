@@ -0,0 +1,157 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// modifierKeys maps chord modifier names to the Ebiten keys that hold them.
+var modifierKeys = map[string]ebiten.Key{
+	"Ctrl":  ebiten.KeyControl,
+	"Alt":   ebiten.KeyAlt,
+	"Shift": ebiten.KeyShift,
+}
+
+// parseKey returns the Ebiten key with the given name.
+func parseKey(name string) (ebiten.Key, error) {
+	var key ebiten.Key
+
+	if err := key.UnmarshalText([]byte(name)); err != nil {
+		return key, fmt.Errorf("%w: %q", ErrUnknownKey, name)
+	}
+
+	return key, nil
+}
+
+// validateKeymapKeys checks that every chord in keymap names a key Ebiten
+// knows about.
+func validateKeymapKeys(keymap Keymap) error {
+	for _, chords := range keymap {
+		for _, chord := range chords {
+			_, key := SplitChord(chord)
+
+			if _, err := parseKey(key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// chordHeldTicks returns how many ticks the chord's key has been held. ok is
+// false if the key is not held or the held modifiers do not match the chord.
+func chordHeldTicks(chord string) (int, bool) {
+	modifiers, name := SplitChord(chord)
+
+	key, err := parseKey(name)
+	if err != nil {
+		return 0, false
+	}
+
+	ticks := inpututil.KeyPressDuration(key)
+	if ticks == 0 {
+		return 0, false
+	}
+
+	held := make(map[string]bool, len(modifiers))
+	for _, modifier := range modifiers {
+		held[modifier] = true
+	}
+
+	for modifier, modifierKey := range modifierKeys {
+		if ebiten.IsKeyPressed(modifierKey) != held[modifier] {
+			return 0, false
+		}
+	}
+
+	return ticks, true
+}
+
+// triggeredCommands returns the commands triggered this frame in the given
+// context. A command triggers when one of its chords is first pressed or, for
+// repeating commands, when a held chord reaches its next repeat. Gamepad
+// commands are included. Repeats stay suppressed after an interruption until
+// every repeating chord and gamepad direction is released.
+func (renderer *EbitenRenderer) triggeredCommands(context InputContext) []Command {
+	var commands []Command
+
+	repeat := renderer.game.Settings.KeyRepeat
+	frame := time.Second / time.Duration(ebiten.TPS())
+	repeatHeld := false
+
+	for _, command := range Commands() {
+		if command.Contexts()&context == 0 {
+			continue
+		}
+
+		for _, chord := range renderer.game.Keymap[command] {
+			ticks, ok := chordHeldTicks(chord)
+			if !ok {
+				continue
+			}
+
+			if ticks == 1 {
+				commands = append(commands, command)
+				break
+			}
+
+			if !command.Repeats() {
+				continue
+			}
+
+			repeatHeld = true
+
+			if !renderer.repeatSuppressed && repeat.Fires(time.Duration(ticks)*frame, frame) {
+				commands = append(commands, command)
+				break
+			}
+		}
+	}
+
+	commands = append(commands, renderer.gamepadCommands(context)...)
+
+	if !repeatHeld && renderer.gamepadDirection == GamepadNone {
+		renderer.repeatSuppressed = false
+	}
+
+	return commands
+}
+
+// justPressedChord returns the chord formed by the first non-modifier key
+// pressed this frame and the modifiers currently held.
+func justPressedChord() (string, bool) {
+	for _, key := range inpututil.AppendJustPressedKeys(nil) {
+		if isModifierKey(key) {
+			continue
+		}
+
+		chord := key.String()
+		for i := len(chordModifiers) - 1; i >= 0; i-- {
+			if ebiten.IsKeyPressed(modifierKeys[chordModifiers[i]]) {
+				chord = chordModifiers[i] + "+" + chord
+			}
+		}
+
+		return chord, true
+	}
+
+	return "", false
+}
+
+// isModifierKey reports whether key is one of the modifier keys, which are
+// never bound on their own.
+func isModifierKey(key ebiten.Key) bool {
+	switch key {
+	case ebiten.KeyShiftLeft, ebiten.KeyShiftRight,
+		ebiten.KeyControlLeft, ebiten.KeyControlRight,
+		ebiten.KeyAltLeft, ebiten.KeyAltRight,
+		ebiten.KeyMetaLeft, ebiten.KeyMetaRight:
+		return true
+	}
+
+	return false
+}
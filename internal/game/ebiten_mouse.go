@@ -0,0 +1,148 @@
+package game
+
+import (
+	"image"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// hotspot is a clickable area of the screen registered while drawing.
+type hotspot struct {
+	bounds  image.Rectangle // bounds is the clickable area in pixels
+	onClick func() bool     // onClick runs on left click and returns true to exit
+}
+
+// ScreenToTile converts a pixel position to map tile coordinates through the
+// viewport. ok is false if the position is outside the map viewport.
+func (renderer *EbitenRenderer) ScreenToTile(pixelX, pixelY int) (int, int, bool) {
+	if pixelX < 0 || pixelY < 0 {
+		return 0, 0, false
+	}
+
+	screenX := pixelX / renderer.tileSize
+	screenY := pixelY / renderer.tileSize
+
+	minX, minY, maxX, maxY := renderer.CalculateViewportBounds()
+	tileX := minX + screenX
+	tileY := minY + screenY
+
+	if tileX >= maxX || tileY >= maxY || !renderer.game.InBounds(tileX, tileY) {
+		return 0, 0, false
+	}
+
+	return tileX, tileY, true
+}
+
+// updateMouse handles hover and clicks for the current frame. Returns true if
+// a click asked the game to exit.
+func (renderer *EbitenRenderer) updateMouse() bool {
+	renderer.hovering = false
+
+	if !renderer.game.Settings.Mouse {
+		return false
+	}
+
+	cursorX, cursorY := ebiten.CursorPosition()
+	renderer.cursor = image.Pt(cursorX, cursorY)
+
+	// On the map the cursor picks tiles; elsewhere it picks menu items
+	if renderer.game.InputContext() == ContextPlaying {
+		tileX, tileY, ok := renderer.ScreenToTile(cursorX, cursorY)
+		if !ok {
+			return false
+		}
+
+		renderer.hovering = true
+		renderer.hoverTile = Point{X: tileX, Y: tileY}
+
+		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+			renderer.game.TravelTo(tileX, tileY)
+		}
+
+		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+			renderer.game.Examine(tileX, tileY)
+		}
+
+		return false
+	}
+
+	if !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return false
+	}
+
+	for _, spot := range renderer.hotspots {
+		if renderer.cursor.In(spot.bounds) {
+			return spot.onClick()
+		}
+	}
+
+	return false
+}
+
+// updateTravel walks the player along a travel route, one step per key repeat
+// interval so travel is as fast as holding a movement key.
+func (renderer *EbitenRenderer) updateTravel() {
+	if !renderer.game.IsTraveling() {
+		renderer.travelTicks = 0
+		return
+	}
+
+	frame := time.Second / time.Duration(ebiten.TPS())
+	ticksPerStep := max(1, int(time.Duration(renderer.game.Settings.KeyRepeat.Interval)/frame))
+
+	renderer.travelTicks++
+	if renderer.travelTicks >= ticksPerStep {
+		renderer.travelTicks = 0
+		renderer.game.StepTravel()
+	}
+}
+
+// drawClickable draws text that runs onClick when clicked, highlighting it
+// while the mouse hovers over it.
+func (renderer *EbitenRenderer) drawClickable(screen *ebiten.Image, txt string, x, y float64, role ColorRole, onClick func() bool) {
+	width := text.Advance(txt, renderer.fontFace)
+	bounds := image.Rect(int(x), int(y), int(x+width), int(y)+renderer.tileSize)
+
+	renderer.hotspots = append(renderer.hotspots, hotspot{bounds: bounds, onClick: onClick})
+
+	if renderer.game.Settings.Mouse && renderer.cursor.In(bounds) {
+		role = RoleText
+	}
+
+	renderer.drawText(screen, txt, x, y, role)
+}
+
+// centerClickable draws clickable text centered horizontally at y.
+func (renderer *EbitenRenderer) centerClickable(screen *ebiten.Image, txt string, y float64, role ColorRole, onClick func() bool) {
+	screenWidthPixels := float64(renderer.screenWidth)
+	x := (screenWidthPixels - text.Advance(txt, renderer.fontFace)) / 2.0
+	renderer.drawClickable(screen, txt, x, y, role, onClick)
+}
+
+// RenderHover outlines the tile under the mouse cursor and describes it in a
+// tooltip while playing.
+func (renderer *EbitenRenderer) RenderHover(screen *ebiten.Image) {
+	if !renderer.hovering {
+		return
+	}
+
+	minX, minY, _, _ := renderer.CalculateViewportBounds()
+	size := float32(renderer.tileSize)
+	x := float32(renderer.hoverTile.X-minX) * size
+	y := float32(renderer.hoverTile.Y-minY) * size
+
+	vector.StrokeRect(screen, x, y, size, size, 1, renderer.color(RoleAccent), false)
+
+	if renderer.game.State != StatePlaying {
+		return
+	}
+
+	anchor := Point{X: renderer.hoverTile.X - minX, Y: renderer.hoverTile.Y - minY}
+	viewport := Rect{Width: renderer.layout.ViewportWidth, Height: renderer.layout.ViewportHeight}
+	description := renderer.game.Describe(renderer.hoverTile.X, renderer.hoverTile.Y)
+	renderer.drawTooltip(screen, NewTooltip(description, anchor, viewport))
+}
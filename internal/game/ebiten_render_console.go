@@ -0,0 +1,28 @@
+package game
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const consoleTitle = "Wizard Console"
+
+// RenderConsole draws the wizard console as a panel across the top of the
+// map viewport: recent output above the line being typed.
+func (renderer *EbitenRenderer) RenderConsole(screen *ebiten.Image) {
+	console := renderer.game.Console
+	panel := Panel{
+		Bounds: Rect{X: 1, Y: 1, Width: renderer.layout.ViewportWidth - 2, Height: consoleLines + 3},
+		Title:  consoleTitle,
+	}
+
+	renderer.drawPanel(screen, panel)
+
+	inner := panel.Bounds.Inner()
+	lineHeight := float64(renderer.tileSize)
+
+	for i, line := range console.Output {
+		renderer.drawText(screen, line, float64(inner.X)*lineHeight, float64(inner.Y+i)*lineHeight, RoleTextDim)
+	}
+
+	renderer.drawTextInput(screen, console.Input, inner.X, inner.Y+consoleLines)
+}
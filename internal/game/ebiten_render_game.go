@@ -2,9 +2,9 @@ package game
 
 import (
 	"fmt"
-	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 // RenderMap draws all the tiles from the game map that are visible in the viewport.
@@ -13,6 +13,10 @@ func (renderer *EbitenRenderer) RenderMap(screen *ebiten.Image, game *Game) {
 
 	for y := minY; y < maxY; y++ {
 		for x := minX; x < maxX; x++ {
+			if !game.Visible(x, y) {
+				continue
+			}
+
 			tile := game.Tiles[y][x]
 
 			// Render at screen position offset by viewport origin
@@ -27,55 +31,121 @@ func (renderer *EbitenRenderer) RenderMap(screen *ebiten.Image, game *Game) {
 // RenderTile draws a single tile glyph at the specified tile coordinates.
 // tileX and tileY are in tile units which are converted to pixel coordinates.
 func (renderer *EbitenRenderer) RenderTile(screen *ebiten.Image, tile Tile, tileX, tileY int) {
-	renderer.renderGlyph(screen, tile.Glyph, tileX, tileY, tile.Color)
+	renderer.renderSprite(screen, tile.Name, tile.Glyph, tileX, tileY, tile.Color)
+}
+
+// RenderEntities draws the monsters and items inside the viewport, items
+// first so monsters standing on them stay visible.
+func (renderer *EbitenRenderer) RenderEntities(screen *ebiten.Image) {
+	minX, minY, maxX, maxY := renderer.CalculateViewportBounds()
+
+	for _, kind := range []EntityKind{EntityItem, EntityMonster} {
+		for _, entity := range renderer.game.Entities {
+			if entity.Kind != kind || entity.X < minX || entity.X >= maxX || entity.Y < minY || entity.Y >= maxY || !renderer.game.Visible(entity.X, entity.Y) {
+				continue
+			}
+
+			renderer.renderSprite(screen, entity.Name, entity.Glyph, entity.X-minX, entity.Y-minY, entity.Color)
+			renderer.renderMarker(screen, renderer.game.EntityMarker(entity), entity.X-minX, entity.Y-minY, entity.Color)
+		}
+	}
+}
+
+// renderMarker draws marker on the tile at the specified tile coordinates:
+// brackets down both sides for a hostile creature, a line along the bottom
+// for a friendly one.
+func (renderer *EbitenRenderer) renderMarker(screen *ebiten.Image, marker EntityMarker, tileX, tileY int, role ColorRole) {
+	size := float32(renderer.tileSize)
+	x, y := float32(tileX)*size, float32(tileY)*size
+	thickness := max(size/8, 1)
+	arm := size / 4
+	markerColor := renderer.color(role)
+
+	switch marker {
+	case MarkerHostile:
+		for _, side := range []float32{x, x + size - thickness} {
+			vector.FillRect(screen, side, y, thickness, size, markerColor, false)
+		}
+
+		for _, corner := range []float32{x, x + size - arm} {
+			vector.FillRect(screen, corner, y, arm, thickness, markerColor, false)
+			vector.FillRect(screen, corner, y+size-thickness, arm, thickness, markerColor, false)
+		}
+	case MarkerFriendly:
+		vector.FillRect(screen, x, y+size-thickness, size, thickness, markerColor, false)
+	}
 }
 
 // RenderPlayer draws the player character at their viewport relative position.
 func (renderer *EbitenRenderer) RenderPlayer(screen *ebiten.Image, player Player) {
 	screenX, screenY := renderer.CalculatePlayerScreenPosition()
-	renderer.renderGlyph(screen, player.Glyph, screenX, screenY, player.Color)
+	renderer.renderSprite(screen, "player", player.Glyph, screenX, screenY, player.Color)
 }
 
 // RenderStatsPanel draws the player stats in the right panel (24 columns).
 func (renderer *EbitenRenderer) RenderStatsPanel(screen *ebiten.Image) {
-	// Panel starts at x=56 (after viewport), top of screen
-	panelX := float64(mapViewportWidth * renderer.tileSize)
+	// Panel starts after the viewport, top of screen
+	panelX := float64(renderer.layout.ViewportWidth * renderer.tileSize)
 	startY := 0.0
 	lineHeight := float64(renderer.tileSize)
 
 	// Draw panel title
-	renderer.drawText(screen, "== Runner ==", panelX, startY, colorYellow)
+	renderer.drawText(screen, "== Runner ==", panelX, startY, RoleAccent)
 
 	// Draw player name
 	nameY := startY + lineHeight*2
-	renderer.drawText(screen, renderer.game.Player.Name, panelX, nameY, colorWhite)
+	renderer.drawText(screen, renderer.game.Player.Name, panelX, nameY, RoleText)
 
 	// Draw level
 	levelY := nameY + lineHeight*2
 	levelText := fmt.Sprintf("Level: %d", renderer.game.Player.Level)
-	renderer.drawText(screen, levelText, panelX, levelY, colorWhite)
+	renderer.drawText(screen, levelText, panelX, levelY, RoleText)
 
 	// Draw health
 	healthY := levelY + lineHeight
 	healthText := fmt.Sprintf("Health: %d", renderer.game.Player.Health)
-	renderer.drawText(screen, healthText, panelX, healthY, color.White)
+	renderer.drawText(screen, healthText, panelX, healthY, RoleText)
+
+	// Draw the turn history position in wizard mode
+	if history := renderer.game.History; history != nil {
+		position := "present"
+		if history.Position() >= 0 {
+			position = fmt.Sprintf("turn %d", history.At(history.Position()).Turn)
+		}
+
+		historyText := fmt.Sprintf("History: %d (%s)", history.Len(), position)
+		renderer.drawText(screen, historyText, panelX, healthY+lineHeight*4, RoleTextDim)
+	}
+
+	// Draw where the mouse cursor is; the hover tooltip describes what is there
+	if renderer.hovering {
+		cursorY := healthY + lineHeight*2
+		cursorText := fmt.Sprintf("Cursor: %d,%d", renderer.hoverTile.X, renderer.hoverTile.Y)
+		renderer.drawText(screen, cursorText, panelX, cursorY, RoleTextDim)
+	}
 }
 
 // RenderMessageLog draws the message log area at the bottom of the screen
 // (4 lines high).
 func (renderer *EbitenRenderer) RenderMessageLog(screen *ebiten.Image) {
-	// Message log starts below the viewport (20 tiles down)
-	logY := mapViewportHeight
+	// Message log starts below the viewport
+	logY := renderer.layout.ViewportHeight
 
 	// Draw separator line
-	for x := 0; x < renderer.game.Width; x++ {
-		renderer.renderGlyph(screen, '=', x, logY, colorYellow)
+	for x := 0; x < renderer.layout.Columns; x++ {
+		renderer.renderGlyph(screen, '=', x, logY, RoleAccent)
 	}
 
-	// If quit confirmation is active show it in the message log
-	if renderer.game.IsConfirmingQuit() {
-		promptX := 1.0 * float64(renderer.tileSize)
-		promptY := float64((logY + 1) * renderer.tileSize)
-		renderer.drawText(screen, "Really quit? (Y/N)", promptX, promptY, colorYellow)
+	textX := 1.0 * float64(renderer.tileSize)
+
+	// Show the newest messages, important ones highlighted
+	for i, message := range renderer.game.RecentMessages(renderer.layout.MessageLines()) {
+		role := RoleTextDim
+		if message.Important {
+			role = RoleWarning
+		}
+
+		messageY := float64((logY + 1 + i) * renderer.tileSize)
+		renderer.drawText(screen, renderer.game.MessageText(message), textX, messageY, role)
 	}
 }
@@ -0,0 +1,60 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const gameOverHeading = "== You Died =="
+
+// RenderGameOver draws the death screen with the cause of death, turns
+// survived, score, and menu, or the morgue text while it is being viewed.
+func (renderer *EbitenRenderer) RenderGameOver(screen *ebiten.Image) {
+	gameOver := renderer.game.GameOver
+	if gameOver.ViewingMorgue {
+		renderer.renderMorgue(screen)
+		return
+	}
+
+	lineHeight := float64(renderer.tileSize)
+	y := 5.0 * lineHeight
+
+	renderer.centerText(screen, gameOverHeading, y, RoleWarning)
+	renderer.centerText(screen, "Killed by "+gameOver.Cause, y+2*lineHeight, RoleText)
+	renderer.centerText(screen, fmt.Sprintf("Survived %d turns", renderer.game.TurnCount), y+3*lineHeight, RoleText)
+	renderer.centerText(screen, fmt.Sprintf("Score: %d", gameOver.Score), y+4*lineHeight, RoleAccent)
+
+	menuY := y + 7*lineHeight
+	for i, item := range GameOverMenu {
+		role := RoleTextDim
+		if i == gameOver.Cursor {
+			item = "> " + item + " <"
+			role = RoleAccent
+		}
+
+		renderer.centerClickable(screen, item, menuY+float64(i)*lineHeight, role, func() bool {
+			return renderer.game.ClickEntry(i)
+		})
+	}
+}
+
+// renderMorgue draws the morgue text from the scroll position down, with a
+// help line at the bottom.
+func (renderer *EbitenRenderer) renderMorgue(screen *ebiten.Image) {
+	lineHeight := float64(renderer.tileSize)
+	leftX := lineHeight
+	gameOver := renderer.game.GameOver
+
+	rows := max(renderer.layout.Rows-2, 1)
+	end := min(gameOver.Scroll+rows, len(gameOver.MorgueLines))
+
+	for i, line := range gameOver.MorgueLines[gameOver.Scroll:end] {
+		renderer.drawText(screen, line, leftX, float64(i)*lineHeight, RoleText)
+	}
+
+	help := fmt.Sprintf("%s/%s: scroll   %s: back",
+		renderer.game.Keymap.Label(CommandMenuUp), renderer.game.Keymap.Label(CommandMenuDown),
+		renderer.game.Keymap.Label(CommandCancel))
+	renderer.drawText(screen, help, leftX, float64(renderer.layout.Rows-1)*lineHeight, RoleTextDim)
+}
@@ -0,0 +1,35 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const helpTitle = "== Help =="
+
+// RenderHelp draws the visible part of the help text.
+func (renderer *EbitenRenderer) RenderHelp(screen *ebiten.Image) {
+	lineHeight := float64(renderer.tileSize)
+	leftX := 2.0 * lineHeight
+	help := renderer.game.Help
+
+	renderer.centerText(screen, helpTitle, lineHeight, RoleAccent)
+
+	rows := max(renderer.layout.Rows-5, 1)
+	end := min(help.Scroll+rows, len(help.Lines))
+
+	for i, line := range help.Lines[help.Scroll:end] {
+		role := RoleText
+		if line != "" && line[0] != ' ' {
+			role = RoleAccent
+		}
+
+		renderer.drawText(screen, line, leftX, float64(i+3)*lineHeight, role)
+	}
+
+	keys := fmt.Sprintf("%s/%s: scroll   %s: back",
+		renderer.game.Keymap.Label(CommandMenuUp), renderer.game.Keymap.Label(CommandMenuDown),
+		renderer.game.Keymap.Label(CommandCancel))
+	renderer.drawText(screen, keys, leftX, float64(renderer.layout.Rows-2)*lineHeight, RoleTextDim)
+}
@@ -0,0 +1,43 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const keyBindingsTitle = "== Key Bindings =="
+
+// RenderKeyBindings draws the list of commands with their bound keys, the
+// current selection, and feedback from the last rebind attempt.
+func (renderer *EbitenRenderer) RenderKeyBindings(screen *ebiten.Image) {
+	lineHeight := float64(renderer.tileSize)
+	leftX := 2.0 * lineHeight
+	keysX := 20.0 * lineHeight
+
+	renderer.centerText(screen, keyBindingsTitle, lineHeight, RoleAccent)
+
+	for i, command := range Commands() {
+		y := float64(i+3) * lineHeight
+
+		role := RoleTextDim
+		if i == renderer.game.KeyBindings.Cursor {
+			role = RoleAccent
+			renderer.drawText(screen, ">", leftX-lineHeight, y, role)
+		}
+
+		// Clicking a row selects it; clicking the selected row rebinds it
+		label := fmt.Sprintf("%s (%s)", command.Description(), command.Group())
+		renderer.drawClickable(screen, label, leftX, y, role, func() bool {
+			return renderer.game.ClickEntry(i)
+		})
+		renderer.drawText(screen, renderer.game.Keymap.Label(command), keysX, y, role)
+	}
+
+	statusY := float64(renderer.layout.Rows-3) * lineHeight
+	renderer.drawText(screen, renderer.game.KeyBindings.Status, leftX, statusY, RoleText)
+
+	help := fmt.Sprintf("%s: rebind   %s: back",
+		renderer.game.Keymap.Label(CommandConfirm), renderer.game.Keymap.Label(CommandCancel))
+	renderer.drawText(screen, help, leftX, statusY+lineHeight, RoleTextDim)
+}
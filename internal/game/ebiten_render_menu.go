@@ -0,0 +1,20 @@
+package game
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const commandMenuTitle = "Commands"
+
+// RenderCommandMenu draws the command menu as a panel over the map viewport.
+// Each entry shows its hotkey, the command, and its keys, and can be clicked.
+func (renderer *EbitenRenderer) RenderCommandMenu(screen *ebiten.Image) {
+	menu := &renderer.game.CommandMenu
+	panel := Panel{
+		Bounds: Rect{X: 4, Y: 3, Width: renderer.layout.ViewportWidth - 8, Height: min(len(menu.Items)+2, renderer.layout.ViewportHeight-4)},
+		Title:  commandMenuTitle,
+	}
+
+	renderer.drawPanel(screen, panel)
+	renderer.drawList(screen, menu, panel.Bounds.Inner(), renderer.game.ClickEntry)
+}
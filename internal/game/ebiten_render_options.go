@@ -0,0 +1,38 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const optionsTitle = "== Options =="
+
+// RenderOptions draws the adjustable settings with their current values.
+func (renderer *EbitenRenderer) RenderOptions(screen *ebiten.Image) {
+	lineHeight := float64(renderer.tileSize)
+	leftX := 2.0 * lineHeight
+
+	renderer.centerText(screen, optionsTitle, lineHeight, RoleAccent)
+
+	for i, label := range renderer.game.OptionLabels() {
+		y := float64(i+3) * lineHeight
+
+		role := RoleTextDim
+		if i == renderer.game.Options.Cursor {
+			role = RoleAccent
+			renderer.drawText(screen, ">", leftX-lineHeight, y, role)
+		}
+
+		renderer.drawClickable(screen, label, leftX, y, role, func() bool {
+			return renderer.game.ClickEntry(i)
+		})
+	}
+
+	statusY := float64(renderer.layout.Rows-3) * lineHeight
+	renderer.drawText(screen, renderer.game.Options.Status, leftX, statusY, RoleWarning)
+
+	help := fmt.Sprintf("%s: change   %s: back",
+		renderer.game.Keymap.Label(CommandConfirm), renderer.game.Keymap.Label(CommandCancel))
+	renderer.drawText(screen, help, leftX, statusY+lineHeight, RoleTextDim)
+}
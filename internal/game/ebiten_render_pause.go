@@ -0,0 +1,27 @@
+package game
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const pauseTitle = "Paused"
+
+// RenderPauseMenu draws the pause menu as a panel over the map viewport, or
+// the abandon dialog while it is being asked.
+func (renderer *EbitenRenderer) RenderPauseMenu(screen *ebiten.Image) {
+	pause := &renderer.game.Pause
+	width := 30
+	panel := Panel{
+		Bounds: Rect{X: (renderer.layout.ViewportWidth - width) / 2, Y: 3, Width: width, Height: len(PauseMenu) + 2},
+		Title:  pauseTitle,
+	}
+
+	renderer.drawPanel(screen, panel)
+
+	if pause.Confirming {
+		renderer.drawDialog(screen, pause.Abandon, panel.Bounds.Inner())
+		return
+	}
+
+	renderer.drawList(screen, &pause.Menu, panel.Bounds.Inner(), renderer.game.ClickEntry)
+}
@@ -0,0 +1,23 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// RenderReplayStatus draws the replay position and controls along the top
+// row of the screen.
+func (renderer *EbitenRenderer) RenderReplayStatus(screen *ebiten.Image) {
+	lineHeight := float64(renderer.tileSize)
+	keymap := renderer.game.Keymap
+
+	status := fmt.Sprintf("%s   %s: pause  %s: step  %s/%s: speed  %s: quit",
+		renderer.replay.Status(),
+		keymap.Label(CommandReplayPause), keymap.Label(CommandReplayStep),
+		keymap.Label(CommandReplaySlower), keymap.Label(CommandReplayFaster), keymap.Label(CommandQuit))
+
+	vector.FillRect(screen, 0, 0, float32(renderer.screenWidth), float32(lineHeight), renderer.color(RoleBackground), false)
+	renderer.drawText(screen, status, 0, 0, RoleAccent)
+}
@@ -0,0 +1,49 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const scoresTitle = "== High Scores =="
+
+// RenderScores draws the best runs for the selected archetype filter.
+func (renderer *EbitenRenderer) RenderScores(screen *ebiten.Image) {
+	lineHeight := float64(renderer.tileSize)
+	leftX := 2.0 * lineHeight
+
+	renderer.centerText(screen, scoresTitle, lineHeight, RoleAccent)
+
+	filter := renderer.game.ScoreFilters()[renderer.game.ScoresScreen.Filter]
+
+	label := filter
+	if label == "" {
+		label = "All"
+	}
+
+	renderer.drawText(screen, "Archetype: "+label, leftX, 3*lineHeight, RoleText)
+
+	header := fmt.Sprintf("%3s %8s  %-16s %-14s %5s %6s  %s", "#", "Score", "Name", "Archetype", "Depth", "Turns", "Ending")
+	renderer.drawText(screen, header, leftX, 5*lineHeight, RoleAccent)
+
+	runs := renderer.game.Scores.Top(filter, scoresShown)
+	if len(runs) == 0 {
+		renderer.drawText(screen, "No runs recorded yet.", leftX, 6*lineHeight, RoleTextDim)
+	}
+
+	for i, run := range runs {
+		ending := run.Ending
+		if run.Wizard {
+			ending += " (wizard)"
+		}
+
+		row := fmt.Sprintf("%3d %8d  %-16s %-14s %5d %6d  %s", i+1, run.Score, run.Name, run.Archetype, run.Depth, run.Turns, ending)
+		renderer.drawText(screen, row, leftX, float64(i+6)*lineHeight, RoleTextDim)
+	}
+
+	help := fmt.Sprintf("%s/%s: archetype   %s: back",
+		renderer.game.Keymap.Label(CommandMenuUp), renderer.game.Keymap.Label(CommandMenuDown),
+		renderer.game.Keymap.Label(CommandCancel))
+	renderer.drawText(screen, help, leftX, float64(renderer.layout.Rows-2)*lineHeight, RoleTextDim)
+}
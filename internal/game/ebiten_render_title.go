@@ -1,6 +1,10 @@
 package game
 
-import "github.com/hajimehoshi/ebiten/v2"
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
 
 var titleScreenArt = []string{
 	"  _________                          .__                                          ",
@@ -12,14 +16,13 @@ var titleScreenArt = []string{
 }
 
 const (
-	titleScreenSubtitle    = "A Cyberpunk Roguelike"
-	titleScreenCopyright   = "Copyright 2025"
-	titleScreenInstruction = "Press SPACE to start or Q to quit"
+	titleScreenSubtitle  = "A Cyberpunk Roguelike"
+	titleScreenCopyright = "Copyright 2025"
 )
 
 // RenderTitleScreen draws the title screen with ASCII art and instructions.
 func (renderer *EbitenRenderer) RenderTitleScreen(screen *ebiten.Image) {
-	screen.Fill(colorBlack) // Clear screen to black
+	screen.Fill(renderer.color(RoleBackground))
 
 	lineHeight := float64(renderer.tileSize)
 	startY := 5.0 * lineHeight
@@ -27,13 +30,27 @@ func (renderer *EbitenRenderer) RenderTitleScreen(screen *ebiten.Image) {
 	// Draw title ASCII art
 	for i, line := range titleScreenArt {
 		y := startY + float64(i)*lineHeight
-		renderer.centerText(screen, line, y, colorYellow)
+		renderer.centerText(screen, line, y, RoleAccent)
 	}
 
 	metaY := startY + float64(len(titleScreenArt)+3)*lineHeight
-	renderer.centerText(screen, titleScreenSubtitle, metaY, colorWhite)
-	renderer.centerText(screen, titleScreenCopyright, metaY+lineHeight*2, colorWhite)
-
-	instructionsY := float64(renderer.game.Height-3) * lineHeight
-	renderer.centerText(screen, titleScreenInstruction, instructionsY, colorYellow)
+	renderer.centerText(screen, titleScreenSubtitle, metaY, RoleText)
+	renderer.centerText(screen, titleScreenCopyright, metaY+lineHeight*2, RoleText)
+
+	// Draw the menu, each entry clickable and labelled with its keys
+	menuY := float64(renderer.layout.Rows-len(TitleMenu)-2) * lineHeight
+	for i, command := range TitleMenu {
+		item := fmt.Sprintf("[%s] %s", renderer.game.Keymap.Label(command), command.Description())
+		y := menuY + float64(i)*lineHeight
+
+		role := RoleTextDim
+		if i == renderer.game.TitleCursor {
+			item = "> " + item + " <"
+			role = RoleAccent
+		}
+
+		renderer.centerClickable(screen, item, y, role, func() bool {
+			return renderer.game.HandleCommand(command)
+		})
+	}
 }
@@ -3,8 +3,12 @@ package game
 import (
 	"errors"
 	"fmt"
-	"image/color"
+	"image"
+	"io"
+	"io/fs"
+	"math"
 	"os"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
@@ -20,21 +24,44 @@ type EbitenRenderer struct {
 	tileSize     int
 	fontFace     *text.GoTextFace
 	game         *Game
+	layout       ScreenLayout // layout divides the screen into viewport, panel, and log.
+	tileset      *Tileset     // tileset is the sprite sheet for graphical tiles, if any.
+
+	repeatSuppressed bool               // repeatSuppressed blocks held-key repeat until keys are released.
+	cursor           image.Point        // cursor is the mouse position in screen pixels.
+	hovering         bool               // hovering is true while the mouse is over a map tile.
+	hoverTile        Point              // hoverTile is the map tile under the mouse.
+	hotspots         []hotspot          // hotspots are the clickable areas drawn last frame.
+	travelTicks      int                // travelTicks counts ticks since the last travel step.
+	gamepadIDs       []ebiten.GamepadID // gamepadIDs is reused each frame to list connected gamepads.
+	gamepadDirection GamepadInput       // gamepadDirection is the direction held last frame.
+	gamepadHeldTicks int                // gamepadHeldTicks counts ticks the direction has been held.
+	replay           *ReplayPlayer      // replay plays a recorded session instead of taking input, when set.
 }
 
 // NewEbitenRenderer creates a new Ebiten renderer for the given game.
-// Screen dimensions are derived from the game's map size.
-// fontPath specifies the TrueType font file to use and fontSize is in points.
+// Screen dimensions start at the game's map size and follow the window size
+// once Layout is called. fontPath specifies the TrueType font file to use and
+// fontSize is in points; tiles are sized to fit the font.
 // Returns an error if font cannot be loaded.
 func NewEbitenRenderer(game *Game, fontPath string, fontSize float64) (*EbitenRenderer, error) {
-	renderer := &EbitenRenderer{
-		screenWidth:  game.Width * tileSize,
-		screenHeight: game.Height * tileSize,
-		tileSize:     tileSize,
-		game:         game,
+	fontData, err := os.Open(fontPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w", errors.Join(ErrFontNotFound, err))
 	}
 
-	fontData, err := os.Open(fontPath)
+	defer func() {
+		_ = fontData.Close()
+	}()
+
+	return NewEbitenRendererFromReader(game, fontData, fontSize)
+}
+
+// NewEbitenRendererFromFS creates a new Ebiten renderer, loading the font at
+// fontPath from fsys, such as the embedded assets.
+// Returns an error if font cannot be loaded.
+func NewEbitenRendererFromFS(game *Game, fsys fs.FS, fontPath string, fontSize float64) (*EbitenRenderer, error) {
+	fontData, err := fsys.Open(fontPath)
 	if err != nil {
 		return nil, fmt.Errorf("%w", errors.Join(ErrFontNotFound, err))
 	}
@@ -43,6 +70,19 @@ func NewEbitenRenderer(game *Game, fontPath string, fontSize float64) (*EbitenRe
 		_ = fontData.Close()
 	}()
 
+	return NewEbitenRendererFromReader(game, fontData, fontSize)
+}
+
+// NewEbitenRendererFromReader creates a new Ebiten renderer, reading TrueType
+// font data from fontData.
+// Returns an error if font cannot be parsed.
+func NewEbitenRendererFromReader(game *Game, fontData io.Reader, fontSize float64) (*EbitenRenderer, error) {
+	renderer := &EbitenRenderer{
+		tileSize: max(1, int(math.Ceil(fontSize))),
+		game:     game,
+	}
+	renderer.resize(game.Width, game.Height)
+
 	fontSource, err := text.NewGoTextFaceSource(fontData)
 	if err != nil {
 		return nil, fmt.Errorf("%w", errors.Join(ErrFontParseFailed, err))
@@ -53,105 +93,164 @@ func NewEbitenRenderer(game *Game, fontPath string, fontSize float64) (*EbitenRe
 		Size:   fontSize,
 	}
 
+	if err := validateKeymapKeys(game.Keymap); err != nil {
+		return nil, err
+	}
+
 	return renderer, nil
 }
 
 // Update updates the game state. Required by ebiten.Game interface.
 // Returns error if the game should terminate.
 func (renderer *EbitenRenderer) Update() error {
-	// Handle title screen
-	if renderer.game.State == StateTitleScreen {
-		if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
-			renderer.game.StartGame()
+	renderer.applyFontSize()
+
+	// Capture the next key press while rebinding a command
+	if renderer.game.IsRebinding() {
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+			renderer.game.CancelRebind()
+			return nil
 		}
 
-		if ebiten.IsKeyPressed(ebiten.KeyShift) && ebiten.IsKeyPressed(ebiten.KeyQ) {
-			return ebiten.Termination
+		if chord, ok := justPressedChord(); ok {
+			_ = renderer.game.RebindSelected(chord)
 		}
 
 		return nil
 	}
 
-	// Quit
-	if ebiten.IsKeyPressed(ebiten.KeyShift) && ebiten.IsKeyPressed(ebiten.KeyQ) {
-		renderer.game.RequestQuit()
-	}
-
-	// Handle quit confirmation if active
-	if renderer.game.IsConfirmingQuit() {
-		if inpututil.IsKeyJustPressed(ebiten.KeyY) {
-			if renderer.game.ConfirmQuit(true) {
+	// A replay takes the place of the player's input
+	if renderer.replay != nil {
+		for _, command := range renderer.triggeredCommands(ContextReplay) {
+			if renderer.replay.HandleCommand(command) {
 				return ebiten.Termination
 			}
 		}
 
-		if inpututil.IsKeyJustPressed(ebiten.KeyN) {
-			renderer.game.ConfirmQuit(false)
-		}
+		renderer.replay.Update(time.Second / time.Duration(ebiten.TPS()))
 
 		return nil
 	}
 
-	// Up
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) || inpututil.IsKeyJustPressed(ebiten.KeyK) || inpututil.IsKeyJustPressed(ebiten.KeyNumpad8) {
-		renderer.game.MovePlayer(0, -1)
-	}
-
-	// Down
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) || inpututil.IsKeyJustPressed(ebiten.KeyJ) || inpututil.IsKeyJustPressed(ebiten.KeyNumpad2) {
-		renderer.game.MovePlayer(0, 1)
-	}
+	renderer.game.SetViewSize(renderer.layout.ViewportWidth, renderer.layout.ViewportHeight)
 
-	// Left
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) || inpututil.IsKeyJustPressed(ebiten.KeyH) || inpututil.IsKeyJustPressed(ebiten.KeyNumpad4) {
-		renderer.game.MovePlayer(-1, 0)
+	// Stop held-key movement and travel when something needs the player's
+	// attention
+	if renderer.game.TakeInterrupt() {
+		renderer.repeatSuppressed = true
+		renderer.game.CancelTravel()
+		renderer.travelTicks = 0
 	}
 
-	// Right
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) || inpututil.IsKeyJustPressed(ebiten.KeyL) || inpututil.IsKeyJustPressed(ebiten.KeyNumpad6) {
-		renderer.game.MovePlayer(1, 0)
+	if renderer.updateMouse() {
+		return ebiten.Termination
 	}
 
-	// Up left
-	if inpututil.IsKeyJustPressed(ebiten.KeyHome) || inpututil.IsKeyJustPressed(ebiten.KeyY) || inpututil.IsKeyJustPressed(ebiten.KeyNumpad7) {
-		renderer.game.MovePlayer(-1, -1)
-	}
+	context := renderer.game.InputContext()
 
-	// Up right
-	if inpututil.IsKeyJustPressed(ebiten.KeyPageUp) || inpututil.IsKeyJustPressed(ebiten.KeyU) || inpututil.IsKeyJustPressed(ebiten.KeyNumpad9) {
-		renderer.game.MovePlayer(1, -1)
+	// Typed characters go to list hotkeys and text fields
+	if context == ContextMenu || context == ContextText {
+		for _, r := range ebiten.AppendInputChars(nil) {
+			if renderer.game.HandleRune(r) {
+				return ebiten.Termination
+			}
+		}
 	}
 
-	// Down left
-	if inpututil.IsKeyJustPressed(ebiten.KeyEnd) || inpututil.IsKeyJustPressed(ebiten.KeyB) || inpututil.IsKeyJustPressed(ebiten.KeyNumpad1) {
-		renderer.game.MovePlayer(-1, 1)
+	for _, command := range renderer.triggeredCommands(context) {
+		if renderer.game.HandleCommand(command) {
+			return ebiten.Termination
+		}
 	}
 
-	// Down right
-	if inpututil.IsKeyJustPressed(ebiten.KeyPageDown) || inpututil.IsKeyJustPressed(ebiten.KeyN) || inpututil.IsKeyJustPressed(ebiten.KeyNumpad3) {
-		renderer.game.MovePlayer(1, 1)
-	}
+	renderer.updateTravel()
 
 	return nil
 }
 
 // Draw renders the game state to the screen. Required by ebiten.Game interface.
 func (renderer *EbitenRenderer) Draw(screen *ebiten.Image) {
-	screen.Fill(color.Black) // Clear screen to black
+	screen.Fill(renderer.color(RoleBackground))
+	renderer.hotspots = renderer.hotspots[:0]
 
+	renderer.drawState(screen)
+
+	if renderer.replay != nil {
+		renderer.RenderReplayStatus(screen)
+	}
+}
+
+// drawState draws the screen for the current game state.
+func (renderer *EbitenRenderer) drawState(screen *ebiten.Image) {
 	// Show title screen if not playing
 	if renderer.game.State == StateTitleScreen {
 		renderer.RenderTitleScreen(screen)
 		return
 	}
 
+	if renderer.game.State == StateKeyBindings {
+		renderer.RenderKeyBindings(screen)
+		return
+	}
+
+	if renderer.game.State == StateScores {
+		renderer.RenderScores(screen)
+		return
+	}
+
+	if renderer.game.State == StateGameOver {
+		renderer.RenderGameOver(screen)
+		return
+	}
+
+	if renderer.game.State == StateOptions {
+		renderer.RenderOptions(screen)
+		return
+	}
+
+	if renderer.game.State == StateHelp {
+		renderer.RenderHelp(screen)
+		return
+	}
+
 	renderer.RenderMap(screen, renderer.game)
+	renderer.RenderEntities(screen)
 	renderer.RenderPlayer(screen, renderer.game.Player)
+	renderer.RenderHover(screen)
 	renderer.RenderStatsPanel(screen)
 	renderer.RenderMessageLog(screen)
+
+	if renderer.game.State == StateCommandMenu {
+		renderer.RenderCommandMenu(screen)
+	}
+
+	if renderer.game.State == StatePaused {
+		renderer.RenderPauseMenu(screen)
+	}
+
+	if renderer.game.State == StateConsole {
+		renderer.RenderConsole(screen)
+	}
 }
 
 // Layout returns the game's logical screen size. Required by ebiten.Game interface.
+// The screen is one logical pixel per window pixel, so resizing the window
+// shows more or fewer tiles instead of scaling them.
 func (renderer *EbitenRenderer) Layout(outsideWidth, outsideHeight int) (int, int) {
+	renderer.resize(outsideWidth/renderer.tileSize, outsideHeight/renderer.tileSize)
+
 	return renderer.screenWidth, renderer.screenHeight
 }
+
+// resize lays the screen out as columns by rows tiles.
+func (renderer *EbitenRenderer) resize(columns, rows int) {
+	renderer.layout = NewScreenLayout(columns, rows)
+	renderer.screenWidth = renderer.layout.Columns * renderer.tileSize
+	renderer.screenHeight = renderer.layout.Rows * renderer.tileSize
+}
+
+// SetReplay plays player's replay instead of taking input from the player.
+// The renderer should have been created for player.Game.
+func (renderer *EbitenRenderer) SetReplay(player *ReplayPlayer) {
+	renderer.replay = player
+}
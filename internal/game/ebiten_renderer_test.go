@@ -124,3 +124,69 @@ func TestFontParseFailed(t *testing.T) {
 		t.Errorf("expected %v, got %v", ErrFontParseFailed, err)
 	}
 }
+
+func TestLayoutFollowsWindowSize(t *testing.T) {
+	game := NewGame()
+
+	renderer, err := NewEbitenRenderer(game, fontGoMono, tileSize)
+	if err != nil {
+		t.Fatalf("failed to create renderer: %v", err)
+	}
+
+	width, height := renderer.Layout(1280, 800)
+
+	if width != 1280 || height != 800 {
+		t.Errorf("want logical size 1280x800, got %dx%d", width, height)
+	}
+
+	minX, minY, maxX, maxY := renderer.CalculateViewportBounds()
+	if minX < 0 || minY < 0 || maxX > game.Width || maxY > game.Height {
+		t.Errorf("want bounds inside map, got (%d,%d,%d,%d)", minX, minY, maxX, maxY)
+	}
+}
+
+func TestLoadTileset(t *testing.T) {
+	t.Run("loads bundled tileset", func(t *testing.T) {
+		tileset, err := LoadTileset(os.DirFS("../../assets"), "tilesets/neon/tileset.json")
+		if err != nil {
+			t.Fatalf("failed to load tileset: %v", err)
+		}
+
+		if _, ok := tileset.cell(2); !ok {
+			t.Error("want cell 2 inside the sheet, got false")
+		}
+
+		if _, ok := tileset.cell(3); ok {
+			t.Error("want cell 3 outside the sheet, got true")
+		}
+	})
+
+	t.Run("missing index", func(t *testing.T) {
+		_, err := LoadTileset(os.DirFS("../../assets"), "missing.json")
+
+		if !errors.Is(err, ErrTilesetNotFound) {
+			t.Errorf("want %v, got %v", ErrTilesetNotFound, err)
+		}
+	})
+}
+
+func TestNewEbitenRendererFromFS(t *testing.T) {
+	t.Run("loads font from file system", func(t *testing.T) {
+		renderer, err := NewEbitenRendererFromFS(NewGame(), os.DirFS("../../assets"), "fonts/Go-Mono.ttf", tileSize)
+		if err != nil {
+			t.Fatalf("failed to create renderer: %v", err)
+		}
+
+		if renderer.fontFace == nil {
+			t.Error("expected fontFace to be set, got nil")
+		}
+	})
+
+	t.Run("missing font", func(t *testing.T) {
+		_, err := NewEbitenRendererFromFS(NewGame(), os.DirFS("../../assets"), "fonts/missing.ttf", tileSize)
+
+		if !errors.Is(err, ErrFontNotFound) {
+			t.Errorf("expected %v, got %v", ErrFontNotFound, err)
+		}
+	})
+}
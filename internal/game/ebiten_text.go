@@ -2,14 +2,17 @@ package game
 
 import (
 	"image/color"
+	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 )
 
-// renderGlyph draws a single character glyph at the specified position with the given color.
-// This is a helper method used by RenderTile and RenderPlayer.
-func (renderer *EbitenRenderer) renderGlyph(screen *ebiten.Image, glyph rune, tileX, tileY int, color color.Color) {
+// renderGlyph draws a single character glyph at the specified position in the
+// palette color for role.
+// This is a helper method used by RenderTile and RenderPlayer when drawing
+// with the font rather than a tileset.
+func (renderer *EbitenRenderer) renderGlyph(screen *ebiten.Image, glyph rune, tileX, tileY int, role ColorRole) {
 	// Convert tile coordinates to pixel coordinates
 	pixelX := float64(tileX * renderer.tileSize)
 	pixelY := float64(tileY * renderer.tileSize)
@@ -18,24 +21,44 @@ func (renderer *EbitenRenderer) renderGlyph(screen *ebiten.Image, glyph rune, ti
 	glyphString := string(glyph)
 	options := &text.DrawOptions{}
 	options.GeoM.Translate(pixelX, pixelY)
-	options.ColorScale.ScaleWithColor(color)
+	options.ColorScale.ScaleWithColor(renderer.color(role))
 
 	text.Draw(screen, glyphString, renderer.fontFace, options)
 }
 
-// drawText is a helper to render text at pixel coordinates.
-func (renderer *EbitenRenderer) drawText(screen *ebiten.Image, txt string, x, y float64, clr color.Color) {
+// drawText is a helper to render text at pixel coordinates in the palette
+// color for role.
+func (renderer *EbitenRenderer) drawText(screen *ebiten.Image, txt string, x, y float64, role ColorRole) {
 	options := &text.DrawOptions{}
 	options.GeoM.Translate(x, y)
-	options.ColorScale.ScaleWithColor(clr)
+	options.ColorScale.ScaleWithColor(renderer.color(role))
 
 	text.Draw(screen, txt, renderer.fontFace, options)
 }
 
 // centerText measures, centers, and draws at the given Y position.
-func (renderer *EbitenRenderer) centerText(screen *ebiten.Image, txt string, y float64, clr color.Color) {
-	screenWidthPixels := float64(renderer.game.Width * renderer.tileSize)
+func (renderer *EbitenRenderer) centerText(screen *ebiten.Image, txt string, y float64, role ColorRole) {
+	screenWidthPixels := float64(renderer.screenWidth)
 	textWidth := text.Advance(txt, renderer.fontFace)
 	x := (screenWidthPixels - textWidth) / 2.0
-	renderer.drawText(screen, txt, x, y, clr)
+	renderer.drawText(screen, txt, x, y, role)
+}
+
+// color returns the active palette's color for role. An unknown theme falls
+// back to the default palette.
+func (renderer *EbitenRenderer) color(role ColorRole) color.Color {
+	return renderer.game.Palette().Color(role)
+}
+
+// applyFontSize resizes the font and tiles when the font size setting has
+// changed, so the next Layout shows more or fewer tiles.
+func (renderer *EbitenRenderer) applyFontSize() {
+	size := renderer.game.Settings.FontSize
+	if size <= 0 || size == renderer.fontFace.Size {
+		return
+	}
+
+	renderer.fontFace.Size = size
+	renderer.tileSize = max(1, int(math.Ceil(size)))
+	renderer.resize(renderer.screenWidth/renderer.tileSize, renderer.screenHeight/renderer.tileSize)
 }
@@ -0,0 +1,120 @@
+package game
+
+import (
+	"fmt"
+	"image"
+	_ "image/png" // register the PNG decoder for tileset images
+	"io/fs"
+	"path"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Tileset is a loaded sprite sheet used to draw tiles and entities instead of
+// font glyphs.
+type Tileset struct {
+	index   TilesetIndex
+	image   *ebiten.Image
+	columns int
+}
+
+// LoadTileset loads the tileset index at indexPath in fsys and the sprite
+// sheet image it names, which is looked up next to the index. Returns an error
+// if either file is missing or invalid.
+func LoadTileset(fsys fs.FS, indexPath string) (*Tileset, error) {
+	indexFile, err := fsys.Open(indexPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTilesetNotFound, err)
+	}
+
+	defer func() {
+		_ = indexFile.Close()
+	}()
+
+	index, err := LoadTilesetIndex(indexFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", indexPath, err)
+	}
+
+	imageFile, err := fsys.Open(path.Join(path.Dir(indexPath), index.Image))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTilesetNotFound, err)
+	}
+
+	defer func() {
+		_ = imageFile.Close()
+	}()
+
+	sheet, _, err := image.Decode(imageFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTilesetInvalid, err)
+	}
+
+	columns := sheet.Bounds().Dx() / index.TileWidth
+	if columns == 0 || sheet.Bounds().Dy() < index.TileHeight {
+		return nil, fmt.Errorf("%w: image is smaller than one tile", ErrTilesetInvalid)
+	}
+
+	tileset := &Tileset{
+		index:   index,
+		image:   ebiten.NewImageFromImage(sheet),
+		columns: columns,
+	}
+
+	return tileset, nil
+}
+
+// cell returns the sub image for a cell number, or false if the cell is
+// outside the sheet.
+func (tileset *Tileset) cell(cell int) (*ebiten.Image, bool) {
+	x := (cell % tileset.columns) * tileset.index.TileWidth
+	y := (cell / tileset.columns) * tileset.index.TileHeight
+	bounds := image.Rect(x, y, x+tileset.index.TileWidth, y+tileset.index.TileHeight)
+
+	if !bounds.In(tileset.image.Bounds()) {
+		return nil, false
+	}
+
+	return tileset.image.SubImage(bounds).(*ebiten.Image), true
+}
+
+// SetTileset sets the sprite sheet used when graphical tiles are enabled.
+func (renderer *EbitenRenderer) SetTileset(tileset *Tileset) {
+	renderer.tileset = tileset
+}
+
+// renderSprite draws an entity of the given kind at the specified tile
+// coordinates. It uses the tileset when graphical tiles are enabled and the
+// tileset has a cell for it, and falls back to drawing the glyph otherwise.
+func (renderer *EbitenRenderer) renderSprite(screen *ebiten.Image, kind string, glyph rune, tileX, tileY int, role ColorRole) {
+	if renderer.tileset == nil || !renderer.game.Settings.GraphicalTiles {
+		renderer.renderGlyph(screen, glyph, tileX, tileY, role)
+		return
+	}
+
+	cellNumber, ok := renderer.tileset.index.CellFor(kind, glyph)
+	if !ok {
+		renderer.renderGlyph(screen, glyph, tileX, tileY, role)
+		return
+	}
+
+	sprite, ok := renderer.tileset.cell(cellNumber)
+	if !ok {
+		renderer.renderGlyph(screen, glyph, tileX, tileY, role)
+		return
+	}
+
+	// Scale the cell to the tile size so any sheet fits the font grid
+	options := &ebiten.DrawImageOptions{}
+	options.GeoM.Scale(
+		float64(renderer.tileSize)/float64(renderer.tileset.index.TileWidth),
+		float64(renderer.tileSize)/float64(renderer.tileset.index.TileHeight),
+	)
+	options.GeoM.Translate(float64(tileX*renderer.tileSize), float64(tileY*renderer.tileSize))
+
+	if renderer.tileset.index.Tint {
+		options.ColorScale.ScaleWithColor(renderer.color(role))
+	}
+
+	screen.DrawImage(sprite, options)
+}
@@ -0,0 +1,98 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Scroll indicators drawn in a list's border when items are hidden.
+const (
+	scrollUpRune   = '▲'
+	scrollDownRune = '▼'
+)
+
+// drawPanel fills the panel's area and draws its border and title.
+func (renderer *EbitenRenderer) drawPanel(screen *ebiten.Image, panel Panel) {
+	bounds := panel.Bounds
+	size := float32(renderer.tileSize)
+
+	vector.FillRect(screen, float32(bounds.X)*size, float32(bounds.Y)*size, float32(bounds.Width)*size, float32(bounds.Height)*size, renderer.color(RoleBackground), false)
+
+	for y := bounds.Y; y < bounds.Y+bounds.Height; y++ {
+		for x := bounds.X; x < bounds.X+bounds.Width; x++ {
+			if border, ok := panel.BorderRune(x, y); ok {
+				renderer.renderGlyph(screen, border, x, y, RoleAccent)
+			}
+		}
+	}
+
+	if panel.Title != "" {
+		title := fmt.Sprintf(" %s ", panel.Title)
+		renderer.drawText(screen, title, float64((bounds.X+2)*renderer.tileSize), float64(bounds.Y*renderer.tileSize), RoleAccent)
+	}
+}
+
+// drawList draws the visible items of list inside area, one per row, with
+// their hotkeys. Clicking an item runs onClick with its index.
+func (renderer *EbitenRenderer) drawList(screen *ebiten.Image, list *List, area Rect, onClick func(index int) bool) {
+	list.Resize(area.Height)
+	start, end := list.Visible()
+	tileSize := float64(renderer.tileSize)
+
+	for i := start; i < end; i++ {
+		item := list.Items[i]
+		y := float64(area.Y+i-start) * tileSize
+
+		role := RoleTextDim
+		if i == list.Cursor {
+			role = RoleAccent
+			renderer.drawText(screen, ">", float64(area.X)*tileSize, y, role)
+		}
+
+		label := item.Label
+		if item.Hotkey != 0 {
+			label = fmt.Sprintf("%c) %s", item.Hotkey, item.Label)
+		}
+
+		renderer.drawClickable(screen, label, float64(area.X+2)*tileSize, y, role, func() bool {
+			return onClick(i)
+		})
+	}
+
+	right := area.X + area.Width
+	if start > 0 {
+		renderer.renderGlyph(screen, scrollUpRune, right, area.Y-1, RoleAccent)
+	}
+
+	if end < len(list.Items) {
+		renderer.renderGlyph(screen, scrollDownRune, right, area.Y+area.Height, RoleAccent)
+	}
+}
+
+// drawTextInput draws input's prompt and text with a cursor at row y from
+// column x.
+func (renderer *EbitenRenderer) drawTextInput(screen *ebiten.Image, input TextInput, x, y int) {
+	line := fmt.Sprintf("%s%s_", input.Prompt, input.Value())
+	renderer.drawText(screen, line, float64(x*renderer.tileSize), float64(y*renderer.tileSize), RoleText)
+}
+
+// drawDialog draws dialog's question and the keys that answer it inside
+// area.
+func (renderer *EbitenRenderer) drawDialog(screen *ebiten.Image, dialog Dialog, area Rect) {
+	tileSize := float64(renderer.tileSize)
+	keymap := renderer.game.Keymap
+	answers := fmt.Sprintf("[%s] Yes  [%s] No", keymap.Label(CommandConfirm), keymap.Label(CommandCancel))
+
+	renderer.drawText(screen, dialog.Question, float64(area.X)*tileSize, float64(area.Y)*tileSize, RoleWarning)
+	renderer.drawText(screen, answers, float64(area.X)*tileSize, float64(area.Y+2)*tileSize, RoleTextDim)
+}
+
+// drawTooltip draws tooltip as a small panel.
+func (renderer *EbitenRenderer) drawTooltip(screen *ebiten.Image, tooltip Tooltip) {
+	renderer.drawPanel(screen, Panel{Bounds: tooltip.Bounds})
+
+	inner := tooltip.Bounds.Inner()
+	renderer.drawText(screen, tooltip.Text, float64(inner.X*renderer.tileSize), float64(inner.Y*renderer.tileSize), RoleText)
+}
@@ -0,0 +1,110 @@
+package game
+
+import "slices"
+
+// EntityKind distinguishes creatures from things lying on the map.
+type EntityKind string
+
+const (
+	// EntityMonster is a creature that can act and be fought.
+	EntityMonster EntityKind = "monster"
+
+	// EntityItem is an object that can be picked up.
+	EntityItem EntityKind = "item"
+)
+
+// Disposition is whether a creature attacks the player. Only a creature's
+// disposition decides this; its color is only how it is drawn.
+type Disposition string
+
+const (
+	// DispositionHostile is a creature that attacks the player. A creature
+	// with no disposition is hostile.
+	DispositionHostile Disposition = "hostile"
+
+	// DispositionFriendly is a creature that helps or ignores the player.
+	DispositionFriendly Disposition = "friendly"
+)
+
+// Entity is a creature or item on the current level.
+type Entity struct {
+	Kind        EntityKind  `json:"kind"`                  // Kind is whether the entity is a monster or an item.
+	ID          string      `json:"id,omitempty"`          // ID is the content definition the entity was made from, if any.
+	Name        string      `json:"name"`                  // Name is shown when the entity is examined.
+	Glyph       rune        `json:"glyph"`                 // Glyph is the rune used to render the entity.
+	Color       ColorRole   `json:"color"`                 // Color is the palette role used to render the entity.
+	Disposition Disposition `json:"disposition,omitempty"` // Disposition is whether a monster attacks the player.
+	X           int         `json:"x"`                     // X is the entity's horizontal position in tile coordinates.
+	Y           int         `json:"y"`                     // Y is the entity's vertical position in tile coordinates.
+}
+
+// Hostile returns true if the entity is a creature that attacks the player.
+func (entity Entity) Hostile() bool {
+	return entity.Kind == EntityMonster && entity.Disposition != DispositionFriendly
+}
+
+// Friendly returns true if the entity is a creature that helps or ignores
+// the player.
+func (entity Entity) Friendly() bool {
+	return entity.Kind == EntityMonster && entity.Disposition == DispositionFriendly
+}
+
+// EntityAt returns the entity at (x, y). Monsters are returned before items
+// when both share a tile.
+func (game *Game) EntityAt(x, y int) (*Entity, bool) {
+	found := -1
+
+	for i, entity := range game.Entities {
+		if entity.X != x || entity.Y != y {
+			continue
+		}
+
+		if entity.Kind == EntityMonster {
+			return &game.Entities[i], true
+		}
+
+		if found < 0 {
+			found = i
+		}
+	}
+
+	if found < 0 {
+		return nil, false
+	}
+
+	return &game.Entities[found], true
+}
+
+// RemoveEntity takes entity, as returned by EntityAt, off the current level.
+func (game *Game) RemoveEntity(entity *Entity) {
+	for i := range game.Entities {
+		if &game.Entities[i] == entity {
+			game.Entities = slices.Delete(game.Entities, i, i+1)
+			return
+		}
+	}
+}
+
+// SpawnEntity adds entity to the current level.
+func (game *Game) SpawnEntity(entity Entity) {
+	game.Entities = append(game.Entities, entity)
+}
+
+// freeTileNear returns a walkable tile next to (x, y) with nothing on it.
+// Returns false if every neighbor is blocked.
+func (game *Game) freeTileNear(x, y int) (int, int, bool) {
+	for _, direction := range neighborOffsets {
+		nx, ny := x+direction.X, y+direction.Y
+		if !game.InBounds(nx, ny) || !game.Tiles[ny][nx].Walkable {
+			continue
+		}
+
+		if _, taken := game.EntityAt(nx, ny); taken {
+			continue
+		}
+
+		return nx, ny, true
+	}
+
+	return 0, 0, false
+}
@@ -0,0 +1,39 @@
+package game
+
+import "testing"
+
+func TestEntityAt(t *testing.T) {
+	game := NewGame()
+	game.SpawnEntity(Entity{Kind: EntityItem, Name: "credstick", X: 12, Y: 9})
+	game.SpawnEntity(Entity{Kind: EntityMonster, Name: "ganger", X: 12, Y: 9})
+	game.SpawnEntity(Entity{Kind: EntityItem, Name: "medkit", X: 13, Y: 9})
+
+	tests := []struct {
+		name   string
+		x, y   int
+		want   string
+		wantOK bool
+	}{
+		{name: "monster before item", x: 12, y: 9, want: "ganger", wantOK: true},
+		{name: "item alone", x: 13, y: 9, want: "medkit", wantOK: true},
+		{name: "empty tile", x: 14, y: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entity, ok := game.EntityAt(tt.x, tt.y)
+
+			if ok != tt.wantOK {
+				t.Fatalf("want found %v, got %v", tt.wantOK, ok)
+			}
+
+			if ok && entity.Name != tt.want {
+				t.Errorf("want %q, got %q", tt.want, entity.Name)
+			}
+		})
+	}
+
+	if got := game.Describe(13, 9); got != "You see a medkit." {
+		t.Errorf("want entity described, got %q", got)
+	}
+}
@@ -3,6 +3,32 @@ package game
 import "errors"
 
 var (
-	ErrFontNotFound    = errors.New("font file not found")
-	ErrFontParseFailed = errors.New("font file could not be parsed")
+	ErrFontNotFound          = errors.New("font file not found")
+	ErrFontParseFailed       = errors.New("font file could not be parsed")
+	ErrUnknownCommand        = errors.New("unknown command")
+	ErrInvalidChord          = errors.New("invalid key chord")
+	ErrUnknownKey            = errors.New("unknown key")
+	ErrKeyBindingConflict    = errors.New("key binding conflict")
+	ErrKeymapParseFailed     = errors.New("key bindings could not be parsed")
+	ErrLastKeyBinding        = errors.New("a command's last key cannot be removed")
+	ErrSettingsParseFailed   = errors.New("settings could not be parsed")
+	ErrInvalidSetting        = errors.New("invalid setting")
+	ErrTilesetNotFound       = errors.New("tileset not found")
+	ErrTilesetInvalid        = errors.New("tileset is invalid")
+	ErrUnknownTheme          = errors.New("unknown theme")
+	ErrMorgueDisabled        = errors.New("no morgue directory is set")
+	ErrMorgueExists          = errors.New("morgue file names are all taken")
+	ErrScoresParseFailed     = errors.New("failed to parse score table")
+	ErrSaveDisabled          = errors.New("no save file is set")
+	ErrSaveInvalid           = errors.New("save file is invalid")
+	ErrUnknownConsoleCommand = errors.New("unknown console command")
+	ErrConsoleUsage          = errors.New("invalid console command arguments")
+	ErrDumpDisabled          = errors.New("no dump directory is set")
+	ErrReplayInvalid         = errors.New("replay file is invalid")
+	ErrReplayMismatch        = errors.New("replay ended in a different state")
+	ErrContentNotFound       = errors.New("content definitions not found")
+	ErrContentParseFailed    = errors.New("content definitions could not be parsed")
+	ErrContentInvalid        = errors.New("content definition is invalid")
+	ErrScriptFailed          = errors.New("script failed")
+	ErrUnknownGenerator      = errors.New("unknown level generator")
 )
@@ -0,0 +1,103 @@
+package game
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// EventKind names something that happened in the game.
+type EventKind string
+
+const (
+	// EventRunStarted is emitted when a run begins.
+	EventRunStarted EventKind = "run_started"
+
+	// EventMoved is emitted when an entity moves to a new tile.
+	EventMoved EventKind = "moved"
+
+	// EventAttacked is emitted when an entity attacks another.
+	EventAttacked EventKind = "attacked"
+
+	// EventItemPickedUp is emitted when an entity picks up an item.
+	EventItemPickedUp EventKind = "item_picked_up"
+
+	// EventLevelEntered is emitted when the player enters a level.
+	EventLevelEntered EventKind = "level_entered"
+
+	// EventDied is emitted when an entity dies.
+	EventDied EventKind = "died"
+)
+
+// eventKinds lists every event kind, used to check quest triggers.
+var eventKinds = []EventKind{
+	EventRunStarted, EventMoved, EventAttacked, EventItemPickedUp, EventLevelEntered, EventDied,
+}
+
+// Event is a structured record of something that happened. Fields that do
+// not apply to the kind are left empty.
+type Event struct {
+	Kind   EventKind `json:"kind"`             // Kind is what happened.
+	Turn   int       `json:"turn"`             // Turn is the turn on which it happened.
+	Actor  string    `json:"actor,omitempty"`  // Actor is who did it.
+	Target string    `json:"target,omitempty"` // Target is who or what it was done to.
+	X      int       `json:"x"`                // X is where it happened.
+	Y      int       `json:"y"`                // Y is where it happened.
+	Damage int       `json:"damage,omitempty"` // Damage is the damage dealt by an attack.
+	Item   string    `json:"item,omitempty"`   // Item is the item picked up.
+	Depth  int       `json:"depth,omitempty"`  // Depth is the level entered.
+	Cause  string    `json:"cause,omitempty"`  // Cause is what killed the entity.
+	Seed   int64     `json:"seed,omitempty"`   // Seed is the seed of the run that started.
+}
+
+// EventBus delivers events to every subscriber in the order they
+// subscribed.
+type EventBus struct {
+	subscribers []func(Event) // subscribers are called for every published event.
+}
+
+// Subscribe registers handler to receive every published event.
+func (bus *EventBus) Subscribe(handler func(Event)) {
+	bus.subscribers = append(bus.subscribers, handler)
+}
+
+// Publish delivers event to every subscriber.
+func (bus *EventBus) Publish(event Event) {
+	for _, handler := range bus.subscribers {
+		handler(event)
+	}
+}
+
+// emit stamps event with the current turn, publishes it, and runs the
+// quests it triggers.
+func (game *Game) emit(event Event) {
+	event.Turn = game.TurnCount
+	game.Events.Publish(event)
+	game.runQuests(event)
+}
+
+// Telemetry writes events as JSON lines, one object per event, for analysis
+// across many runs.
+type Telemetry struct {
+	encoder *json.Encoder // encoder writes each event as one line.
+	err     error         // err is the first write error.
+}
+
+// NewTelemetry creates a telemetry writer that writes to writer.
+func NewTelemetry(writer io.Writer) *Telemetry {
+	return &Telemetry{encoder: json.NewEncoder(writer)}
+}
+
+// Record writes event as a JSON line. After a write fails later events are
+// dropped; the error is available from Err.
+func (telemetry *Telemetry) Record(event Event) {
+	if telemetry.err != nil {
+		return
+	}
+
+	telemetry.err = telemetry.encoder.Encode(event)
+}
+
+// Err returns the first error that occurred while writing events.
+func (telemetry *Telemetry) Err() error {
+	return telemetry.err
+}
@@ -0,0 +1,104 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEventBus(t *testing.T) {
+	var bus EventBus
+	var order []string
+
+	bus.Subscribe(func(event Event) { order = append(order, "first:"+string(event.Kind)) })
+	bus.Subscribe(func(event Event) { order = append(order, "second:"+string(event.Kind)) })
+
+	bus.Publish(Event{Kind: EventMoved})
+
+	want := "first:moved,second:moved"
+	if got := strings.Join(order, ","); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
+
+func TestGameEvents(t *testing.T) {
+	tests := []struct {
+		name  string
+		act   func(game *Game)
+		kinds []EventKind
+	}{
+		{name: "start game", act: func(game *Game) { game.StartGame() }, kinds: []EventKind{EventRunStarted, EventLevelEntered}},
+		{name: "move", act: func(game *Game) { game.MovePlayer(1, 0) }, kinds: []EventKind{EventMoved}},
+		{name: "bump into wall", act: func(game *Game) { game.MovePlayer(0, -10) }, kinds: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := NewGame()
+
+			var kinds []EventKind
+			game.Events.Subscribe(func(event Event) { kinds = append(kinds, event.Kind) })
+
+			tt.act(game)
+
+			if len(kinds) != len(tt.kinds) {
+				t.Fatalf("want events %v, got %v", tt.kinds, kinds)
+			}
+
+			for i := range kinds {
+				if kinds[i] != tt.kinds[i] {
+					t.Errorf("want events %v, got %v", tt.kinds, kinds)
+				}
+			}
+		})
+	}
+}
+
+func TestTelemetry(t *testing.T) {
+	var output bytes.Buffer
+
+	game := NewGame()
+	telemetry := NewTelemetry(&output)
+	game.Events.Subscribe(telemetry.Record)
+
+	game.StartGame()
+	game.MovePlayer(1, 0)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("want 3 lines, got %d: %q", len(lines), output.String())
+	}
+
+	var moved Event
+	if err := json.Unmarshal([]byte(lines[2]), &moved); err != nil {
+		t.Fatalf("want JSON line, got %v", err)
+	}
+
+	if moved.Kind != EventMoved || moved.X != 18 || moved.Y != 9 {
+		t.Errorf("want moved to 18,9, got %+v", moved)
+	}
+}
+
+func TestTelemetryWriteError(t *testing.T) {
+	telemetry := NewTelemetry(failingWriter{})
+
+	telemetry.Record(Event{Kind: EventMoved})
+	telemetry.Record(Event{Kind: EventMoved})
+
+	if !errors.Is(telemetry.Err(), errWriteFailed) {
+		t.Errorf("want %v, got %v", errWriteFailed, telemetry.Err())
+	}
+}
+
+// errWriteFailed is returned by failingWriter.
+var errWriteFailed = errors.New("write failed")
+
+// failingWriter is an io.Writer whose writes always fail.
+type failingWriter struct{}
+
+// Write returns errWriteFailed.
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errWriteFailed
+}
@@ -1,7 +1,13 @@
 // Package game contains core game state and logic independent of rendering.
 package game
 
-import "image/color"
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
 
 const (
 	mapWidth  = 80
@@ -17,19 +23,94 @@ const (
 
 	// StatePlaying represents the playing state.
 	StatePlaying
+
+	// StateKeyBindings represents the key binding screen.
+	StateKeyBindings
+
+	// StateCommandMenu represents the command menu shown over the map.
+	StateCommandMenu
+
+	// StateScores represents the high score screen.
+	StateScores
+
+	// StateGameOver represents the screen shown after the player dies.
+	StateGameOver
+
+	// StatePaused represents the pause menu shown over the map.
+	StatePaused
+
+	// StateOptions represents the options screen.
+	StateOptions
+
+	// StateHelp represents the help screen.
+	StateHelp
+
+	// StateConsole represents the wizard console shown over the map.
+	StateConsole
 )
 
+// TitleMenu lists the commands offered on the title screen.
+var TitleMenu = []Command{CommandStartGame, CommandScores, CommandHelp, CommandKeyBindings, CommandQuit}
+
 // Game holds the current game state including map and entities.
 type Game struct {
-	Width          int       // Width describes the horizontal map dimensions in tiles
-	Height         int       // Height describes the vertical map dimensions in tiles
-	Tiles          [][]Tile  // Tiles is a 2D grid of map tiles indexed as Tiles[y][x]
-	Player         Player    // Player represents the runner controlled by the user
-	CameraX        int       // CameraX is the camera's center position (horizontal)
-	CameraY        int       // CameraY is the camera's center position (vertical)
-	TurnCount      int       // TurnCount tracks the number of turns that have elapsed.
-	confirmingQuit bool      // confirmingQuit tracks whether the game is waiting for quit confirmation.
-	State          GameState // State tracks the current game state (title screen, playing, etc.)
+	Width        int               // Width describes the horizontal map dimensions in tiles
+	Height       int               // Height describes the vertical map dimensions in tiles
+	Tiles        [][]Tile          // Tiles is a 2D grid of map tiles indexed as Tiles[y][x]
+	Player       Player            // Player represents the runner controlled by the user
+	CameraX      int               // CameraX is the camera's center position (horizontal)
+	CameraY      int               // CameraY is the camera's center position (vertical)
+	TurnCount    int               // TurnCount tracks the number of turns that have elapsed.
+	Depth        int               // Depth is the current level, starting at 1.
+	State        GameState         // State tracks the current game state (title screen, playing, etc.)
+	Keymap       Keymap            // Keymap maps key chords to the commands they trigger.
+	KeymapPath   string            // KeymapPath is where rebound keys are saved; empty disables saving.
+	KeyBindings  KeyBindingsScreen // KeyBindings holds the key binding screen state.
+	Settings     Settings          // Settings holds presentation and control preferences.
+	Messages     []Message         // Messages is the message log, oldest first.
+	interrupted  bool              // interrupted is set when held-key movement should stop.
+	viewWidth    int               // viewWidth is the map viewport's width in tiles; 0 until a renderer sets it.
+	viewHeight   int               // viewHeight is the map viewport's height in tiles; 0 until a renderer sets it.
+	hostilesSeen map[Point]bool    // hostilesSeen holds where hostiles were in view at the end of the last turn.
+	travelPath   []Point           // travelPath holds the remaining steps of a mouse travel route.
+	TitleCursor  int               // TitleCursor is the selected title screen menu entry.
+	CommandMenu  List              // CommandMenu lists the commands in the command menu.
+	Narrator     io.Writer         // Narrator receives turn summaries in screen reader mode; nil discards them.
+	Seed         int64             // Seed is the seed of the random number generator, recorded so runs can be reproduced.
+	rngSource    *rand.PCG         // rngSource is the state of rng, kept so it can be saved.
+	rng          *rand.Rand        // rng is the game's random number generator.
+	Events       EventBus          // Events delivers structured game events to subscribers.
+	MorgueDir    string            // MorgueDir is where character dumps are written; empty disables them.
+	Scores       ScoreTable        // Scores is the history of finished runs.
+	ScoresPath   string            // ScoresPath is where the score table is saved; empty disables saving.
+	ScoresScreen ScoresScreen      // ScoresScreen holds the scores screen state.
+	GameOver     GameOverScreen    // GameOver holds the game over screen state.
+	lastDamage   string            // lastDamage names what last hurt the player, reported as the cause of death.
+	Pause        PauseScreen       // Pause holds the pause menu state.
+	Options      OptionsScreen     // Options holds the options screen state.
+	SavePath     string            // SavePath is where the run is saved; empty disables saving.
+	SettingsPath string            // SettingsPath is where changed options are saved; empty disables saving.
+	Help         HelpScreen        // Help holds the help screen state.
+	Entities     []Entity          // Entities are the monsters and items on the current level.
+	Content      *Content          // Content holds the tile, monster, item, cyberware, and spell definitions.
+	Flags        map[string]string // Flags are values scripts store for the rest of the run.
+	Wizard       bool              // Wizard enables the debug console; runs played with it are flagged in the scores.
+	GodMode      bool              // GodMode stops the player taking damage; toggled from the wizard console.
+	Revealed     bool              // Revealed shows the whole level whatever the player can see; toggled from the wizard console.
+	Console      ConsoleScreen     // Console holds the wizard console state.
+	DumpDir      string            // DumpDir is where the wizard console writes state dumps; empty disables them.
+	Recording    *Replay           // Recording receives every input when set, so the session can be replayed.
+	savedRun     []byte            // savedRun is a replay's save, continued instead of reading SavePath.
+	History      *History          // History holds turn snapshots for rewinding in wizard mode; nil when off.
+	Generator    Generator         // Generator lays out new levels; empty uses the room generator.
+}
+
+// KeyBindingsScreen holds the state of the key binding screen.
+type KeyBindingsScreen struct {
+	Cursor      int       // Cursor is the index of the selected command in Commands().
+	Rebinding   bool      // Rebinding is true while waiting for a key to bind.
+	Status      string    // Status is feedback about the last rebind attempt.
+	returnState GameState // returnState is the state to go back to when the screen closes.
 }
 
 // NewGame creates a new Game with three rooms connected by corridors.
@@ -40,34 +121,73 @@ func NewGame() *Game {
 		Height: mapHeight,
 		Tiles:  make([][]Tile, mapHeight),
 		Player: Player{
-			Glyph:  '@',
-			Color:  color.White,
-			Name:   "Decker",
-			Level:  1,
-			Health: 100,
+			Glyph:     '@',
+			Color:     RolePlayer,
+			Name:      "Decker",
+			Archetype: "Decker",
+			Level:     1,
+			Health:    maxHealth,
 		},
-		State: StateTitleScreen,
+		Depth:    1,
+		State:    StateTitleScreen,
+		Keymap:   DefaultKeymap(),
+		Settings: DefaultSettings(),
+		Content:  NewContent(),
 	}
 
+	game.SetSeed(time.Now().UnixNano())
 	game.initializeMap(mapWidth, mapHeight)
 
 	return game
 }
 
-// initializeMap creates 3 hardcoded rooms with corridors and starts the player
-// in the center of room 1.
+// SetSeed reseeds the game's random number generator so the same seed gives
+// the same run.
+func (game *Game) SetSeed(seed int64) {
+	game.Seed = seed
+	game.rngSource = rand.NewPCG(uint64(seed), 0)
+	game.rng = rand.New(game.rngSource)
+}
+
+// SetContent replaces the game's definitions and rebuilds the map from
+// them. Call it before the run starts.
+func (game *Game) SetContent(content *Content) {
+	game.Content = content
+	game.Tiles = make([][]Tile, game.Height)
+	game.initializeMap(game.Width, game.Height)
+}
+
+// initializeMap fills the map with walls, then lays out the level with the
+// game's generator and centers the camera on the player.
 func (game *Game) initializeMap(width, height int) {
+	game.Entities = nil
+
 	// Initialize all tiles as walls
 	for y := range height {
 		row := make([]Tile, width)
 
 		for x := range width {
-			row[x] = WallTile
+			row[x] = game.Content.Tile(TileWall)
 		}
 
 		game.Tiles[y] = row
 	}
 
+	generate, ok := generators[game.Generator]
+	if !ok {
+		generate = (*Game).generateRooms
+	}
+
+	generate(game, width, height)
+
+	// Center camera on player
+	game.CameraX = game.Player.X
+	game.CameraY = game.Player.Y
+}
+
+// generateRooms creates 3 hardcoded rooms with corridors and starts the
+// player in the center of room 1. The rooms fit an 80x24 map.
+func (game *Game) generateRooms(width, height int) {
 	// Create rooms
 	game.CreateRoom(10, 5, 15, 8)
 	game.CreateRoom(35, 3, 12, 10)
@@ -77,13 +197,14 @@ func (game *Game) initializeMap(width, height int) {
 	game.CreateCorridor(17, 9, 41, 8)
 	game.CreateCorridor(41, 8, 64, 16)
 
-	// Start player in center of first room
+	// Start player in center of first room, clearing anything a prefab put
+	// there
 	game.Player.X = 17
 	game.Player.Y = 9
 
-	// Center camera on player
-	game.CameraX = game.Player.X
-	game.CameraY = game.Player.Y
+	if entity, ok := game.EntityAt(game.Player.X, game.Player.Y); ok {
+		game.RemoveEntity(entity)
+	}
 }
 
 // MovePlayer attempts to move the player by (dx, dy). The move only succeeds
@@ -106,60 +227,441 @@ func (game *Game) MovePlayer(dx, dy int) {
 		return
 	}
 
+	// Bumping into a monster attacks it instead of moving.
+	if entity, ok := game.EntityAt(newX, newY); ok && entity.Kind == EntityMonster {
+		game.attack(entity)
+		return
+	}
+
 	game.Player.X = newX
 	game.Player.Y = newY
 
 	game.CameraX = newX
 	game.CameraY = newY
+
+	game.emit(Event{Kind: EventMoved, Actor: game.Player.Name, X: newX, Y: newY})
+	game.enterTile(newX, newY)
 }
 
-// CreateRoom creates a room at x, y with the specified dimensions.
+// CreateRoom creates a room at x, y with the specified dimensions. When the
+// content defines prefabs, one that fits may be stamped inside it instead of
+// leaving the room empty.
 func (game *Game) CreateRoom(x, y, width, height int) {
 	for yPos := y; yPos < y+height; yPos++ {
 		for xPos := x; xPos < x+width; xPos++ {
-			game.Tiles[yPos][xPos] = FloorTile
+			game.Tiles[yPos][xPos] = game.Content.Tile(TileFloor)
 		}
 	}
+
+	game.furnishRoom(x, y, width, height)
 }
 
 // CreateCorridor creates a corridor between two points horizontally then vertically.
 func (game *Game) CreateCorridor(x1, y1, x2, y2 int) {
 	// Horizontal segment
 	for x := min(x1, x2); x <= max(x1, x2); x++ {
-		game.Tiles[y1][x] = FloorTile
+		game.Tiles[y1][x] = game.Content.Tile(TileFloor)
 	}
 
 	// Vertical segment
 	for y := min(y1, y2); y <= max(y1, y2); y++ {
-		game.Tiles[y][x2] = FloorTile
+		game.Tiles[y][x2] = game.Content.Tile(TileFloor)
 	}
 }
 
-// RequestQuit enters the quit confirmation state.
-func (game *Game) RequestQuit() {
-	game.confirmingQuit = true
-}
+// EndRun records a finished run in the score table, writes its morgue file,
+// and deletes its save. ending describes how the run ended. Failures are
+// reported in the message log so the run still ends.
+func (game *Game) EndRun(ending string) {
+	if err := game.DeleteSave(); err != nil {
+		game.AddImportantMessage(fmt.Sprintf("Deleting the save failed: %v", err))
+	}
 
-// IsConfirmingQuit returns true if the game is waiting for quit confirmation.
-func (game *Game) IsConfirmingQuit() bool {
-	return game.confirmingQuit
-}
+	if err := game.RecordRun(ending); err != nil {
+		game.AddImportantMessage(fmt.Sprintf("Saving the score failed: %v", err))
+	}
 
-// ConfirmQuit handles the user's response to the quit confirmation.
-// Returns true if the game should exit, false otherwise.
-func (game *Game) ConfirmQuit(confirmed bool) bool {
-	game.confirmingQuit = false
+	if game.MorgueDir == "" {
+		return
+	}
 
-	return confirmed
+	if _, err := game.WriteMorgue(ending); err != nil {
+		game.AddImportantMessage(fmt.Sprintf("Writing the morgue failed: %v", err))
+	}
 }
 
-// Tick advances the game state by one turn.
+// Tick advances the game state by one turn and ends the run if the player
+// has died.
 // This is called once per player action to process the game.
 func (game *Game) Tick() {
 	game.TurnCount++
+	game.noticeHostiles()
+	game.narrateTurn()
+	game.recordHistory()
+	game.checkDeath()
 }
 
-// StartGame transitions from the title screen to playing state.
+// StartGame transitions from the title screen to playing state, continuing
+// the saved run if there is one.
 func (game *Game) StartGame() {
 	game.State = StatePlaying
+
+	restored, err := game.LoadSavedGame()
+	if err != nil {
+		game.AddImportantMessage(fmt.Sprintf("Loading the saved game failed: %v", err))
+	}
+
+	if restored {
+		game.AddMessage(fmt.Sprintf("Welcome back, %s.", game.Player.Name))
+		return
+	}
+
+	game.emit(Event{Kind: EventRunStarted, Actor: game.Player.Name, X: game.Player.X, Y: game.Player.Y, Seed: game.Seed})
+	game.emit(Event{Kind: EventLevelEntered, Actor: game.Player.Name, X: game.Player.X, Y: game.Player.Y, Depth: game.Depth})
+}
+
+// InputContext returns the input context for the current game state, used to
+// decide which key bindings are active.
+func (game *Game) InputContext() InputContext {
+	switch {
+	case game.State == StateTitleScreen:
+		return ContextTitle
+	case game.State == StatePaused && game.Pause.Confirming:
+		return ContextPrompt
+	case game.State == StateConsole:
+		return ContextText
+	case game.State != StatePlaying:
+		return ContextMenu
+	default:
+		return ContextPlaying
+	}
+}
+
+// HandleCommand applies a command to the game according to the current state.
+// Returns true if the game should exit.
+func (game *Game) HandleCommand(command Command) bool {
+	game.record(ReplayStep{Kind: StepCommand, Command: command.String()})
+
+	return game.handleCommand(command)
+}
+
+// handleCommand applies a command without recording it, so commands run by
+// other commands are not recorded twice.
+func (game *Game) handleCommand(command Command) bool {
+	switch game.InputContext() {
+	case ContextTitle:
+		switch command {
+		case CommandStartGame:
+			game.StartGame()
+		case CommandKeyBindings:
+			game.OpenKeyBindings()
+		case CommandScores:
+			game.OpenScores()
+		case CommandHelp:
+			game.OpenHelp()
+		case CommandQuit:
+			return true
+		case CommandMenuUp:
+			game.TitleCursor = wrapCursor(game.TitleCursor, -1, len(TitleMenu))
+		case CommandMenuDown:
+			game.TitleCursor = wrapCursor(game.TitleCursor, 1, len(TitleMenu))
+		case CommandConfirm:
+			return game.handleCommand(TitleMenu[game.TitleCursor])
+		case CommandToggleTiles:
+			game.ToggleGraphicalTiles()
+		case CommandFontLarger:
+			game.AdjustFontSize(fontSizeStep)
+		case CommandFontSmaller:
+			game.AdjustFontSize(-fontSizeStep)
+		}
+
+	case ContextMenu:
+		if game.State == StateCommandMenu {
+			return game.handleCommandMenuResult(game.CommandMenu.HandleCommand(command))
+		}
+
+		if game.State == StateScores {
+			game.handleScoresCommand(command)
+			return false
+		}
+
+		if game.State == StateGameOver {
+			game.handleGameOverCommand(command)
+			return false
+		}
+
+		if game.State == StatePaused {
+			return game.handlePauseCommand(command)
+		}
+
+		if game.State == StateOptions {
+			game.handleOptionsCommand(command)
+			return false
+		}
+
+		if game.State == StateHelp {
+			game.handleHelpCommand(command)
+			return false
+		}
+
+		game.handleKeyBindingsCommand(command)
+
+	case ContextPrompt:
+		game.handleAbandonPrompt(command)
+
+	case ContextText:
+		game.handleConsoleCommand(command)
+
+	case ContextPlaying:
+		// Any key press takes over from mouse travel
+		game.CancelTravel()
+
+		switch command {
+		case CommandQuit:
+			game.OpenPauseMenu(pauseSaveAndQuit)
+			return false
+		case CommandPause:
+			game.OpenPauseMenu(pauseResume)
+			return false
+		case CommandCommandMenu:
+			game.OpenCommandMenu()
+			return false
+		case CommandToggleTiles:
+			game.ToggleGraphicalTiles()
+			return false
+		case CommandFontLarger:
+			game.AdjustFontSize(fontSizeStep)
+			return false
+		case CommandFontSmaller:
+			game.AdjustFontSize(-fontSizeStep)
+			return false
+		case CommandMorgue:
+			game.DumpCharacter()
+			return false
+		case CommandHelp:
+			game.OpenHelp()
+			return false
+		case CommandConsole:
+			game.OpenConsole()
+			return false
+		case CommandRewind:
+			game.Rewind()
+			return false
+		case CommandFastForward:
+			game.FastForward()
+			return false
+		case CommandUseItem:
+			game.UseItem()
+			return false
+		}
+
+		if dx, dy, ok := command.moveDelta(); ok {
+			game.MovePlayer(dx, dy)
+		}
+	}
+
+	return false
+}
+
+// OpenKeyBindings shows the key binding screen. Closing it returns to the
+// current state.
+func (game *Game) OpenKeyBindings() {
+	game.KeyBindings = KeyBindingsScreen{returnState: game.State}
+	game.State = StateKeyBindings
+}
+
+// handleKeyBindingsCommand moves the selection on the key binding screen,
+// starts rebinding the selected command, or closes the screen.
+func (game *Game) handleKeyBindingsCommand(command Command) {
+	commands := Commands()
+
+	switch command {
+	case CommandMenuUp:
+		game.KeyBindings.Cursor = wrapCursor(game.KeyBindings.Cursor, -1, len(commands))
+	case CommandMenuDown:
+		game.KeyBindings.Cursor = wrapCursor(game.KeyBindings.Cursor, 1, len(commands))
+	case CommandConfirm:
+		game.KeyBindings.Rebinding = true
+		game.KeyBindings.Status = fmt.Sprintf("Press a key to add to %s or one of its keys to remove, Esc to cancel", commands[game.KeyBindings.Cursor].Description())
+	case CommandCancel:
+		game.State = game.KeyBindings.returnState
+	}
+}
+
+// IsRebinding returns true if the key binding screen is waiting for a key.
+func (game *Game) IsRebinding() bool {
+	return game.State == StateKeyBindings && game.KeyBindings.Rebinding
+}
+
+// RebindSelected adds chord to the keys of the command selected on the key
+// binding screen, or removes it if the command is already bound to it, so
+// the command's other keys are kept. The keymap is left unchanged and an
+// error is returned if the chord conflicts with another command or is the
+// command's last key. On success the keymap is saved to KeymapPath when one
+// is set.
+func (game *Game) RebindSelected(chord string) error {
+	game.record(ReplayStep{Kind: StepRebind, Text: chord})
+
+	game.KeyBindings.Rebinding = false
+
+	canonical, err := NormalizeChord(chord)
+	if err != nil {
+		game.KeyBindings.Status = err.Error()
+		return err
+	}
+
+	command := Commands()[game.KeyBindings.Cursor]
+
+	keymap := game.Keymap.Clone()
+	status := fmt.Sprintf("%s bound to %s", command.Description(), ChordLabel(canonical))
+
+	if slices.Contains(keymap[command], canonical) {
+		if len(keymap[command]) == 1 {
+			err := fmt.Errorf("%w: %s is the only key for %s", ErrLastKeyBinding, ChordLabel(canonical), command.Description())
+			game.KeyBindings.Status = err.Error()
+			return err
+		}
+
+		keymap[command] = slices.DeleteFunc(keymap[command], func(bound string) bool { return bound == canonical })
+		status = fmt.Sprintf("%s no longer bound to %s", command.Description(), ChordLabel(canonical))
+	} else {
+		keymap[command] = append(keymap[command], canonical)
+	}
+
+	if err := keymap.Validate(); err != nil {
+		game.KeyBindings.Status = err.Error()
+		return err
+	}
+
+	game.Keymap = keymap
+	game.KeyBindings.Status = status
+
+	if game.KeymapPath == "" {
+		return nil
+	}
+
+	if err := keymap.SaveFile(game.KeymapPath); err != nil {
+		game.KeyBindings.Status = err.Error()
+		return err
+	}
+
+	return nil
+}
+
+// CancelRebind stops waiting for a key without changing the keymap.
+func (game *Game) CancelRebind() {
+	game.record(ReplayStep{Kind: StepCancelRebind})
+
+	game.KeyBindings.Rebinding = false
+	game.KeyBindings.Status = ""
+}
+
+// CommandMenuItems returns the commands listed in the command menu: every
+// command available while playing except movement, which has its own input.
+func CommandMenuItems() []Command {
+	var items []Command
+
+	for _, command := range Commands() {
+		if command.Contexts()&ContextPlaying == 0 || command.Repeats() || command == CommandCommandMenu {
+			continue
+		}
+
+		items = append(items, command)
+	}
+
+	return items
+}
+
+// OpenCommandMenu shows the command menu over the map, each entry labelled
+// with its keys.
+func (game *Game) OpenCommandMenu() {
+	items := CommandMenuItems()
+
+	labels := make([]string, len(items))
+	for i, command := range items {
+		labels[i] = fmt.Sprintf("%s [%s]", command.Description(), game.Keymap.Label(command))
+	}
+
+	game.CommandMenu = NewList(labels, len(labels), game.Keymap.FreeLetters(ContextMenu))
+	game.State = StateCommandMenu
+}
+
+// handleCommandMenuResult runs the selected command or closes the menu after
+// the command menu list handled input. Returns true if the game should exit.
+func (game *Game) handleCommandMenuResult(result WidgetResult) bool {
+	switch result {
+	case WidgetSubmitted:
+		game.State = StatePlaying
+		return game.handleCommand(CommandMenuItems()[game.CommandMenu.Cursor])
+	case WidgetCancelled:
+		game.State = StatePlaying
+	}
+
+	return false
+}
+
+// HandleRune passes a typed character to the focused widget, such as a list
+// hotkey or a text field. Returns true if the game should exit.
+func (game *Game) HandleRune(r rune) bool {
+	game.record(ReplayStep{Kind: StepRune, Text: string(r)})
+
+	switch game.State {
+	case StateCommandMenu:
+		return game.handleCommandMenuResult(game.CommandMenu.HandleRune(r))
+	case StateConsole:
+		game.Console.Input.HandleRune(r)
+	case StatePaused:
+		if !game.Pause.Confirming && game.Pause.Menu.HandleRune(r) == WidgetSubmitted {
+			return game.selectPauseEntry()
+		}
+	}
+
+	return false
+}
+
+// wrapCursor moves a menu cursor by delta, wrapping around a list of length
+// entries.
+func wrapCursor(cursor, delta, length int) int {
+	return ((cursor+delta)%length + length) % length
+}
+
+// ToggleGraphicalTiles switches between drawing with font glyphs and drawing
+// with the tileset.
+func (game *Game) ToggleGraphicalTiles() {
+	game.Settings.GraphicalTiles = !game.Settings.GraphicalTiles
+
+	if game.Settings.GraphicalTiles {
+		game.AddMessage("Graphical tiles on.")
+	} else {
+		game.AddMessage("Graphical tiles off.")
+	}
+}
+
+// ClickEntry selects entry index of the menu on screen, as when it is
+// clicked, and chooses it. On the key binding screen the first click only
+// selects the entry. Returns true if the game should exit.
+func (game *Game) ClickEntry(index int) bool {
+	game.record(ReplayStep{Kind: StepClick, Index: index})
+
+	switch game.State {
+	case StateTitleScreen:
+		game.TitleCursor = index
+	case StateKeyBindings:
+		if game.KeyBindings.Cursor != index {
+			game.KeyBindings.Cursor = index
+			return false
+		}
+	case StateCommandMenu:
+		game.CommandMenu.Select(index)
+	case StatePaused:
+		game.Pause.Menu.Select(index)
+	case StateGameOver:
+		game.GameOver.Cursor = index
+	case StateOptions:
+		game.Options.Cursor = index
+	default:
+		return false
+	}
+
+	return game.handleCommand(CommandConfirm)
 }
@@ -1,6 +1,11 @@
 package game
 
-import "testing"
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+)
 
 func TestNewGame(t *testing.T) {
 	t.Run("initializes map", func(t *testing.T) {
@@ -408,71 +413,296 @@ func TestCreateCorridor(t *testing.T) {
 	}
 }
 
-func TestQuitConfirmation(t *testing.T) {
-	game := NewGame()
+func TestTick(t *testing.T) {
+	t.Run("increments turn counter", func(t *testing.T) {
+		game := NewGame()
 
-	if game.IsConfirmingQuit() {
-		t.Fatal("new game should not be confirming quit")
-	}
+		initialTurn := game.TurnCount
+		game.Tick()
 
-	game.RequestQuit()
+		if game.TurnCount != initialTurn+1 {
+			t.Errorf("want TurnCount %d, got %d", initialTurn+1, game.TurnCount)
+		}
+	})
 
-	if !game.IsConfirmingQuit() {
-		t.Error("game should be confirming quit after RequestQuit()")
-	}
+	t.Run("increments turn counter multiple times", func(t *testing.T) {
+		game := NewGame()
+
+		game.Tick()
+		game.Tick()
+		game.Tick()
+
+		if game.TurnCount != 3 {
+			t.Errorf("want TurnCount 3, got %d", game.TurnCount)
+		}
+	})
 }
 
-func TestConfirmQuitExitsGame(t *testing.T) {
-	game := NewGame()
-	game.RequestQuit()
+func TestHandleCommand(t *testing.T) {
+	t.Run("starts game from title screen", func(t *testing.T) {
+		game := NewGame()
 
-	shouldQuit := game.ConfirmQuit(true)
+		game.HandleCommand(CommandStartGame)
 
-	if !shouldQuit {
-		t.Error("ConfirmQuit(true) should return true to exit, got false")
-	}
+		if game.State != StatePlaying {
+			t.Errorf("want state %v, got %v", StatePlaying, game.State)
+		}
+	})
 
-	if game.IsConfirmingQuit() {
-		t.Error("game should not be confirming quit after confirmation")
-	}
+	t.Run("quits from title screen", func(t *testing.T) {
+		game := NewGame()
+
+		if !game.HandleCommand(CommandQuit) {
+			t.Error("want quit on title screen to exit, got false")
+		}
+	})
+
+	t.Run("moves player while playing", func(t *testing.T) {
+		game := NewGame()
+		game.StartGame()
+
+		game.HandleCommand(CommandMoveRight)
+
+		if game.Player.X != 18 || game.Player.Y != 9 {
+			t.Errorf("want player at (18,9), got (%d,%d)", game.Player.X, game.Player.Y)
+		}
+	})
+
+	t.Run("ignores movement on title screen", func(t *testing.T) {
+		game := NewGame()
+
+		game.HandleCommand(CommandMoveRight)
+
+		if game.Player.X != 17 {
+			t.Errorf("want player X 17, got %d", game.Player.X)
+		}
+	})
+
+	t.Run("quit opens the pause menu while playing", func(t *testing.T) {
+		game := NewGame()
+		game.SavePath = filepath.Join(t.TempDir(), "save.json")
+		game.StartGame()
+
+		if game.HandleCommand(CommandQuit) {
+			t.Fatal("want quit to open the pause menu, got exit")
+		}
+
+		if game.State != StatePaused || game.Pause.Menu.Cursor != pauseSaveAndQuit {
+			t.Fatalf("want pause menu at save and quit, got state %v cursor %d", game.State, game.Pause.Menu.Cursor)
+		}
+
+		if game.HandleCommand(CommandMoveUp); game.Player.Y != 9 {
+			t.Errorf("want no movement while paused, got Y %d", game.Player.Y)
+		}
+
+		if !game.HandleCommand(CommandConfirm) {
+			t.Error("want save and quit to exit, got false")
+		}
+	})
+}
+
+func TestKeyBindingsScreen(t *testing.T) {
+	t.Run("opens from title and returns", func(t *testing.T) {
+		game := NewGame()
+
+		game.HandleCommand(CommandKeyBindings)
+
+		if game.State != StateKeyBindings {
+			t.Fatalf("want state %v, got %v", StateKeyBindings, game.State)
+		}
+
+		game.HandleCommand(CommandCancel)
+
+		if game.State != StateTitleScreen {
+			t.Errorf("want state %v, got %v", StateTitleScreen, game.State)
+		}
+	})
+
+	t.Run("rebinds selected command", func(t *testing.T) {
+		game := NewGame()
+		game.HandleCommand(CommandKeyBindings)
+		game.HandleCommand(CommandConfirm)
+
+		if !game.IsRebinding() {
+			t.Fatal("want rebinding after confirm, got false")
+		}
+
+		if err := game.RebindSelected("W"); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if command, _ := game.Keymap.Lookup(ContextPlaying, "W"); command != Commands()[0] {
+			t.Errorf("want W bound to %s, got %s", Commands()[0], command)
+		}
+	})
+
+	t.Run("keeps the other keys", func(t *testing.T) {
+		game := NewGame()
+		game.HandleCommand(CommandKeyBindings)
+		game.HandleCommand(CommandConfirm)
+
+		if err := game.RebindSelected("W"); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		for _, chord := range DefaultKeymap()[Commands()[0]] {
+			if command, _ := game.Keymap.Lookup(ContextPlaying, chord); command != Commands()[0] {
+				t.Errorf("want %s still bound to %s, got %s", chord, Commands()[0], command)
+			}
+		}
+	})
+
+	t.Run("removes a bound key", func(t *testing.T) {
+		game := NewGame()
+		game.HandleCommand(CommandKeyBindings)
+		game.HandleCommand(CommandConfirm)
+
+		if err := game.RebindSelected("Numpad8"); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if command, ok := game.Keymap.Lookup(ContextPlaying, "Numpad8"); ok {
+			t.Errorf("want Numpad8 unbound, got %s", command)
+		}
+
+		if command, _ := game.Keymap.Lookup(ContextPlaying, "K"); command != CommandMoveUp {
+			t.Errorf("want K still bound to move_up, got %s", command)
+		}
+	})
+
+	t.Run("keeps the last key", func(t *testing.T) {
+		game := NewGame()
+		game.Keymap[CommandMoveUp] = []string{"K"}
+		game.HandleCommand(CommandKeyBindings)
+		game.HandleCommand(CommandConfirm)
+
+		if err := game.RebindSelected("K"); !errors.Is(err, ErrLastKeyBinding) {
+			t.Errorf("want %v, got %v", ErrLastKeyBinding, err)
+		}
+
+		if command, _ := game.Keymap.Lookup(ContextPlaying, "K"); command != CommandMoveUp {
+			t.Errorf("want K still bound to move_up, got %s", command)
+		}
+	})
+
+	t.Run("rejects conflicting binding", func(t *testing.T) {
+		game := NewGame()
+		game.HandleCommand(CommandKeyBindings)
+		game.HandleCommand(CommandConfirm)
+
+		err := game.RebindSelected("J")
+
+		if !errors.Is(err, ErrKeyBindingConflict) {
+			t.Errorf("want %v, got %v", ErrKeyBindingConflict, err)
+		}
+
+		if command, _ := game.Keymap.Lookup(ContextPlaying, "K"); command != CommandMoveUp {
+			t.Errorf("want K still bound to move_up, got %s", command)
+		}
+	})
 }
 
-func TestCancelQuitReturnsToGame(t *testing.T) {
+func TestTitleMenu(t *testing.T) {
 	game := NewGame()
-	game.RequestQuit()
 
-	shouldQuit := game.ConfirmQuit(false)
+	game.HandleCommand(CommandMenuDown)
+	game.HandleCommand(CommandConfirm)
 
-	if shouldQuit {
-		t.Error("ConfirmQuit(false) should return false to stay in game")
+	if game.State != StateScores {
+		t.Errorf("want second title entry to open scores, got state %v", game.State)
 	}
 
-	if game.IsConfirmingQuit() {
-		t.Error("game should not be confirming quit after cancellation")
+	game = NewGame()
+	game.HandleCommand(CommandMenuUp)
+
+	if !game.HandleCommand(CommandConfirm) {
+		t.Error("want title cursor to wrap to quit, got false")
 	}
 }
 
-func TestTick(t *testing.T) {
-	t.Run("increments turn counter", func(t *testing.T) {
+func TestCommandMenu(t *testing.T) {
+	t.Run("lists non-movement commands", func(t *testing.T) {
+		for _, command := range CommandMenuItems() {
+			if command.Repeats() || command == CommandCommandMenu {
+				t.Errorf("want %s excluded from command menu", command)
+			}
+		}
+	})
+
+	t.Run("runs selected command", func(t *testing.T) {
 		game := NewGame()
+		game.StartGame()
 
-		initialTurn := game.TurnCount
-		game.Tick()
+		game.HandleCommand(CommandCommandMenu)
 
-		if game.TurnCount != initialTurn+1 {
-			t.Errorf("want TurnCount %d, got %d", initialTurn+1, game.TurnCount)
+		if game.State != StateCommandMenu {
+			t.Fatalf("want state %v, got %v", StateCommandMenu, game.State)
+		}
+
+		game.HandleCommand(CommandConfirm)
+
+		if game.State != StatePaused {
+			t.Errorf("want pause menu after choosing quit, got state %v", game.State)
 		}
 	})
 
-	t.Run("increments turn counter multiple times", func(t *testing.T) {
+	t.Run("hotkey runs command", func(t *testing.T) {
 		game := NewGame()
+		game.StartGame()
+		game.HandleCommand(CommandCommandMenu)
 
-		game.Tick()
-		game.Tick()
-		game.Tick()
+		pause := game.CommandMenu.Items[slices.Index(CommandMenuItems(), CommandPause)]
+		game.HandleRune(pause.Hotkey)
 
-		if game.TurnCount != 3 {
-			t.Errorf("want TurnCount 3, got %d", game.TurnCount)
+		if game.State != StatePaused {
+			t.Errorf("want pause menu after typing %q, got state %v", pause.Hotkey, game.State)
+		}
+	})
+
+	t.Run("cancel returns to play", func(t *testing.T) {
+		game := NewGame()
+		game.StartGame()
+		game.HandleCommand(CommandCommandMenu)
+
+		game.HandleCommand(CommandCancel)
+
+		if game.State != StatePlaying {
+			t.Errorf("want state %v, got %v", StatePlaying, game.State)
 		}
 	})
 }
+
+func TestToggleGraphicalTiles(t *testing.T) {
+	game := NewGame()
+	game.StartGame()
+
+	game.HandleCommand(CommandToggleTiles)
+
+	if !game.Settings.GraphicalTiles {
+		t.Error("want graphical tiles on after toggle, got off")
+	}
+
+	game.HandleCommand(CommandToggleTiles)
+
+	if game.Settings.GraphicalTiles {
+		t.Error("want graphical tiles off after second toggle, got on")
+	}
+}
+
+func TestSetSeed(t *testing.T) {
+	first := NewGame()
+	second := NewGame()
+
+	first.SetSeed(42)
+	second.SetSeed(42)
+
+	if first.Seed != 42 {
+		t.Errorf("want seed 42, got %d", first.Seed)
+	}
+
+	for range 10 {
+		if a, b := first.rng.IntN(1000), second.rng.IntN(1000); a != b {
+			t.Fatalf("want the same sequence for the same seed, got %d and %d", a, b)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Entries of the game over menu, indexing GameOverMenu.
+const (
+	gameOverViewMorgue = iota
+	gameOverNewRun
+	gameOverReturnToTitle
+)
+
+// GameOverMenu lists the choices offered on the game over screen.
+var GameOverMenu = []string{
+	gameOverViewMorgue:    "View morgue",
+	gameOverNewRun:        "New run",
+	gameOverReturnToTitle: "Return to title",
+}
+
+// GameOverScreen holds the state of the game over screen.
+type GameOverScreen struct {
+	Cause         string   // Cause is what killed the player.
+	Score         int      // Score is the final score of the run.
+	Cursor        int      // Cursor is the selected GameOverMenu entry.
+	ViewingMorgue bool     // ViewingMorgue is true while the morgue text is shown.
+	Scroll        int      // Scroll is the first morgue line shown.
+	MorgueLines   []string // MorgueLines is the character dump of the run.
+}
+
+// DamagePlayer reduces the player's health by amount and interrupts held-key
+// movement and travel. cause names what dealt the damage and is reported as
+// the cause of death if the player dies when the turn ends.
+func (game *Game) DamagePlayer(amount int, cause string) {
+	if game.GodMode {
+		return
+	}
+
+	game.Player.Health -= amount
+	game.lastDamage = cause
+
+	// Getting hurt stops held-key movement and travel
+	if amount > 0 {
+		game.Interrupt()
+	}
+}
+
+// checkDeath ends the run if the player's health has run out.
+func (game *Game) checkDeath() {
+	if game.State != StatePlaying || game.Player.Health > 0 {
+		return
+	}
+
+	cause := game.lastDamage
+	if cause == "" {
+		cause = "unknown causes"
+	}
+
+	game.Die(cause)
+}
+
+// Die ends the run with the player killed by cause: the death is announced,
+// narrated for screen readers, recorded in the score table and morgue, and
+// the game over screen is shown.
+func (game *Game) Die(cause string) {
+	ending := fmt.Sprintf("Killed by %s on turn %d.", cause, game.TurnCount)
+
+	game.emit(Event{Kind: EventDied, Actor: game.Player.Name, X: game.Player.X, Y: game.Player.Y, Cause: cause})
+	game.AddImportantMessage("You die...")
+	game.narrate("You die. " + ending)
+	game.EndRun(ending)
+
+	game.GameOver = GameOverScreen{
+		Cause:       cause,
+		Score:       Score(game.Depth, game.Player.Karma, game.Player.Nuyen, game.TurnCount),
+		MorgueLines: strings.Split(strings.TrimSuffix(game.MorgueText(ending), "\n"), "\n"),
+	}
+	game.State = StateGameOver
+}
+
+// handleGameOverCommand moves the game over menu selection, runs the selected
+// entry, or scrolls the morgue while it is shown.
+func (game *Game) handleGameOverCommand(command Command) {
+	screen := &game.GameOver
+
+	if screen.ViewingMorgue {
+		switch command {
+		case CommandMenuUp:
+			screen.Scroll = max(screen.Scroll-1, 0)
+		case CommandMenuDown:
+			screen.Scroll = min(screen.Scroll+1, max(len(screen.MorgueLines)-1, 0))
+		case CommandConfirm, CommandCancel:
+			screen.ViewingMorgue = false
+		}
+
+		return
+	}
+
+	switch command {
+	case CommandMenuUp:
+		screen.Cursor = wrapCursor(screen.Cursor, -1, len(GameOverMenu))
+	case CommandMenuDown:
+		screen.Cursor = wrapCursor(screen.Cursor, 1, len(GameOverMenu))
+	case CommandCancel:
+		game.NewRun()
+		game.State = StateTitleScreen
+	case CommandConfirm:
+		switch screen.Cursor {
+		case gameOverViewMorgue:
+			screen.ViewingMorgue = true
+			screen.Scroll = 0
+		case gameOverNewRun:
+			game.NewRun()
+			game.StartGame()
+		case gameOverReturnToTitle:
+			game.NewRun()
+			game.State = StateTitleScreen
+		}
+	}
+}
+
+// NewRun replaces the world and player with a fresh run on the title screen,
+// keeping the player's preferences, score table, and event subscribers.
+func (game *Game) NewRun() {
+	fresh := NewGame()
+	fresh.SetSeed(game.rng.Int64())
+
+	fresh.Keymap = game.Keymap
+	fresh.KeymapPath = game.KeymapPath
+	fresh.Settings = game.Settings
+	fresh.Narrator = game.Narrator
+	fresh.Events = game.Events
+	fresh.MorgueDir = game.MorgueDir
+	fresh.Scores = game.Scores
+	fresh.ScoresPath = game.ScoresPath
+	fresh.SavePath = game.SavePath
+	fresh.SettingsPath = game.SettingsPath
+	fresh.Wizard = game.Wizard
+	fresh.DumpDir = game.DumpDir
+	fresh.Recording = game.Recording
+	fresh.Generator = game.Generator
+	fresh.SetContent(game.Content)
+
+	if game.History != nil {
+		fresh.EnableHistory(game.History.Interval)
+	}
+
+	*game = *fresh
+}
@@ -0,0 +1,127 @@
+package game
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDeathInTick(t *testing.T) {
+	tests := []struct {
+		name      string
+		damage    int
+		cause     string
+		wantState GameState
+		wantCause string
+	}{
+		{name: "survives", damage: 10, cause: "a ganger", wantState: StatePlaying},
+		{name: "dies", damage: 100, cause: "a ganger", wantState: StateGameOver, wantCause: "a ganger"},
+		{name: "dies of unknown causes", damage: 150, wantState: StateGameOver, wantCause: "unknown causes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := NewGame()
+			game.StartGame()
+
+			game.DamagePlayer(tt.damage, tt.cause)
+			game.Tick()
+
+			if game.State != tt.wantState {
+				t.Fatalf("want state %v, got %v", tt.wantState, game.State)
+			}
+
+			if game.GameOver.Cause != tt.wantCause {
+				t.Errorf("want cause %q, got %q", tt.wantCause, game.GameOver.Cause)
+			}
+		})
+	}
+}
+
+func TestDie(t *testing.T) {
+	game := NewGame()
+	game.ScoresPath = filepath.Join(t.TempDir(), "scores.json")
+	game.StartGame()
+
+	var died bool
+	game.Events.Subscribe(func(event Event) {
+		died = died || event.Kind == EventDied
+	})
+
+	game.Die("a drone")
+
+	if !died {
+		t.Error("want died event, got none")
+	}
+
+	if len(game.Scores.Runs) != 1 || game.Scores.Runs[0].Ending != "Killed by a drone on turn 0." {
+		t.Errorf("want recorded run, got %+v", game.Scores.Runs)
+	}
+
+	if game.GameOver.Score != Score(1, 0, 0, 0) || len(game.GameOver.MorgueLines) == 0 {
+		t.Errorf("want score and morgue on the game over screen, got %+v", game.GameOver)
+	}
+}
+
+func TestGameOverMenu(t *testing.T) {
+	tests := []struct {
+		name      string
+		commands  []Command
+		wantState GameState
+	}{
+		{name: "view morgue", commands: []Command{CommandConfirm}, wantState: StateGameOver},
+		{name: "new run", commands: []Command{CommandMenuDown, CommandConfirm}, wantState: StatePlaying},
+		{name: "return to title", commands: []Command{CommandMenuUp, CommandConfirm}, wantState: StateTitleScreen},
+		{name: "cancel returns to title", commands: []Command{CommandCancel}, wantState: StateTitleScreen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := NewGame()
+			game.Settings.Theme = "amber"
+			game.StartGame()
+			game.Die("a drone")
+
+			for _, command := range tt.commands {
+				game.HandleCommand(command)
+			}
+
+			if game.State != tt.wantState {
+				t.Errorf("want state %v, got %v", tt.wantState, game.State)
+			}
+
+			if tt.wantState != StateGameOver && (game.Player.Health != 100 || game.TurnCount != 0) {
+				t.Errorf("want a fresh run, got health %d on turn %d", game.Player.Health, game.TurnCount)
+			}
+
+			if game.Settings.Theme != "amber" || len(game.Scores.Runs) != 1 {
+				t.Errorf("want settings and scores kept, got theme %q and %d runs", game.Settings.Theme, len(game.Scores.Runs))
+			}
+		})
+	}
+}
+
+func TestGameOverMorgueView(t *testing.T) {
+	game := NewGame()
+	game.StartGame()
+	game.Die("a drone")
+
+	game.HandleCommand(CommandConfirm)
+
+	if !game.GameOver.ViewingMorgue {
+		t.Fatal("want morgue shown, got menu")
+	}
+
+	game.HandleCommand(CommandMenuUp)
+	game.HandleCommand(CommandMenuDown)
+	game.HandleCommand(CommandMenuDown)
+
+	if game.GameOver.Scroll != 2 {
+		t.Errorf("want scroll 2, got %d", game.GameOver.Scroll)
+	}
+
+	game.HandleCommand(CommandCancel)
+
+	if game.GameOver.ViewingMorgue || game.State != StateGameOver {
+		t.Errorf("want game over menu, got viewing %v in state %v", game.GameOver.ViewingMorgue, game.State)
+	}
+}
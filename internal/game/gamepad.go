@@ -0,0 +1,160 @@
+package game
+
+import "math"
+
+// GamepadInput is a logical gamepad input after the physical buttons and
+// sticks have been read, independent of the controller model.
+type GamepadInput int
+
+const (
+	// GamepadNone represents no input.
+	GamepadNone GamepadInput = iota
+
+	// GamepadUp is the d-pad or stick pushed up.
+	GamepadUp
+
+	// GamepadDown is the d-pad or stick pushed down.
+	GamepadDown
+
+	// GamepadLeft is the d-pad or stick pushed left.
+	GamepadLeft
+
+	// GamepadRight is the d-pad or stick pushed right.
+	GamepadRight
+
+	// GamepadUpLeft is the d-pad or stick pushed up and left.
+	GamepadUpLeft
+
+	// GamepadUpRight is the d-pad or stick pushed up and right.
+	GamepadUpRight
+
+	// GamepadDownLeft is the d-pad or stick pushed down and left.
+	GamepadDownLeft
+
+	// GamepadDownRight is the d-pad or stick pushed down and right.
+	GamepadDownRight
+
+	// GamepadAccept is the bottom face button (A on Xbox, Cross on PlayStation).
+	GamepadAccept
+
+	// GamepadBack is the right face button (B on Xbox, Circle on PlayStation).
+	GamepadBack
+
+	// GamepadStart is the start or options button.
+	GamepadStart
+)
+
+// gamepadMoves maps each direction to the movement command it issues.
+var gamepadMoves = map[GamepadInput]Command{
+	GamepadUp:        CommandMoveUp,
+	GamepadDown:      CommandMoveDown,
+	GamepadLeft:      CommandMoveLeft,
+	GamepadRight:     CommandMoveRight,
+	GamepadUpLeft:    CommandMoveUpLeft,
+	GamepadUpRight:   CommandMoveUpRight,
+	GamepadDownLeft:  CommandMoveDownLeft,
+	GamepadDownRight: CommandMoveDownRight,
+}
+
+// stickOctants lists directions counterclockwise from right, one per 45
+// degree slice of the stick's range. Screen y grows downward.
+var stickOctants = []GamepadInput{
+	GamepadRight, GamepadUpRight, GamepadUp, GamepadUpLeft,
+	GamepadLeft, GamepadDownLeft, GamepadDown, GamepadDownRight,
+}
+
+// StickDirection converts analog stick axes in the range -1 to 1 into one of
+// eight directions. Returns GamepadNone while the stick is inside deadzone.
+func StickDirection(x, y, deadzone float64) GamepadInput {
+	if math.Hypot(x, y) <= deadzone {
+		return GamepadNone
+	}
+
+	angle := math.Atan2(-y, x)
+	octant := int(math.Round(angle/(math.Pi/4))+8) % 8
+
+	return stickOctants[octant]
+}
+
+// DpadDirection combines the four d-pad buttons into one of eight directions.
+func DpadDirection(up, down, left, right bool) GamepadInput {
+	dx, dy := 0, 0
+
+	if left {
+		dx--
+	}
+
+	if right {
+		dx++
+	}
+
+	if up {
+		dy--
+	}
+
+	if down {
+		dy++
+	}
+
+	for input, command := range gamepadMoves {
+		if moveX, moveY, _ := command.moveDelta(); moveX == dx && moveY == dy {
+			return input
+		}
+	}
+
+	return GamepadNone
+}
+
+// IsDirection reports whether the input is one of the eight directions.
+func (input GamepadInput) IsDirection() bool {
+	_, ok := gamepadMoves[input]
+
+	return ok
+}
+
+// GamepadCommand translates a gamepad input into the command it issues in
+// context, so gamepads share the command layer with the keyboard. Directions
+// move while playing and navigate menus elsewhere; Accept and Back confirm
+// and cancel; Start and Accept open the command menu while playing.
+func GamepadCommand(context InputContext, input GamepadInput) Command {
+	if input.IsDirection() {
+		if context == ContextPlaying {
+			return gamepadMoves[input]
+		}
+
+		switch input {
+		case GamepadUp:
+			return CommandMenuUp
+		case GamepadDown:
+			return CommandMenuDown
+		}
+
+		return CommandNone
+	}
+
+	switch context {
+	case ContextTitle:
+		switch input {
+		case GamepadAccept:
+			return CommandConfirm
+		case GamepadStart:
+			return CommandStartGame
+		}
+
+	case ContextPlaying:
+		switch input {
+		case GamepadAccept, GamepadStart:
+			return CommandCommandMenu
+		}
+
+	case ContextPrompt, ContextMenu:
+		switch input {
+		case GamepadAccept:
+			return CommandConfirm
+		case GamepadBack, GamepadStart:
+			return CommandCancel
+		}
+	}
+
+	return CommandNone
+}
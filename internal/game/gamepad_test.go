@@ -0,0 +1,75 @@
+package game
+
+import "testing"
+
+func TestStickDirection(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y float64
+		want GamepadInput
+	}{
+		{name: "centered", x: 0, y: 0, want: GamepadNone},
+		{name: "inside deadzone", x: 0.1, y: -0.15, want: GamepadNone},
+		{name: "right", x: 1, y: 0.1, want: GamepadRight},
+		{name: "up", x: 0.05, y: -0.9, want: GamepadUp},
+		{name: "down left", x: -0.7, y: 0.7, want: GamepadDownLeft},
+		{name: "up right", x: 0.6, y: -0.6, want: GamepadUpRight},
+		{name: "left", x: -1, y: 0, want: GamepadLeft},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StickDirection(tt.x, tt.y, 0.3); got != tt.want {
+				t.Errorf("want %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDpadDirection(t *testing.T) {
+	tests := []struct {
+		name                  string
+		up, down, left, right bool
+		want                  GamepadInput
+	}{
+		{name: "none", want: GamepadNone},
+		{name: "up", up: true, want: GamepadUp},
+		{name: "up right", up: true, right: true, want: GamepadUpRight},
+		{name: "down left", down: true, left: true, want: GamepadDownLeft},
+		{name: "opposites cancel", left: true, right: true, want: GamepadNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DpadDirection(tt.up, tt.down, tt.left, tt.right); got != tt.want {
+				t.Errorf("want %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGamepadCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		context InputContext
+		input   GamepadInput
+		want    Command
+	}{
+		{name: "moves while playing", context: ContextPlaying, input: GamepadDownRight, want: CommandMoveDownRight},
+		{name: "navigates menus", context: ContextMenu, input: GamepadUp, want: CommandMenuUp},
+		{name: "ignores sideways in menus", context: ContextMenu, input: GamepadLeft, want: CommandNone},
+		{name: "accept confirms prompt", context: ContextPrompt, input: GamepadAccept, want: CommandConfirm},
+		{name: "back cancels prompt", context: ContextPrompt, input: GamepadBack, want: CommandCancel},
+		{name: "accept selects on title", context: ContextTitle, input: GamepadAccept, want: CommandConfirm},
+		{name: "start begins from title", context: ContextTitle, input: GamepadStart, want: CommandStartGame},
+		{name: "start opens command menu", context: ContextPlaying, input: GamepadStart, want: CommandCommandMenu},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GamepadCommand(tt.context, tt.input); got != tt.want {
+				t.Errorf("want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
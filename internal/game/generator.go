@@ -0,0 +1,55 @@
+package game
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Generator names an algorithm that lays out levels.
+type Generator string
+
+const (
+	// GeneratorRooms lays out rooms joined by corridors.
+	GeneratorRooms Generator = "rooms"
+
+	// GeneratorCaves grows sewers, tunnels, and collapsed zones with a
+	// cellular automaton.
+	GeneratorCaves Generator = "caves"
+
+	// GeneratorOffice partitions corporate floors into offices, server
+	// rooms, and security posts.
+	GeneratorOffice Generator = "office"
+)
+
+// generators carve a level into a map of walls and place the player, keyed
+// by name.
+var generators = map[Generator]func(game *Game, width, height int){
+	GeneratorRooms:  (*Game).generateRooms,
+	GeneratorCaves:  (*Game).generateCaves,
+	GeneratorOffice: (*Game).generateOffice,
+}
+
+// GeneratorNames returns the names of the level generators, sorted.
+func GeneratorNames() []string {
+	names := make([]string, 0, len(generators))
+	for _, name := range slices.Sorted(maps.Keys(generators)) {
+		names = append(names, string(name))
+	}
+
+	return names
+}
+
+// ParseGenerator returns the generator called name. An empty name is the
+// room generator.
+func ParseGenerator(name string) (Generator, error) {
+	if name == "" {
+		return GeneratorRooms, nil
+	}
+
+	if _, ok := generators[Generator(name)]; !ok {
+		return "", fmt.Errorf("%w: %q, want one of %v", ErrUnknownGenerator, name, GeneratorNames())
+	}
+
+	return Generator(name), nil
+}
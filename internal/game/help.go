@@ -0,0 +1,113 @@
+package game
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// helpPrimer is the short introduction shown at the end of the help screen.
+var helpPrimer = []string{
+	"You are a runner in the sprawl. Explore the level, fight or slip past",
+	"whatever lives there, and get out alive. Every move you make is one",
+	"turn; nothing else moves until you do, so take your time.",
+	"",
+	"Walking into a wall does nothing, but walking into a creature attacks",
+	"it. Stand on an item and use the use item command to use it up. Hover",
+	"the mouse over a tile to see what it is, or click to travel there.",
+	"Open the command menu to pick any command without knowing its key.",
+	"",
+	"Death is permanent. Saving and quitting keeps your run for next time,",
+	"but the save is gone as soon as you continue it.",
+}
+
+// HelpScreen holds the state of the help screen.
+type HelpScreen struct {
+	Scroll      int       // Scroll is the first help line shown.
+	Lines       []string  // Lines is the help text, built when the screen opens.
+	returnState GameState // returnState is the state to go back to when the screen closes.
+}
+
+// OpenHelp shows the help screen. Closing it returns to the current state.
+func (game *Game) OpenHelp() {
+	game.Help = HelpScreen{Lines: game.HelpLines(), returnState: game.State}
+	game.State = StateHelp
+}
+
+// HelpLines returns the help text: the current key bindings by group, a
+// legend of the terrain, creatures, and items the content defines, and a
+// gameplay primer.
+func (game *Game) HelpLines() []string {
+	lines := []string{"Controls"}
+
+	group := ""
+	for _, command := range Commands() {
+		if command.Group() != group {
+			group = command.Group()
+			lines = append(lines, "", "  "+group)
+		}
+
+		keys := game.Keymap.Label(command)
+		if keys == "" {
+			keys = "unbound"
+		}
+
+		lines = append(lines, fmt.Sprintf("    %-24s %s", command.Description(), keys))
+	}
+
+	lines = append(lines, "", "Legend", "")
+	lines = append(lines, fmt.Sprintf("    %c  %s", game.Player.Glyph, "you"))
+
+	tiles := slices.SortedFunc(maps.Values(game.Content.Tiles), func(a, b TileDef) int {
+		return cmp.Or(cmp.Compare(a.Glyph, b.Glyph), cmp.Compare(a.Name, b.Name))
+	})
+
+	lines = append(lines, "", "  Terrain")
+	for _, tile := range tiles {
+		lines = append(lines, fmt.Sprintf("    %s  %s", tile.Glyph, tile.Name))
+	}
+
+	monsters := slices.SortedFunc(maps.Values(game.Content.Monsters), func(a, b MonsterDef) int {
+		return cmp.Or(cmp.Compare(a.Glyph, b.Glyph), cmp.Compare(a.Name, b.Name))
+	})
+
+	if len(monsters) > 0 {
+		lines = append(lines, "", "  Creatures")
+	}
+
+	for _, monster := range monsters {
+		lines = append(lines, fmt.Sprintf("    %s  %s (%s)", monster.Glyph, monster.Name, cmp.Or(monster.Disposition, string(DispositionHostile))))
+	}
+
+	items := slices.SortedFunc(maps.Values(game.Content.Items), func(a, b ItemDef) int {
+		return cmp.Or(cmp.Compare(a.Glyph, b.Glyph), cmp.Compare(a.Name, b.Name))
+	})
+
+	if len(items) > 0 {
+		lines = append(lines, "", "  Items")
+	}
+
+	for _, item := range items {
+		lines = append(lines, fmt.Sprintf("    %s  %s", item.Glyph, item.Name))
+	}
+
+	lines = append(lines, "", "Primer", "")
+	for _, line := range helpPrimer {
+		lines = append(lines, "    "+line)
+	}
+
+	return lines
+}
+
+// handleHelpCommand scrolls or closes the help screen.
+func (game *Game) handleHelpCommand(command Command) {
+	switch command {
+	case CommandMenuUp:
+		game.Help.Scroll = max(game.Help.Scroll-1, 0)
+	case CommandMenuDown:
+		game.Help.Scroll = min(game.Help.Scroll+1, max(len(game.Help.Lines)-1, 0))
+	case CommandConfirm, CommandCancel:
+		game.State = game.Help.returnState
+	}
+}
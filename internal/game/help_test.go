@@ -0,0 +1,98 @@
+package game
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestHelpScreen(t *testing.T) {
+	t.Run("opens from title and play", func(t *testing.T) {
+		for _, started := range []bool{false, true} {
+			game := NewGame()
+			if started {
+				game.StartGame()
+			}
+
+			returnState := game.State
+			game.HandleCommand(CommandHelp)
+
+			if game.State != StateHelp {
+				t.Fatalf("want state %v, got %v", StateHelp, game.State)
+			}
+
+			game.HandleCommand(CommandCancel)
+
+			if game.State != returnState {
+				t.Errorf("want state %v after closing help, got %v", returnState, game.State)
+			}
+		}
+	})
+
+	t.Run("scroll stays in range", func(t *testing.T) {
+		game := NewGame()
+		game.OpenHelp()
+
+		game.HandleCommand(CommandMenuUp)
+
+		if game.Help.Scroll != 0 {
+			t.Errorf("want scroll 0, got %d", game.Help.Scroll)
+		}
+
+		for range len(game.Help.Lines) + 5 {
+			game.HandleCommand(CommandMenuDown)
+		}
+
+		if game.Help.Scroll != len(game.Help.Lines)-1 {
+			t.Errorf("want scroll %d, got %d", len(game.Help.Lines)-1, game.Help.Scroll)
+		}
+	})
+}
+
+func TestHelpLines(t *testing.T) {
+	content := NewContent()
+	content.Add(ContentFile{
+		Monsters: []MonsterDef{{ID: "ganger", Name: "ganger", Glyph: "g", Health: 10, Depth: 1}},
+		Items:    []ItemDef{{ID: "medkit", Name: "medkit", Glyph: "+"}},
+	})
+
+	game := NewGame()
+	game.SetContent(content)
+	game.Keymap[CommandMorgue] = []string{"F9"}
+	game.Keymap[CommandScores] = nil
+
+	lines := game.HelpLines()
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "live key bindings", want: "Character dump"},
+		{name: "unbound commands", want: "High scores"},
+		{name: "player glyph", want: "@  you"},
+		{name: "wall glyph", want: "#  wall"},
+		{name: "floor glyph", want: ".  floor"},
+		{name: "monster glyph", want: "g  ganger (hostile)"},
+		{name: "item glyph", want: "+  medkit"},
+		{name: "bumping attacks", want: "walking into a creature attacks"},
+		{name: "primer", want: "Death is permanent."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !slices.ContainsFunc(lines, func(line string) bool { return strings.Contains(line, tt.want) }) {
+				t.Errorf("want a line containing %q, got %q", tt.want, lines)
+			}
+		})
+	}
+
+	for _, line := range lines {
+		if strings.Contains(line, "Character dump") && !strings.HasSuffix(line, "F9") {
+			t.Errorf("want rebound key F9, got %q", line)
+		}
+
+		if strings.Contains(line, "High scores") && !strings.HasSuffix(line, "unbound") {
+			t.Errorf("want unbound marker, got %q", line)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package game
+
+import "fmt"
+
+// DefaultSnapshotInterval is how many turns pass between turn history
+// snapshots unless another interval is chosen.
+const DefaultSnapshotInterval = 10
+
+// historySize is the number of snapshots kept before the oldest is dropped.
+const historySize = 100
+
+// Snapshot is the state of the run at the end of a turn, serialized with
+// MarshalState so it stays in step with save files.
+type Snapshot struct {
+	Turn int    // Turn is the turn the snapshot was taken on.
+	Data []byte // Data is the serialized state.
+}
+
+// History is a ring buffer of snapshots taken every Interval turns, used to
+// step backward and forward through a run in wizard mode.
+type History struct {
+	Interval  int        // Interval is the number of turns between snapshots.
+	snapshots []Snapshot // snapshots is the ring, with room for historySize snapshots.
+	start     int        // start is the index in snapshots of the oldest snapshot.
+	count     int        // count is the number of snapshots held.
+	current   int        // current is the position of the snapshot last restored, or -1 at the present.
+}
+
+// NewHistory creates an empty history taking a snapshot every interval turns.
+func NewHistory(interval int) *History {
+	return &History{
+		Interval:  max(interval, 1),
+		snapshots: make([]Snapshot, historySize),
+		current:   -1,
+	}
+}
+
+// Len returns the number of snapshots held.
+func (history *History) Len() int {
+	return history.count
+}
+
+// At returns the snapshot at position i, oldest first.
+func (history *History) At(i int) Snapshot {
+	return history.snapshots[(history.start+i)%len(history.snapshots)]
+}
+
+// Position returns the position of the snapshot last restored, or -1 while
+// the run is at the present.
+func (history *History) Position() int {
+	return history.current
+}
+
+// push adds a snapshot after the newest one, dropping the oldest when the
+// ring is full.
+func (history *History) push(snapshot Snapshot) {
+	if history.count < len(history.snapshots) {
+		history.snapshots[(history.start+history.count)%len(history.snapshots)] = snapshot
+		history.count++
+
+		return
+	}
+
+	history.snapshots[history.start] = snapshot
+	history.start = (history.start + 1) % len(history.snapshots)
+}
+
+// EnableHistory starts keeping a turn history with a snapshot every interval
+// turns.
+func (game *Game) EnableHistory(interval int) {
+	game.History = NewHistory(interval)
+}
+
+// recordHistory is called each turn. A turn taken after rewinding starts a
+// new timeline, so the snapshots after the restored one are dropped. A
+// snapshot is then taken if the turn falls on the interval.
+func (game *Game) recordHistory() {
+	history := game.History
+	if history == nil {
+		return
+	}
+
+	if history.current >= 0 {
+		history.count = history.current + 1
+		history.current = -1
+	}
+
+	if game.TurnCount%history.Interval != 0 {
+		return
+	}
+
+	data, err := game.MarshalState()
+	if err != nil {
+		game.AddImportantMessage(fmt.Sprintf("Taking a snapshot failed: %v", err))
+		return
+	}
+
+	history.push(Snapshot{Turn: game.TurnCount, Data: data})
+}
+
+// Rewind restores the snapshot before the current turn. The present is
+// snapshotted first so FastForward can return to it. Returns false if there
+// is no older snapshot.
+func (game *Game) Rewind() bool {
+	history := game.History
+	if history == nil {
+		game.AddMessage("Turn history is only kept in wizard mode.")
+		return false
+	}
+
+	if history.current < 0 {
+		if history.count == 0 || history.At(history.count-1).Turn != game.TurnCount {
+			data, err := game.MarshalState()
+			if err != nil {
+				game.AddImportantMessage(fmt.Sprintf("Taking a snapshot failed: %v", err))
+				return false
+			}
+
+			history.push(Snapshot{Turn: game.TurnCount, Data: data})
+		}
+
+		history.current = history.count - 1
+	}
+
+	if history.current == 0 {
+		game.AddMessage("There is no older snapshot.")
+		return false
+	}
+
+	return game.restoreSnapshot(history.current - 1)
+}
+
+// FastForward restores the snapshot after the one last restored. Returns
+// false if there is no newer snapshot.
+func (game *Game) FastForward() bool {
+	history := game.History
+	if history == nil {
+		game.AddMessage("Turn history is only kept in wizard mode.")
+		return false
+	}
+
+	if history.current < 0 || history.current == history.count-1 {
+		game.AddMessage("There is no newer snapshot.")
+		return false
+	}
+
+	return game.restoreSnapshot(history.current + 1)
+}
+
+// restoreSnapshot replaces the run with the snapshot at position i.
+func (game *Game) restoreSnapshot(i int) bool {
+	snapshot := game.History.At(i)
+
+	if err := game.RestoreState(snapshot.Data); err != nil {
+		game.AddImportantMessage(fmt.Sprintf("Restoring the snapshot failed: %v", err))
+		return false
+	}
+
+	game.History.current = i
+	game.AddMessage(fmt.Sprintf("Restored turn %d (snapshot %d of %d).", snapshot.Turn, i+1, game.History.count))
+
+	return true
+}
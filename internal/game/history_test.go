@@ -0,0 +1,116 @@
+package game
+
+import "testing"
+
+// walk passes turns turns by stepping the player right and back.
+func walk(game *Game, turns int) {
+	for i := range turns {
+		if i%2 == 0 {
+			game.MovePlayer(1, 0)
+		} else {
+			game.MovePlayer(-1, 0)
+		}
+	}
+}
+
+func TestHistory(t *testing.T) {
+	t.Run("snapshots every interval", func(t *testing.T) {
+		game := NewGame()
+		game.EnableHistory(2)
+
+		walk(game, 7)
+
+		if game.History.Len() != 3 {
+			t.Fatalf("want 3 snapshots, got %d", game.History.Len())
+		}
+
+		for i, want := range []int{2, 4, 6} {
+			if got := game.History.At(i).Turn; got != want {
+				t.Errorf("want snapshot %d on turn %d, got %d", i, want, got)
+			}
+		}
+	})
+
+	t.Run("full ring drops the oldest", func(t *testing.T) {
+		game := NewGame()
+		game.EnableHistory(1)
+
+		walk(game, historySize+5)
+
+		if game.History.Len() != historySize {
+			t.Fatalf("want %d snapshots, got %d", historySize, game.History.Len())
+		}
+
+		if got := game.History.At(0).Turn; got != 6 {
+			t.Errorf("want oldest snapshot on turn 6, got %d", got)
+		}
+	})
+
+	t.Run("rewind and fast forward", func(t *testing.T) {
+		game := NewGame()
+		game.EnableHistory(2)
+		game.HandleCommand(CommandStartGame)
+		walk(game, 5)
+
+		tests := []struct {
+			name    string
+			command Command
+			turn    int
+		}{
+			{"rewind from the present", CommandRewind, 4},
+			{"rewind again", CommandRewind, 2},
+			{"rewind past the oldest", CommandRewind, 2},
+			{"fast forward", CommandFastForward, 4},
+			{"fast forward to the present", CommandFastForward, 5},
+			{"fast forward past the present", CommandFastForward, 5},
+		}
+
+		for _, tt := range tests {
+			game.HandleCommand(tt.command)
+
+			if game.TurnCount != tt.turn {
+				t.Errorf("%s: want turn %d, got %d", tt.name, tt.turn, game.TurnCount)
+			}
+		}
+	})
+
+	t.Run("moving after a rewind drops the future", func(t *testing.T) {
+		game := NewGame()
+		game.EnableHistory(2)
+		walk(game, 6)
+
+		game.Rewind()
+		game.Rewind()
+		walk(game, 1)
+
+		if game.History.Position() != -1 {
+			t.Errorf("want history at the present, got position %d", game.History.Position())
+		}
+
+		if game.History.Len() != 1 {
+			t.Fatalf("want 1 snapshot, got %d", game.History.Len())
+		}
+
+		if game.FastForward() {
+			t.Error("want no newer snapshot after moving on")
+		}
+	})
+
+	t.Run("rewind without history", func(t *testing.T) {
+		game := NewGame()
+		walk(game, 3)
+
+		if game.Rewind() {
+			t.Error("want rewind to fail without history")
+		}
+
+		want := "Turn history is only kept in wizard mode."
+		if got := game.Messages[len(game.Messages)-1].Text; got != want {
+			t.Errorf("want message %q, got %q", want, got)
+		}
+
+		if game.TurnCount != 3 {
+			t.Errorf("want turn 3, got %d", game.TurnCount)
+		}
+	})
+}
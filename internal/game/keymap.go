@@ -0,0 +1,359 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+//go:generate go run ../../cmd/controlsdoc -readme ../../README.md
+
+// Keymap maps each command to the key chords that trigger it. A chord is a
+// key name, optionally prefixed by modifiers, such as "K", "ArrowUp" or
+// "Shift+Q". Key names match the names used by Ebitengine.
+type Keymap map[Command][]string
+
+// chordModifiers lists the supported modifiers in their canonical order.
+var chordModifiers = []string{"Ctrl", "Alt", "Shift"}
+
+// keyLabels holds short display labels for keys whose names are long.
+var keyLabels = map[string]string{
+	"ArrowUp":      "↑",
+	"ArrowDown":    "↓",
+	"ArrowLeft":    "←",
+	"ArrowRight":   "→",
+	"PageUp":       "PgUp",
+	"PageDown":     "PgDn",
+	"Escape":       "Esc",
+	"Backquote":    "`",
+	"Period":       ".",
+	"BracketLeft":  "[",
+	"BracketRight": "]",
+}
+
+// shiftedKeyLabels holds display labels for keys pressed with Shift alone,
+// showing the character typed rather than the key name.
+var shiftedKeyLabels = map[string]string{
+	"Slash":  "?",
+	"Comma":  "<",
+	"Period": ">",
+}
+
+// DefaultKeymap returns the built in key bindings.
+func DefaultKeymap() Keymap {
+	return Keymap{
+		CommandMoveUp:        {"K", "Numpad8", "ArrowUp"},
+		CommandMoveDown:      {"J", "Numpad2", "ArrowDown"},
+		CommandMoveLeft:      {"H", "Numpad4", "ArrowLeft"},
+		CommandMoveRight:     {"L", "Numpad6", "ArrowRight"},
+		CommandMoveUpLeft:    {"Y", "Numpad7", "Home"},
+		CommandMoveUpRight:   {"U", "Numpad9", "PageUp"},
+		CommandMoveDownLeft:  {"B", "Numpad1", "End"},
+		CommandMoveDownRight: {"N", "Numpad3", "PageDown"},
+		CommandQuit:          {"Shift+Q"},
+		CommandConfirm:       {"Y", "Enter"},
+		CommandCancel:        {"N", "Escape"},
+		CommandStartGame:     {"Space"},
+		CommandKeyBindings:   {"F2"},
+		CommandMenuUp:        {"K", "ArrowUp"},
+		CommandMenuDown:      {"J", "ArrowDown"},
+		CommandCommandMenu:   {"Tab"},
+		CommandToggleTiles:   {"F3"},
+		CommandFontLarger:    {"Ctrl+Equal"},
+		CommandFontSmaller:   {"Ctrl+Minus"},
+		CommandMorgue:        {"Shift+M"},
+		CommandScores:        {"S"},
+		CommandPause:         {"Escape"},
+		CommandTextSubmit:    {"Enter"},
+		CommandTextCancel:    {"Escape"},
+		CommandTextDelete:    {"Backspace"},
+		CommandHelp:          {"Shift+Slash"},
+		CommandConsole:       {"Backquote"},
+		CommandReplayPause:   {"Space"},
+		CommandReplayStep:    {"Period"},
+		CommandReplayFaster:  {"BracketRight"},
+		CommandReplaySlower:  {"BracketLeft"},
+		CommandRewind:        {"Shift+Comma"},
+		CommandFastForward:   {"Shift+Period"},
+		CommandUseItem:       {"G"},
+	}
+}
+
+// LoadKeymap reads JSON key bindings from reader and applies them on top of
+// the default keymap. The JSON object maps command names to lists of chords;
+// commands that are not listed keep their default bindings. Returns an error
+// if a command is unknown, a chord is malformed, or two commands in the same
+// context share a chord.
+func LoadKeymap(reader io.Reader) (Keymap, error) {
+	var overrides map[string][]string
+
+	if err := json.NewDecoder(reader).Decode(&overrides); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeymapParseFailed, err)
+	}
+
+	keymap := DefaultKeymap()
+
+	for name, chords := range overrides {
+		command, err := ParseCommand(name)
+		if err != nil {
+			return nil, err
+		}
+
+		normalized := make([]string, 0, len(chords))
+		for _, chord := range chords {
+			canonical, err := NormalizeChord(chord)
+			if err != nil {
+				return nil, err
+			}
+
+			normalized = append(normalized, canonical)
+		}
+
+		keymap[command] = normalized
+	}
+
+	if err := keymap.Validate(); err != nil {
+		return nil, err
+	}
+
+	return keymap, nil
+}
+
+// LoadKeymapFile loads key bindings from the JSON file at path. A missing
+// file is not an error; the default keymap is returned instead.
+func LoadKeymapFile(path string) (Keymap, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return DefaultKeymap(), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	keymap, err := LoadKeymap(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return keymap, nil
+}
+
+// MarshalJSON encodes the keymap as an object mapping command names to lists
+// of chords, the format read by LoadKeymap.
+func (keymap Keymap) MarshalJSON() ([]byte, error) {
+	bindings := make(map[string][]string, len(keymap))
+	for command, chords := range keymap {
+		bindings[command.String()] = chords
+	}
+
+	return json.Marshal(bindings)
+}
+
+// UnmarshalJSON decodes key bindings the way LoadKeymap does, on top of the
+// default keymap.
+func (keymap *Keymap) UnmarshalJSON(data []byte) error {
+	loaded, err := LoadKeymap(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	*keymap = loaded
+
+	return nil
+}
+
+// SaveFile writes the keymap to path as JSON, creating parent directories as
+// needed.
+func (keymap Keymap) SaveFile(path string) error {
+	data, err := json.MarshalIndent(keymap, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Clone returns a copy of the keymap that can be modified independently.
+func (keymap Keymap) Clone() Keymap {
+	clone := make(Keymap, len(keymap))
+	for command, chords := range keymap {
+		clone[command] = slices.Clone(chords)
+	}
+
+	return clone
+}
+
+// Lookup returns the command bound to chord within context.
+func (keymap Keymap) Lookup(context InputContext, chord string) (Command, bool) {
+	canonical, err := NormalizeChord(chord)
+	if err != nil {
+		return CommandNone, false
+	}
+
+	for _, command := range Commands() {
+		if command.Contexts()&context == 0 {
+			continue
+		}
+
+		if containsChord(keymap[command], canonical) {
+			return command, true
+		}
+	}
+
+	return CommandNone, false
+}
+
+// Validate checks that no chord is bound to two commands that can be issued
+// in the same context.
+func (keymap Keymap) Validate() error {
+	var conflicts []error
+
+	commands := Commands()
+	for i, command := range commands {
+		for _, other := range commands[i+1:] {
+			if command.Contexts()&other.Contexts() == 0 {
+				continue
+			}
+
+			for _, chord := range keymap[command] {
+				if containsChord(keymap[other], chord) {
+					conflicts = append(conflicts, fmt.Errorf("%w: %s is bound to both %s and %s", ErrKeyBindingConflict, chord, command, other))
+				}
+			}
+		}
+	}
+
+	return errors.Join(conflicts...)
+}
+
+// Label returns a short description of the chords bound to command, such as
+// "k, Numpad8, ↑".
+func (keymap Keymap) Label(command Command) string {
+	labels := make([]string, 0, len(keymap[command]))
+	for _, chord := range keymap[command] {
+		labels = append(labels, ChordLabel(chord))
+	}
+
+	return strings.Join(labels, ", ")
+}
+
+// ControlsMarkdown renders the keymap as the Markdown tables used in the
+// README, one table per command group.
+func (keymap Keymap) ControlsMarkdown() string {
+	var builder strings.Builder
+
+	for i, group := range []string{"Interface", "Movement", "Actions", "Replay"} {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+
+		fmt.Fprintf(&builder, "### %s\n\n", group)
+		builder.WriteString("| Action | Keys |\n")
+		builder.WriteString("| ------ | ---- |\n")
+
+		for _, command := range Commands() {
+			if command.Group() != group {
+				continue
+			}
+
+			fmt.Fprintf(&builder, "| %s | %s |\n", command.Description(), keymap.Label(command))
+		}
+	}
+
+	return builder.String()
+}
+
+// NormalizeChord returns chord in canonical form, with modifiers in a fixed
+// order and capitalized names, so that "shift+q" and "Shift+Q" compare equal.
+func NormalizeChord(chord string) (string, error) {
+	parts := strings.Split(strings.TrimSpace(chord), "+")
+	key := strings.TrimSpace(parts[len(parts)-1])
+
+	if key == "" {
+		return "", fmt.Errorf("%w: %q", ErrInvalidChord, chord)
+	}
+
+	held := make(map[string]bool)
+	for _, part := range parts[:len(parts)-1] {
+		modifier := strings.TrimSpace(part)
+		if strings.EqualFold(modifier, "Control") {
+			modifier = "Ctrl"
+		}
+
+		index := slices.IndexFunc(chordModifiers, func(name string) bool {
+			return strings.EqualFold(name, modifier)
+		})
+
+		if index < 0 {
+			return "", fmt.Errorf("%w: %q has unknown modifier %q", ErrInvalidChord, chord, part)
+		}
+
+		held[chordModifiers[index]] = true
+	}
+
+	canonical := make([]string, 0, len(parts))
+	for _, modifier := range chordModifiers {
+		if held[modifier] {
+			canonical = append(canonical, modifier)
+		}
+	}
+
+	canonical = append(canonical, strings.ToUpper(key[:1])+key[1:])
+
+	return strings.Join(canonical, "+"), nil
+}
+
+// containsChord reports whether chords contains chord, ignoring case.
+func containsChord(chords []string, chord string) bool {
+	return slices.ContainsFunc(chords, func(candidate string) bool {
+		return strings.EqualFold(candidate, chord)
+	})
+}
+
+// SplitChord separates a canonical chord into its modifiers and key name.
+func SplitChord(chord string) (modifiers []string, key string) {
+	parts := strings.Split(chord, "+")
+
+	return parts[:len(parts)-1], parts[len(parts)-1]
+}
+
+// ChordLabel returns a short display label for chord. Single letters are
+// shown in lower case, or upper case when Shift is the only modifier, as is
+// customary for roguelikes.
+func ChordLabel(chord string) string {
+	modifiers, key := SplitChord(chord)
+
+	if label, ok := shiftedKeyLabels[key]; ok && slices.Equal(modifiers, []string{"Shift"}) {
+		return label
+	}
+
+	if label, ok := keyLabels[key]; ok {
+		key = label
+	}
+
+	if len(key) == 1 && key >= "A" && key <= "Z" {
+		if len(modifiers) == 1 && modifiers[0] == "Shift" {
+			return key
+		}
+
+		key = strings.ToLower(key)
+	}
+
+	return strings.Join(append(modifiers, key), "+")
+}
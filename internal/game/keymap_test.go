@@ -0,0 +1,188 @@
+package game
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDefaultKeymap(t *testing.T) {
+	t.Run("has no conflicts", func(t *testing.T) {
+		if err := DefaultKeymap().Validate(); err != nil {
+			t.Errorf("want no conflicts, got %v", err)
+		}
+	})
+
+	t.Run("binds every command", func(t *testing.T) {
+		keymap := DefaultKeymap()
+
+		for _, command := range Commands() {
+			if len(keymap[command]) == 0 {
+				t.Errorf("want %s to have a binding, got none", command)
+			}
+		}
+	})
+
+	t.Run("matches README controls", func(t *testing.T) {
+		readme, err := os.ReadFile("../../README.md")
+		if err != nil {
+			t.Fatalf("failed to read README: %v", err)
+		}
+
+		if !strings.Contains(string(readme), DefaultKeymap().ControlsMarkdown()) {
+			t.Error("README controls are out of date, run go generate ./internal/game")
+		}
+	})
+}
+
+func TestKeymapLookup(t *testing.T) {
+	tests := []struct {
+		name    string
+		context InputContext
+		chord   string
+		want    Command
+		wantOK  bool
+	}{
+		{name: "vi key while playing", context: ContextPlaying, chord: "K", want: CommandMoveUp, wantOK: true},
+		{name: "arrow while playing", context: ContextPlaying, chord: "ArrowLeft", want: CommandMoveLeft, wantOK: true},
+		{name: "same key in prompt", context: ContextPrompt, chord: "Y", want: CommandConfirm, wantOK: true},
+		{name: "case insensitive", context: ContextPlaying, chord: "shift+q", want: CommandQuit, wantOK: true},
+		{name: "modifier must match", context: ContextPlaying, chord: "Q", want: CommandNone, wantOK: false},
+		{name: "unbound in context", context: ContextTitle, chord: "H", want: CommandNone, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command, ok := DefaultKeymap().Lookup(tt.context, tt.chord)
+
+			if command != tt.want || ok != tt.wantOK {
+				t.Errorf("want (%s, %v), got (%s, %v)", tt.want, tt.wantOK, command, ok)
+			}
+		})
+	}
+}
+
+func TestLoadKeymap(t *testing.T) {
+	t.Run("overrides listed commands", func(t *testing.T) {
+		keymap, err := LoadKeymap(strings.NewReader(`{"move_up": ["w", "arrowup"]}`))
+		if err != nil {
+			t.Fatalf("failed to load keymap: %v", err)
+		}
+
+		if command, _ := keymap.Lookup(ContextPlaying, "W"); command != CommandMoveUp {
+			t.Errorf("want W bound to move_up, got %s", command)
+		}
+
+		if command, _ := keymap.Lookup(ContextPlaying, "K"); command != CommandNone {
+			t.Errorf("want K unbound after override, got %s", command)
+		}
+
+		if command, _ := keymap.Lookup(ContextPlaying, "J"); command != CommandMoveDown {
+			t.Errorf("want J to keep default move_down, got %s", command)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		json    string
+		wantErr error
+	}{
+		{name: "conflict in same context", json: `{"move_up": ["J"]}`, wantErr: ErrKeyBindingConflict},
+		{name: "unknown command", json: `{"fly": ["F"]}`, wantErr: ErrUnknownCommand},
+		{name: "unknown modifier", json: `{"quit": ["Hyper+Q"]}`, wantErr: ErrInvalidChord},
+		{name: "malformed json", json: `{"quit": `, wantErr: ErrKeymapParseFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadKeymap(strings.NewReader(tt.json))
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("want %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadKeymapFile(t *testing.T) {
+	t.Run("missing file uses defaults", func(t *testing.T) {
+		keymap, err := LoadKeymapFile(filepath.Join(t.TempDir(), "missing.json"))
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if keymap.Label(CommandQuit) != DefaultKeymap().Label(CommandQuit) {
+			t.Errorf("want default quit binding, got %s", keymap.Label(CommandQuit))
+		}
+	})
+
+	t.Run("round trips saved keymap", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config", "keymap.json")
+
+		keymap := DefaultKeymap()
+		keymap[CommandQuit] = []string{"Ctrl+C"}
+
+		if err := keymap.SaveFile(path); err != nil {
+			t.Fatalf("failed to save keymap: %v", err)
+		}
+
+		loaded, err := LoadKeymapFile(path)
+		if err != nil {
+			t.Fatalf("failed to load keymap: %v", err)
+		}
+
+		if command, _ := loaded.Lookup(ContextPlaying, "Ctrl+C"); command != CommandQuit {
+			t.Errorf("want Ctrl+C bound to quit, got %s", command)
+		}
+	})
+}
+
+func TestNormalizeChord(t *testing.T) {
+	tests := []struct {
+		chord string
+		want  string
+	}{
+		{chord: "q", want: "Q"},
+		{chord: "shift+q", want: "Shift+Q"},
+		{chord: "Shift+Control+X", want: "Ctrl+Shift+X"},
+		{chord: " alt + numpad8 ", want: "Alt+Numpad8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.chord, func(t *testing.T) {
+			got, err := NormalizeChord(tt.chord)
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestChordLabel(t *testing.T) {
+	tests := []struct {
+		chord string
+		want  string
+	}{
+		{chord: "K", want: "k"},
+		{chord: "Shift+Q", want: "Q"},
+		{chord: "Ctrl+C", want: "Ctrl+c"},
+		{chord: "ArrowUp", want: "↑"},
+		{chord: "Numpad8", want: "Numpad8"},
+		{chord: "Shift+Slash", want: "?"},
+		{chord: "Slash", want: "Slash"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.chord, func(t *testing.T) {
+			if got := ChordLabel(tt.chord); got != tt.want {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package game
+
+const (
+	statsPanelWidth    = 24 // statsPanelWidth is the width of the stats panel in columns
+	messageLogHeight   = 4  // messageLogHeight is the height of the message log in rows, including its separator
+	minViewportColumns = 20 // minViewportColumns is the narrowest map viewport the layout allows
+	minViewportRows    = 8  // minViewportRows is the shortest map viewport the layout allows
+)
+
+// ScreenLayout divides the screen, measured in tiles, into the map viewport,
+// the stats panel on its right, and the message log below it.
+type ScreenLayout struct {
+	Columns        int // Columns is the screen width in tiles
+	Rows           int // Rows is the screen height in tiles
+	ViewportWidth  int // ViewportWidth is the number of map columns shown
+	ViewportHeight int // ViewportHeight is the number of map rows shown
+}
+
+// NewScreenLayout lays out a screen of columns by rows tiles. Screens smaller
+// than the minimum viewport plus the panel and log are enlarged to fit.
+func NewScreenLayout(columns, rows int) ScreenLayout {
+	columns = max(columns, minViewportColumns+statsPanelWidth)
+	rows = max(rows, minViewportRows+messageLogHeight)
+
+	return ScreenLayout{
+		Columns:        columns,
+		Rows:           rows,
+		ViewportWidth:  columns - statsPanelWidth,
+		ViewportHeight: rows - messageLogHeight,
+	}
+}
+
+// MessageLines returns how many message lines fit below the log separator.
+func (layout ScreenLayout) MessageLines() int {
+	return layout.Rows - layout.ViewportHeight - 1
+}
+
+// ViewportBounds returns the map tiles visible in the viewport when centered
+// on the camera, as minX, minY, maxX, maxY with the max values exclusive. The
+// viewport is kept inside the map, and maps smaller than the viewport are
+// shown whole from their top left corner.
+func (layout ScreenLayout) ViewportBounds(cameraX, cameraY, mapWidth, mapHeight int) (int, int, int, int) {
+	minX, maxX := clampSpan(cameraX-layout.ViewportWidth/2, layout.ViewportWidth, mapWidth)
+	minY, maxY := clampSpan(cameraY-layout.ViewportHeight/2, layout.ViewportHeight, mapHeight)
+
+	return minX, minY, maxX, maxY
+}
+
+// clampSpan fits a span of length starting at start inside 0 to limit,
+// shrinking it to the whole range when it is longer than limit.
+func clampSpan(start, length, limit int) (int, int) {
+	if length >= limit {
+		return 0, limit
+	}
+
+	start = max(start, 0)
+	start = min(start, limit-length)
+
+	return start, start + length
+}
@@ -0,0 +1,63 @@
+package game
+
+import "testing"
+
+func TestNewScreenLayout(t *testing.T) {
+	t.Run("default window matches original layout", func(t *testing.T) {
+		layout := NewScreenLayout(80, 24)
+
+		if layout.ViewportWidth != 56 || layout.ViewportHeight != 20 {
+			t.Errorf("want viewport 56x20, got %dx%d", layout.ViewportWidth, layout.ViewportHeight)
+		}
+
+		if layout.MessageLines() != 3 {
+			t.Errorf("want 3 message lines, got %d", layout.MessageLines())
+		}
+	})
+
+	t.Run("larger window shows more map", func(t *testing.T) {
+		layout := NewScreenLayout(120, 40)
+
+		if layout.ViewportWidth != 96 || layout.ViewportHeight != 36 {
+			t.Errorf("want viewport 96x36, got %dx%d", layout.ViewportWidth, layout.ViewportHeight)
+		}
+	})
+
+	t.Run("tiny window keeps minimum viewport", func(t *testing.T) {
+		layout := NewScreenLayout(10, 5)
+
+		if layout.ViewportWidth != minViewportColumns || layout.ViewportHeight != minViewportRows {
+			t.Errorf("want viewport %dx%d, got %dx%d", minViewportColumns, minViewportRows, layout.ViewportWidth, layout.ViewportHeight)
+		}
+	})
+}
+
+func TestViewportBounds(t *testing.T) {
+	tests := []struct {
+		name             string
+		columns, rows    int
+		cameraX, cameraY int
+		mapWidth         int
+		mapHeight        int
+		want             [4]int
+	}{
+		{name: "centered", columns: 80, rows: 24, cameraX: 30, cameraY: 12, mapWidth: 80, mapHeight: 24, want: [4]int{2, 2, 58, 22}},
+		{name: "clamped top left", columns: 80, rows: 24, cameraX: 0, cameraY: 0, mapWidth: 80, mapHeight: 24, want: [4]int{0, 0, 56, 20}},
+		{name: "clamped bottom right", columns: 80, rows: 24, cameraX: 79, cameraY: 23, mapWidth: 80, mapHeight: 24, want: [4]int{24, 4, 80, 24}},
+		{name: "map smaller than viewport", columns: 80, rows: 24, cameraX: 5, cameraY: 3, mapWidth: 30, mapHeight: 10, want: [4]int{0, 0, 30, 10}},
+		{name: "window wider than map", columns: 160, rows: 24, cameraX: 70, cameraY: 12, mapWidth: 80, mapHeight: 24, want: [4]int{0, 2, 80, 22}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := NewScreenLayout(tt.columns, tt.rows)
+
+			minX, minY, maxX, maxY := layout.ViewportBounds(tt.cameraX, tt.cameraY, tt.mapWidth, tt.mapHeight)
+			got := [4]int{minX, minY, maxX, maxY}
+
+			if got != tt.want {
+				t.Errorf("want bounds %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package game
+
+// maxMessages is the number of messages kept in the message log.
+const maxMessages = 100
+
+// Message is a single line in the message log.
+type Message struct {
+	Text      string // Text is the message shown to the player.
+	Important bool   // Important marks messages that need the player's attention.
+	Turn      int    // Turn is the turn on which the message was added.
+}
+
+// AddMessage appends an informational message to the message log.
+func (game *Game) AddMessage(text string) {
+	game.appendMessage(Message{Text: text, Turn: game.TurnCount})
+}
+
+// AddImportantMessage appends a message that needs the player's attention and
+// interrupts any held-key movement so the player does not walk past it.
+func (game *Game) AddImportantMessage(text string) {
+	game.appendMessage(Message{Text: text, Important: true, Turn: game.TurnCount})
+	game.Interrupt()
+}
+
+// appendMessage adds message to the log, dropping the oldest message once the
+// log is full.
+func (game *Game) appendMessage(message Message) {
+	game.Messages = append(game.Messages, message)
+
+	if len(game.Messages) > maxMessages {
+		game.Messages = game.Messages[len(game.Messages)-maxMessages:]
+	}
+}
+
+// RecentMessages returns up to count of the newest messages, oldest first.
+func (game *Game) RecentMessages(count int) []Message {
+	start := max(len(game.Messages)-count, 0)
+
+	return game.Messages[start:]
+}
+
+// Interrupt stops held-key movement. It is called when something happens that
+// the player should not run past, such as a hostile coming into view or an
+// important message.
+func (game *Game) Interrupt() {
+	game.interrupted = true
+	game.CancelTravel()
+}
+
+// TakeInterrupt reports whether the game has been interrupted since the last
+// call and clears the interruption.
+func (game *Game) TakeInterrupt() bool {
+	interrupted := game.interrupted
+	game.interrupted = false
+
+	return interrupted
+}
+
+// SetViewSize tells the game how many tiles the map viewport shows, so it
+// can interrupt when a hostile comes into view. The size stays 0 without a
+// renderer, as in headless replays, which turns the check off.
+func (game *Game) SetViewSize(width, height int) {
+	game.viewWidth, game.viewHeight = width, height
+}
+
+// noticeHostiles interrupts when a hostile is in the viewport at a position
+// where none was in view at the end of the last turn.
+func (game *Game) noticeHostiles() {
+	if game.viewWidth <= 0 || game.viewHeight <= 0 {
+		return
+	}
+
+	layout := ScreenLayout{ViewportWidth: game.viewWidth, ViewportHeight: game.viewHeight}
+	minX, minY, maxX, maxY := layout.ViewportBounds(game.CameraX, game.CameraY, game.Width, game.Height)
+
+	seen := map[Point]bool{}
+	appeared := false
+
+	for _, entity := range game.Entities {
+		if !entity.Hostile() || entity.X < minX || entity.X >= maxX || entity.Y < minY || entity.Y >= maxY {
+			continue
+		}
+
+		p := Point{X: entity.X, Y: entity.Y}
+		seen[p] = true
+
+		if !game.hostilesSeen[p] {
+			appeared = true
+		}
+	}
+
+	game.hostilesSeen = seen
+
+	if appeared {
+		game.Interrupt()
+	}
+}
@@ -0,0 +1,81 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMessageLog(t *testing.T) {
+	t.Run("keeps newest messages", func(t *testing.T) {
+		game := NewGame()
+
+		for i := range maxMessages + 5 {
+			game.AddMessage(fmt.Sprintf("message %d", i))
+		}
+
+		if len(game.Messages) != maxMessages {
+			t.Errorf("want %d messages, got %d", maxMessages, len(game.Messages))
+		}
+
+		recent := game.RecentMessages(2)
+		if len(recent) != 2 || recent[1].Text != fmt.Sprintf("message %d", maxMessages+4) {
+			t.Errorf("want newest two messages, got %v", recent)
+		}
+	})
+
+	t.Run("important message interrupts once", func(t *testing.T) {
+		game := NewGame()
+
+		game.AddMessage("You hear a drone.")
+		if game.TakeInterrupt() {
+			t.Error("want ordinary message not to interrupt")
+		}
+
+		game.AddImportantMessage("A ganger spots you!")
+		if !game.TakeInterrupt() {
+			t.Error("want important message to interrupt")
+		}
+
+		if game.TakeInterrupt() {
+			t.Error("want interrupt cleared after it is taken")
+		}
+	})
+}
+
+func TestNoticeHostiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		viewSize int
+		entity   Entity
+		want     bool
+	}{
+		{name: "hostile comes into view", viewSize: 20, entity: Entity{Kind: EntityMonster, Name: "ganger", Color: RoleHostile, X: 20, Y: 9}, want: true},
+		{name: "hostile out of view", viewSize: 10, entity: Entity{Kind: EntityMonster, Name: "ganger", Color: RoleHostile, X: 70, Y: 16}},
+		{name: "friendly comes into view", viewSize: 20, entity: Entity{Kind: EntityMonster, Name: "worker", Color: RoleFriendly, Disposition: DispositionFriendly, X: 20, Y: 9}},
+		{name: "friendly colored hostile", viewSize: 20, entity: Entity{Kind: EntityMonster, Name: "ganger", Color: RoleFriendly, X: 20, Y: 9}, want: true},
+		{name: "item comes into view", viewSize: 20, entity: Entity{Kind: EntityItem, Name: "medkit", Color: RoleHostile, X: 20, Y: 9}},
+		{name: "no viewport", entity: Entity{Kind: EntityMonster, Name: "ganger", Color: RoleHostile, X: 20, Y: 9}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := NewGame()
+			game.StartGame()
+			game.SetViewSize(tt.viewSize, tt.viewSize)
+			game.Tick()
+			game.TakeInterrupt()
+
+			game.SpawnEntity(tt.entity)
+			game.Tick()
+
+			if got := game.TakeInterrupt(); got != tt.want {
+				t.Fatalf("want interrupt %v, got %v", tt.want, got)
+			}
+
+			game.Tick()
+			if game.TakeInterrupt() {
+				t.Error("want no interrupt for a hostile already in view")
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// morgueMessages is the number of recent messages included in a morgue file.
+const morgueMessages = 20
+
+// maxMorgueSuffix is the highest number tried after a morgue file name
+// before giving up, when files of the same name already exist.
+const maxMorgueSuffix = 100
+
+// MorgueText returns a character dump of the current run in plain text:
+// how the run ended, the character sheet, equipment, inventory, the last
+// messages, and a map of the level. ending describes how the run ended, such
+// as "Killed by a ganger." or "Quit the run.".
+func (game *Game) MorgueText(ending string) string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "Sprawlrunner character dump\n\n")
+	fmt.Fprintf(&builder, "%s, level %d runner\n", game.Player.Name, game.Player.Level)
+	fmt.Fprintf(&builder, "%s\n\n", ending)
+	fmt.Fprintf(&builder, "Seed:  %d\n", game.Seed)
+	fmt.Fprintf(&builder, "Depth: %d\n", game.Depth)
+	fmt.Fprintf(&builder, "Turns: %d\n", game.TurnCount)
+
+	if game.Wizard {
+		builder.WriteString("Wizard mode was used in this run.\n")
+	}
+
+	builder.WriteString("\n== Character ==\n\n")
+	fmt.Fprintf(&builder, "Name:   %s\n", game.Player.Name)
+	fmt.Fprintf(&builder, "Level:  %d\n", game.Player.Level)
+	fmt.Fprintf(&builder, "Health: %d\n", game.Player.Health)
+
+	builder.WriteString("\n== Equipment ==\n\nNothing equipped.\n")
+	builder.WriteString("\n== Inventory ==\n\nNothing carried.\n")
+
+	builder.WriteString("\n== Last Messages ==\n\n")
+
+	messages := game.RecentMessages(morgueMessages)
+	if len(messages) == 0 {
+		builder.WriteString("No messages.\n")
+	}
+
+	for _, message := range messages {
+		fmt.Fprintf(&builder, "%s\n", game.MessageText(message))
+	}
+
+	builder.WriteString("\n== Map ==\n\n")
+	builder.WriteString(game.MapText())
+
+	return builder.String()
+}
+
+// MapText draws the level as rows of glyphs with the player on top.
+func (game *Game) MapText() string {
+	var builder strings.Builder
+
+	for y, row := range game.Tiles {
+		for x, tile := range row {
+			if x == game.Player.X && y == game.Player.Y {
+				builder.WriteRune(game.Player.Glyph)
+				continue
+			}
+
+			if entity, ok := game.EntityAt(x, y); ok {
+				builder.WriteRune(entity.Glyph)
+				continue
+			}
+
+			builder.WriteRune(tile.Glyph)
+		}
+
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+// WriteMorgue writes the character dump to a new file in MorgueDir, named
+// after the player and the current time, and returns its path. A number is
+// added to the name rather than overwrite an earlier file. Returns an error
+// without writing anything if MorgueDir is empty.
+func (game *Game) WriteMorgue(ending string) (string, error) {
+	if game.MorgueDir == "" {
+		return "", ErrMorgueDisabled
+	}
+
+	if err := os.MkdirAll(game.MorgueDir, 0o755); err != nil {
+		return "", err
+	}
+
+	base := fmt.Sprintf("%s-%s", morgueName(game.Player.Name), time.Now().Format("20060102-150405"))
+
+	for suffix := 1; suffix <= maxMorgueSuffix; suffix++ {
+		name := base + ".txt"
+		if suffix > 1 {
+			name = fmt.Sprintf("%s-%d.txt", base, suffix)
+		}
+
+		path := filepath.Join(game.MorgueDir, name)
+
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		_, err = file.WriteString(game.MorgueText(ending))
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		return path, nil
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrMorgueExists, base)
+}
+
+// morgueName turns a player name into a safe file name: lower case letters,
+// digits, and single hyphens in place of anything else. A name with none of
+// those becomes "runner".
+func morgueName(playerName string) string {
+	var builder strings.Builder
+
+	hyphen := false
+	for _, r := range strings.ToLower(playerName) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			if hyphen && builder.Len() > 0 {
+				builder.WriteRune('-')
+			}
+
+			builder.WriteRune(r)
+			hyphen = false
+
+			continue
+		}
+
+		hyphen = true
+	}
+
+	if builder.Len() == 0 {
+		return "runner"
+	}
+
+	return builder.String()
+}
+
+// DumpCharacter writes a morgue file for the run in progress and reports
+// where it went in the message log.
+func (game *Game) DumpCharacter() {
+	path, err := game.WriteMorgue(fmt.Sprintf("Alive on turn %d.", game.TurnCount))
+	if err != nil {
+		game.AddMessage(fmt.Sprintf("Character dump failed: %v", err))
+		return
+	}
+
+	game.AddMessage(fmt.Sprintf("Character dumped to %s.", path))
+}
@@ -0,0 +1,150 @@
+package game
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMorgueText(t *testing.T) {
+	game := NewGame()
+	game.SetSeed(7)
+	game.AddMessage("You jack in.")
+	game.MovePlayer(1, 0)
+
+	text := game.MorgueText("Quit the run.")
+
+	for _, want := range []string{"Decker, level 1 runner", "Quit the run.", "Seed:  7", "Turns: 1", "Health: 100", "Nothing carried.", "You jack in."} {
+		if !strings.Contains(text, want) {
+			t.Errorf("want morgue containing %q, got:\n%s", want, text)
+		}
+	}
+}
+
+func TestMapText(t *testing.T) {
+	game := NewGame()
+
+	rows := strings.Split(strings.TrimSuffix(game.MapText(), "\n"), "\n")
+	if len(rows) != game.Height {
+		t.Fatalf("want %d rows, got %d", game.Height, len(rows))
+	}
+
+	if got := rows[game.Player.Y][game.Player.X]; got != '@' {
+		t.Errorf("want player at %d,%d, got %q", game.Player.X, game.Player.Y, got)
+	}
+
+	if got := rows[0][0]; got != '#' {
+		t.Errorf("want wall in the corner, got %q", got)
+	}
+}
+
+func TestWriteMorgue(t *testing.T) {
+	t.Run("writes to the morgue directory", func(t *testing.T) {
+		game := NewGame()
+		game.MorgueDir = filepath.Join(t.TempDir(), "morgue")
+
+		path, err := game.WriteMorgue("Quit the run.")
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read morgue: %v", err)
+		}
+
+		if !strings.Contains(string(data), "Quit the run.") {
+			t.Errorf("want morgue text in file, got:\n%s", data)
+		}
+	})
+
+	t.Run("two in the same second", func(t *testing.T) {
+		game := NewGame()
+		game.MorgueDir = t.TempDir()
+
+		first, err := game.WriteMorgue("Quit the run.")
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		second, err := game.WriteMorgue("Killed by a drone.")
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		// The clock may tick between writes, so only distinct paths and
+		// contents are certain
+		if first == second {
+			t.Fatalf("want two files, got %s twice", first)
+		}
+
+		data, err := os.ReadFile(first)
+		if err != nil || !strings.Contains(string(data), "Quit the run.") {
+			t.Errorf("want the first morgue kept, got %q, %v", data, err)
+		}
+	})
+
+	t.Run("disabled without a directory", func(t *testing.T) {
+		_, err := NewGame().WriteMorgue("Quit the run.")
+
+		if !errors.Is(err, ErrMorgueDisabled) {
+			t.Errorf("want %v, got %v", ErrMorgueDisabled, err)
+		}
+	})
+}
+
+func TestAbandonWritesMorgue(t *testing.T) {
+	game := NewGame()
+	game.MorgueDir = t.TempDir()
+	game.StartGame()
+
+	game.OpenPauseMenu(pauseAbandon)
+	game.HandleCommand(CommandConfirm)
+	game.HandleCommand(CommandConfirm)
+
+	entries, err := os.ReadDir(game.MorgueDir)
+	if err != nil {
+		t.Fatalf("failed to read morgue directory: %v", err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("want 1 morgue file, got %d", len(entries))
+	}
+}
+
+func TestHandleCommandMorgue(t *testing.T) {
+	game := NewGame()
+	game.MorgueDir = t.TempDir()
+	game.StartGame()
+
+	game.HandleCommand(CommandMorgue)
+
+	messages := game.RecentMessages(1)
+	if len(messages) != 1 || !strings.HasPrefix(messages[0].Text, "Character dumped to ") {
+		t.Errorf("want dump message, got %+v", messages)
+	}
+}
+
+func TestMorgueName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Decker", want: "decker"},
+		{name: "Razor Girl 2", want: "razor-girl-2"},
+		{name: "../../etc/passwd", want: "etc-passwd"},
+		{name: "  Neo--Tokyo  ", want: "neo-tokyo"},
+		{name: "Zoë", want: "zo"},
+		{name: "???", want: "runner"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := morgueName(tt.name); got != tt.want {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package game
+
+const (
+	// officeMinRoomWidth and officeMinRoomHeight are the smallest room
+	// interior the partition leaves, in tiles.
+	officeMinRoomWidth  = 6
+	officeMinRoomHeight = 4
+
+	// officeMaxRoomArea is the largest room left unsplit; areas at or under
+	// it stop splitting with officeStopChance, so room sizes vary.
+	officeMaxRoomArea = 120
+	officeStopChance  = 0.3
+
+	// officeStartDistance is how many tiles away from the player's start
+	// monsters are spawned, so nothing starts within reach.
+	officeStartDistance = 5
+)
+
+// Room purposes assigned by the office generator. Each is also the prefab
+// tag that furnishes the room.
+const (
+	PurposeLobby          = "lobby"
+	PurposeSecurityOffice = "security_office"
+	PurposeServerRoom     = "server_room"
+	PurposeOffice         = "office"
+)
+
+// officePurpose describes what a room of one purpose holds besides its
+// prefab: how many monsters and items with which tags.
+type officePurpose struct {
+	monsterTag string // monsterTag is the tag of the monsters spawned.
+	monsters   int    // monsters is how many monsters are spawned.
+	itemTag    string // itemTag is the tag of the items spawned.
+	items      int    // items is how many items are spawned.
+}
+
+// officePurposes lists the monsters and items spawned in each kind of room.
+var officePurposes = map[string]officePurpose{
+	PurposeLobby:          {monsterTag: "office", monsters: 1},
+	PurposeSecurityOffice: {monsterTag: "security", monsters: 2, itemTag: "weapon", items: 1},
+	PurposeServerRoom:     {monsterTag: "security", monsters: 1, itemTag: "corporate", items: 2},
+	PurposeOffice:         {monsterTag: "office", monsters: 1, itemTag: "loot", items: 1},
+}
+
+// officeRoom is a room laid out by the office generator.
+type officeRoom struct {
+	x, y          int    // x and y are the room's top-left floor tile.
+	width, height int    // width and height are the room's size in tiles.
+	purpose       string // purpose says what the room is for, such as PurposeLobby.
+}
+
+// generateOffice lays out a corporate floor by binary space partition: the
+// map is split into rooms sharing walls, with a doorway through every
+// split. The largest room is the lobby, where the player starts; one room
+// each becomes the security office and the server room, and the rest are
+// offices. Rooms are furnished with prefabs tagged with their purpose, the
+// player is placed, then monsters and items are spawned to suit each room,
+// keeping monsters away from the player's start.
+func (game *Game) generateOffice(width, height int) {
+	rooms := game.splitOffice(1, 1, width-2, height-2, nil)
+	game.assignPurposes(rooms)
+
+	for _, room := range rooms {
+		game.furnishOffice(room)
+	}
+
+	lobby := rooms[0]
+	x, y, ok := game.randomFreeTile(lobby, 0)
+	if !ok {
+		x, y = lobby.x+lobby.width/2, lobby.y+lobby.height/2
+		game.Tiles[y][x] = game.Content.Tile(TileFloor)
+
+		if entity, ok := game.EntityAt(x, y); ok {
+			game.RemoveEntity(entity)
+		}
+	}
+
+	game.Player.X = x
+	game.Player.Y = y
+
+	for _, room := range rooms {
+		game.populateOffice(room)
+	}
+}
+
+// splitOffice partitions the area at (x, y) into rooms, appending them to
+// rooms. Areas too small to split, or small enough and lucky, become a
+// room; others are split by a wall across their longer side, and a doorway
+// is cut through the wall once both halves are laid out.
+func (game *Game) splitOffice(x, y, width, height int, rooms []officeRoom) []officeRoom {
+	canSplitX := width >= 2*officeMinRoomWidth+1
+	canSplitY := height >= 2*officeMinRoomHeight+1
+
+	stop := width*height <= officeMaxRoomArea && game.rng.Float64() < officeStopChance
+	if stop || !canSplitX && !canSplitY {
+		for yPos := y; yPos < y+height; yPos++ {
+			for xPos := x; xPos < x+width; xPos++ {
+				game.Tiles[yPos][xPos] = game.Content.Tile(TileFloor)
+			}
+		}
+
+		return append(rooms, officeRoom{x: x, y: y, width: width, height: height})
+	}
+
+	// Split across the longer side; tiles are square, so the sides compare
+	// directly
+	if canSplitX && (!canSplitY || width >= height) {
+		wall := x + officeMinRoomWidth + game.rng.IntN(width-2*officeMinRoomWidth)
+		rooms = game.splitOffice(x, y, wall-x, height, rooms)
+		rooms = game.splitOffice(wall+1, y, x+width-wall-1, height, rooms)
+		game.cutDoorway(wall, y, 0, 1, height)
+
+		return rooms
+	}
+
+	wall := y + officeMinRoomHeight + game.rng.IntN(height-2*officeMinRoomHeight)
+	rooms = game.splitOffice(x, y, width, wall-y, rooms)
+	rooms = game.splitOffice(x, wall+1, width, y+height-wall-1, rooms)
+	game.cutDoorway(x, wall, 1, 0, width)
+
+	return rooms
+}
+
+// cutDoorway opens a random tile of the wall running length tiles from
+// (x, y) in direction (dx, dy) that has floor on both sides. If no tile
+// does, the floors on either side are joined with a corridor instead.
+func (game *Game) cutDoorway(x, y, dx, dy, length int) {
+	// The sides of the wall are across it: left and right of a vertical
+	// wall, above and below a horizontal one
+	sideX, sideY := dy, dx
+
+	var doors []Point
+
+	for i := range length {
+		p := Point{X: x + dx*i, Y: y + dy*i}
+		if game.Tiles[p.Y-sideY][p.X-sideX].Walkable && game.Tiles[p.Y+sideY][p.X+sideX].Walkable {
+			doors = append(doors, p)
+		}
+	}
+
+	if len(doors) == 0 {
+		middle := Point{X: x + dx*(length/2), Y: y + dy*(length/2)}
+		game.CreateCorridor(middle.X-sideX, middle.Y-sideY, middle.X+sideX, middle.Y+sideY)
+
+		return
+	}
+
+	door := doors[game.rng.IntN(len(doors))]
+	game.Tiles[door.Y][door.X] = game.Content.Tile(TileFloor)
+}
+
+// assignPurposes makes the largest room the lobby and moves it first, then
+// picks a security office and a server room at random from the rest. Every
+// other room is an office.
+func (game *Game) assignPurposes(rooms []officeRoom) {
+	largest := 0
+	for i, room := range rooms {
+		if room.width*room.height > rooms[largest].width*rooms[largest].height {
+			largest = i
+		}
+	}
+
+	rooms[0], rooms[largest] = rooms[largest], rooms[0]
+	rooms[0].purpose = PurposeLobby
+
+	rest := rooms[1:]
+	game.rng.Shuffle(len(rest), func(i, j int) {
+		rest[i], rest[j] = rest[j], rest[i]
+	})
+
+	for i := range rest {
+		switch i {
+		case 0:
+			rest[i].purpose = PurposeSecurityOffice
+		case 1:
+			rest[i].purpose = PurposeServerRoom
+		default:
+			rest[i].purpose = PurposeOffice
+		}
+	}
+}
+
+// furnishOffice stamps a prefab for the room's purpose, leaving a ring of
+// floor around it so every doorway stays reachable.
+func (game *Game) furnishOffice(room officeRoom) {
+	game.stampFittingPrefab(room.x+1, room.y+1, room.width-2, room.height-2, room.purpose)
+}
+
+// populateOffice spawns the monsters and items the room's purpose calls for
+// on free floor. Monsters are kept officeStartDistance tiles from the
+// player, and are left out if the room has no floor that far away.
+func (game *Game) populateOffice(room officeRoom) {
+	purpose := officePurposes[room.purpose]
+
+	for range purpose.monsters {
+		def, ok := game.randomMonster(purpose.monsterTag)
+		if !ok {
+			break
+		}
+
+		if x, y, ok := game.randomFreeTile(room, officeStartDistance); ok {
+			game.SpawnEntity(def.Entity(x, y))
+		}
+	}
+
+	for range purpose.items {
+		def, ok := game.randomItem(purpose.itemTag)
+		if !ok {
+			break
+		}
+
+		if x, y, ok := game.randomFreeTile(room, 0); ok {
+			game.SpawnEntity(def.Entity(x, y))
+		}
+	}
+}
+
+// randomFreeTile picks a walkable tile in room with nothing on it, at least
+// distance tiles from the player in every direction.
+func (game *Game) randomFreeTile(room officeRoom, distance int) (int, int, bool) {
+	var free []Point
+
+	for y := room.y; y < room.y+room.height; y++ {
+		for x := room.x; x < room.x+room.width; x++ {
+			if !game.Tiles[y][x].Walkable {
+				continue
+			}
+
+			if _, taken := game.EntityAt(x, y); taken {
+				continue
+			}
+
+			if max(x-game.Player.X, game.Player.X-x, y-game.Player.Y, game.Player.Y-y) < distance {
+				continue
+			}
+
+			free = append(free, Point{X: x, Y: y})
+		}
+	}
+
+	if len(free) == 0 {
+		return 0, 0, false
+	}
+
+	p := free[game.rng.IntN(len(free))]
+
+	return p.X, p.Y, true
+}
@@ -0,0 +1,234 @@
+package game
+
+import (
+	"os"
+	"testing"
+)
+
+// officeGame returns a game whose level was laid out by the office
+// generator from seed, using the bundled content.
+func officeGame(t *testing.T, seed int64) *Game {
+	t.Helper()
+
+	content, err := LoadContent(os.DirFS("../../assets"), "content", "")
+	if err != nil {
+		t.Fatalf("failed to load content: %v", err)
+	}
+
+	game := NewGame()
+	game.SetSeed(seed)
+	game.Generator = GeneratorOffice
+	game.SetContent(content)
+
+	return game
+}
+
+func TestGenerateOffice(t *testing.T) {
+	for seed := range int64(20) {
+		game := officeGame(t, seed)
+
+		if !game.Tiles[game.Player.Y][game.Player.X].Walkable {
+			t.Fatalf("seed %d: want the player on a floor tile", seed)
+		}
+
+		if _, ok := game.EntityAt(game.Player.X, game.Player.Y); ok {
+			t.Fatalf("seed %d: want the player's tile free", seed)
+		}
+
+		floors := 0
+		for _, row := range game.Tiles {
+			for _, tile := range row {
+				if tile.Walkable {
+					floors++
+				}
+			}
+		}
+
+		if got := reachable(game, game.Player.X, game.Player.Y); got != floors {
+			t.Errorf("seed %d: want all %d floor tiles reachable through doorways, got %d", seed, floors, got)
+		}
+
+		if len(game.Entities) == 0 {
+			t.Errorf("seed %d: want monsters and items spawned, got none", seed)
+		}
+
+		for _, entity := range game.Entities {
+			dx, dy := entity.X-game.Player.X, entity.Y-game.Player.Y
+			if entity.Kind == EntityMonster && max(dx, -dx, dy, -dy) < officeStartDistance {
+				t.Errorf("seed %d: want monsters at least %d tiles from the start, got %s %d,%d away", seed, officeStartDistance, entity.Name, dx, dy)
+			}
+		}
+	}
+
+	t.Run("same seed, same floor", func(t *testing.T) {
+		first, second := officeGame(t, 4), officeGame(t, 4)
+
+		for y := range first.Tiles {
+			for x := range first.Tiles[y] {
+				if first.Tiles[y][x] != second.Tiles[y][x] {
+					t.Fatalf("want the same level, got different tiles at %d,%d", x, y)
+				}
+			}
+		}
+
+		if len(first.Entities) != len(second.Entities) {
+			t.Errorf("want the same entities, got %d and %d", len(first.Entities), len(second.Entities))
+		}
+	})
+}
+
+func TestSplitOffice(t *testing.T) {
+	game := NewGame()
+	game.SetSeed(3)
+
+	for y := range game.Tiles {
+		for x := range game.Tiles[y] {
+			game.Tiles[y][x] = WallTile
+		}
+	}
+
+	rooms := game.splitOffice(1, 1, game.Width-2, game.Height-2, nil)
+	if len(rooms) < 4 {
+		t.Fatalf("want at least 4 rooms, got %d", len(rooms))
+	}
+
+	area := 0
+	for _, room := range rooms {
+		if room.width < officeMinRoomWidth || room.height < officeMinRoomHeight {
+			t.Errorf("want rooms at least %dx%d, got %dx%d", officeMinRoomWidth, officeMinRoomHeight, room.width, room.height)
+		}
+
+		area += room.width * room.height
+	}
+
+	// Rooms and the walls between them cover the map inside its border
+	if area >= (game.Width-2)*(game.Height-2) {
+		t.Errorf("want walls between rooms, got rooms covering %d tiles", area)
+	}
+
+	game.assignPurposes(rooms)
+
+	counts := map[string]int{}
+	for _, room := range rooms {
+		counts[room.purpose]++
+
+		if room.width*room.height > rooms[0].width*rooms[0].height {
+			t.Errorf("want the lobby largest, got a %dx%d %s", room.width, room.height, room.purpose)
+		}
+	}
+
+	if rooms[0].purpose != PurposeLobby {
+		t.Errorf("want the lobby first, got %s", rooms[0].purpose)
+	}
+
+	for _, purpose := range []string{PurposeLobby, PurposeSecurityOffice, PurposeServerRoom} {
+		if counts[purpose] != 1 {
+			t.Errorf("want one %s, got %d", purpose, counts[purpose])
+		}
+	}
+
+	if counts[PurposeOffice] != len(rooms)-3 {
+		t.Errorf("want the other %d rooms to be offices, got %d", len(rooms)-3, counts[PurposeOffice])
+	}
+}
+
+func TestCutDoorway(t *testing.T) {
+	// Two rooms split by the wall in column 3
+	game := NewGame()
+	game.Width, game.Height = 7, 5
+	game.Tiles = make([][]Tile, game.Height)
+
+	for y := range game.Tiles {
+		game.Tiles[y] = make([]Tile, game.Width)
+
+		for x := range game.Tiles[y] {
+			game.Tiles[y][x] = WallTile
+			if y > 0 && y < 4 && x > 0 && x < 6 && x != 3 {
+				game.Tiles[y][x] = FloorTile
+			}
+		}
+	}
+
+	game.cutDoorway(3, 1, 0, 1, 3)
+
+	doors := 0
+	for y := 1; y < 4; y++ {
+		if game.Tiles[y][3].Walkable {
+			doors++
+		}
+	}
+
+	if doors != 1 {
+		t.Errorf("want one doorway, got %d", doors)
+	}
+}
+
+func TestFurnishOffice(t *testing.T) {
+	content := NewContent()
+	content.Add(ContentFile{
+		Tiles:    []TileDef{{ID: "server_rack", Name: "server rack", Glyph: "▓", Color: "accent"}},
+		Monsters: []MonsterDef{{ID: "drone", Name: "drone", Glyph: "d", Health: 5, Depth: 1, Tags: []string{"security"}}},
+		Items:    []ItemDef{{ID: "datachip", Name: "datachip", Glyph: "=", Tags: []string{"corporate"}}},
+		Prefabs: []PrefabDef{
+			{ID: "racks", Name: "racks", Map: "▓.▓\n...", Tags: []string{PurposeServerRoom}},
+			{ID: "desk", Name: "desk", Map: "#.#\n...", Tags: []string{PurposeOffice}},
+		},
+	})
+
+	game := NewGame()
+	game.SetSeed(1)
+	game.SetContent(content)
+	game.Entities = nil
+
+	room := officeRoom{x: 11, y: 6, width: 5, height: 4, purpose: PurposeServerRoom}
+	game.furnishOffice(room)
+
+	// Move the player well clear so every tile may take a monster
+	game.Player.X, game.Player.Y = 30, 20
+	game.populateOffice(room)
+
+	if game.Tiles[7][12].Glyph != '▓' {
+		t.Errorf("want the server room prefab stamped inside a ring of floor, got %q at 12,7", game.Tiles[7][12].Glyph)
+	}
+
+	monsters, items := 0, 0
+	for _, entity := range game.Entities {
+		if entity.X < room.x || entity.X >= room.x+room.width || entity.Y < room.y || entity.Y >= room.y+room.height {
+			t.Errorf("want entities inside the room, got %s at %d,%d", entity.Name, entity.X, entity.Y)
+		}
+
+		switch entity.ID {
+		case "drone":
+			monsters++
+		case "datachip":
+			items++
+		}
+	}
+
+	want := officePurposes[PurposeServerRoom]
+	if monsters != want.monsters || items != want.items {
+		t.Errorf("want %d drones and %d datachips, got %d and %d", want.monsters, want.items, monsters, items)
+	}
+}
+
+func TestPopulateOfficeKeepsAwayFromStart(t *testing.T) {
+	content := NewContent()
+	content.Add(ContentFile{
+		Monsters: []MonsterDef{{ID: "guard", Name: "guard", Glyph: "G", Health: 5, Depth: 1, Tags: []string{"office"}}},
+	})
+
+	game := NewGame()
+	game.SetSeed(1)
+	game.SetContent(content)
+	game.Entities = nil
+
+	// A lobby too small to hold a monster that far from the player
+	room := officeRoom{x: 11, y: 6, width: 5, height: 4, purpose: PurposeLobby}
+	game.Player.X, game.Player.Y = 13, 8
+
+	game.populateOffice(room)
+
+	if len(game.Entities) != 0 {
+		t.Errorf("want no monster spawned beside the player, got %+v", game.Entities)
+	}
+}
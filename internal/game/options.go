@@ -0,0 +1,122 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+)
+
+// option is one adjustable setting on the options screen.
+type option struct {
+	label  string                 // label names the setting.
+	value  func(*Settings) string // value describes the current value.
+	change func(*Settings)        // change steps to the next value.
+}
+
+// options lists the settings that can be changed in game.
+var options = []option{
+	{
+		label: "Theme",
+		value: func(settings *Settings) string { return settings.Theme },
+		change: func(settings *Settings) {
+			themes := ThemeNames()
+			settings.Theme = themes[(slices.Index(themes, settings.Theme)+1)%len(themes)]
+		},
+	},
+	{
+		label: "Font size",
+		value: func(settings *Settings) string { return fmt.Sprintf("%g", settings.FontSize) },
+		change: func(settings *Settings) {
+			settings.FontSize += fontSizeStep
+			if settings.FontSize > maxFontSize {
+				settings.FontSize = minFontSize
+			}
+		},
+	},
+	{
+		label:  "Graphical tiles",
+		value:  func(settings *Settings) string { return onOff(settings.GraphicalTiles) },
+		change: func(settings *Settings) { settings.GraphicalTiles = !settings.GraphicalTiles },
+	},
+	{
+		label:  "Glyph hints",
+		value:  func(settings *Settings) string { return onOff(settings.GlyphHints) },
+		change: func(settings *Settings) { settings.GlyphHints = !settings.GlyphHints },
+	},
+	{
+		label:  "Screen reader",
+		value:  func(settings *Settings) string { return onOff(settings.ScreenReader) },
+		change: func(settings *Settings) { settings.ScreenReader = !settings.ScreenReader },
+	},
+	{
+		label:  "Key repeat",
+		value:  func(settings *Settings) string { return onOff(settings.KeyRepeat.Enabled) },
+		change: func(settings *Settings) { settings.KeyRepeat.Enabled = !settings.KeyRepeat.Enabled },
+	},
+	{
+		label:  "Mouse",
+		value:  func(settings *Settings) string { return onOff(settings.Mouse) },
+		change: func(settings *Settings) { settings.Mouse = !settings.Mouse },
+	},
+	{
+		label:  "Gamepad",
+		value:  func(settings *Settings) string { return onOff(settings.Gamepad.Enabled) },
+		change: func(settings *Settings) { settings.Gamepad.Enabled = !settings.Gamepad.Enabled },
+	},
+}
+
+// OptionsScreen holds the state of the options screen.
+type OptionsScreen struct {
+	Cursor      int       // Cursor is the selected option.
+	Status      string    // Status reports a failure to save the settings.
+	returnState GameState // returnState is the state to go back to when the screen closes.
+}
+
+// onOff describes a boolean setting.
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+
+	return "off"
+}
+
+// OptionLabels returns each option with its current value, such as
+// "Theme: neon".
+func (game *Game) OptionLabels() []string {
+	labels := make([]string, len(options))
+	for i, option := range options {
+		labels[i] = fmt.Sprintf("%s: %s", option.label, option.value(&game.Settings))
+	}
+
+	return labels
+}
+
+// OpenOptions shows the options screen. Closing it returns to the current
+// state.
+func (game *Game) OpenOptions() {
+	game.Options = OptionsScreen{returnState: game.State}
+	game.State = StateOptions
+}
+
+// handleOptionsCommand moves the options selection, changes the selected
+// option, or closes the screen, saving the settings to SettingsPath when one
+// is set.
+func (game *Game) handleOptionsCommand(command Command) {
+	switch command {
+	case CommandMenuUp:
+		game.Options.Cursor = wrapCursor(game.Options.Cursor, -1, len(options))
+	case CommandMenuDown:
+		game.Options.Cursor = wrapCursor(game.Options.Cursor, 1, len(options))
+	case CommandConfirm:
+		options[game.Options.Cursor].change(&game.Settings)
+	case CommandCancel:
+		if game.SettingsPath != "" {
+			if err := game.Settings.SaveFile(game.SettingsPath); err != nil {
+				game.Options.Status = err.Error()
+				return
+			}
+		}
+
+		game.State = game.Options.returnState
+	}
+}
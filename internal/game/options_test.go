@@ -0,0 +1,70 @@
+package game
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestOptionsScreen(t *testing.T) {
+	game := NewGame()
+	game.SettingsPath = filepath.Join(t.TempDir(), "settings.json")
+	game.StartGame()
+	game.OpenPauseMenu(pauseOptions)
+	game.HandleCommand(CommandConfirm)
+
+	if game.State != StateOptions {
+		t.Fatalf("want state %v, got %v", StateOptions, game.State)
+	}
+
+	// The first option cycles the theme
+	game.HandleCommand(CommandConfirm)
+
+	if game.Settings.Theme == DefaultTheme {
+		t.Errorf("want theme changed, got %s", game.Settings.Theme)
+	}
+
+	game.HandleCommand(CommandCancel)
+
+	if game.State != StatePaused {
+		t.Errorf("want pause menu after closing options, got state %v", game.State)
+	}
+
+	saved, err := LoadSettingsFile(game.SettingsPath)
+	if err != nil {
+		t.Fatalf("failed to load saved settings: %v", err)
+	}
+
+	if saved != game.Settings {
+		t.Errorf("want saved settings %+v, got %+v", game.Settings, saved)
+	}
+}
+
+func TestOptionChanges(t *testing.T) {
+	for i, option := range options {
+		t.Run(option.label, func(t *testing.T) {
+			settings := DefaultSettings()
+			before := option.value(&settings)
+
+			option.change(&settings)
+
+			if option.value(&settings) == before {
+				t.Errorf("want option %d to change from %s", i, before)
+			}
+
+			if err := settings.Validate(); err != nil {
+				t.Errorf("want valid settings, got %v", err)
+			}
+		})
+	}
+
+	t.Run("font size wraps", func(t *testing.T) {
+		settings := DefaultSettings()
+		settings.FontSize = maxFontSize
+
+		options[1].change(&settings)
+
+		if settings.FontSize != minFontSize {
+			t.Errorf("want font size %d, got %g", minFontSize, settings.FontSize)
+		}
+	})
+}
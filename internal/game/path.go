@@ -0,0 +1,153 @@
+package game
+
+// Point is a position on the map in tile coordinates.
+type Point struct {
+	X int // X is the horizontal tile coordinate
+	Y int // Y is the vertical tile coordinate
+}
+
+// neighborOffsets lists the eight directions the player can step in.
+var neighborOffsets = []Point{
+	{X: 0, Y: -1}, {X: 0, Y: 1}, {X: -1, Y: 0}, {X: 1, Y: 0},
+	{X: -1, Y: -1}, {X: 1, Y: -1}, {X: -1, Y: 1}, {X: 1, Y: 1},
+}
+
+// InBounds reports whether (x, y) is inside the map.
+func (game *Game) InBounds(x, y int) bool {
+	return x >= 0 && x < game.Width && y >= 0 && y < game.Height
+}
+
+// Visible reports whether the tile at (x, y) and anything on it is drawn:
+// everything on the map when Revealed is set, otherwise what is in sight.
+func (game *Game) Visible(x, y int) bool {
+	return game.InBounds(x, y) && (game.Revealed || game.inSight(x, y))
+}
+
+// inSight reports whether the player can see (x, y). There is no fog of war
+// yet, so every tile is in sight.
+func (game *Game) inSight(x, y int) bool {
+	return true
+}
+
+// FindPath returns the shortest sequence of steps from one tile to another,
+// moving in eight directions over walkable tiles. The starting tile is not
+// included. Returns nil if the destination cannot be reached.
+func (game *Game) FindPath(from, to Point) []Point {
+	if !game.InBounds(to.X, to.Y) || !game.Tiles[to.Y][to.X].Walkable || from == to {
+		return nil
+	}
+
+	// Breadth first search is enough for maps this size and always finds the
+	// path with the fewest steps since every step costs one turn.
+	cameFrom := map[Point]Point{from: from}
+	queue := []Point{from}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current == to {
+			break
+		}
+
+		for _, offset := range neighborOffsets {
+			next := Point{X: current.X + offset.X, Y: current.Y + offset.Y}
+
+			if !game.InBounds(next.X, next.Y) || !game.Tiles[next.Y][next.X].Walkable {
+				continue
+			}
+
+			if _, seen := cameFrom[next]; seen {
+				continue
+			}
+
+			cameFrom[next] = current
+			queue = append(queue, next)
+		}
+	}
+
+	if _, reached := cameFrom[to]; !reached {
+		return nil
+	}
+
+	var path []Point
+	for step := to; step != from; step = cameFrom[step] {
+		path = append([]Point{step}, path...)
+	}
+
+	return path
+}
+
+// TravelTo plans a route for the player to the given tile. The player walks
+// the route one step at a time through StepTravel. Returns false and logs a
+// message if the tile cannot be reached.
+func (game *Game) TravelTo(x, y int) bool {
+	game.record(ReplayStep{Kind: StepTravel, X: x, Y: y})
+
+	path := game.FindPath(Point{X: game.Player.X, Y: game.Player.Y}, Point{X: x, Y: y})
+	if path == nil {
+		game.AddMessage("You can't get there.")
+		return false
+	}
+
+	game.travelPath = path
+
+	return true
+}
+
+// IsTraveling returns true while the player is following a travel route.
+func (game *Game) IsTraveling() bool {
+	return len(game.travelPath) > 0
+}
+
+// StepTravel moves the player one step along the travel route, spending a
+// turn. Travel stops early if the step is blocked. Returns true while more
+// steps remain.
+func (game *Game) StepTravel() bool {
+	game.record(ReplayStep{Kind: StepTravelStep})
+
+	if !game.IsTraveling() {
+		return false
+	}
+
+	next := game.travelPath[0]
+	game.MovePlayer(next.X-game.Player.X, next.Y-game.Player.Y)
+
+	if game.Player.X != next.X || game.Player.Y != next.Y {
+		game.CancelTravel()
+		return false
+	}
+
+	game.travelPath = game.travelPath[1:]
+
+	return game.IsTraveling()
+}
+
+// CancelTravel abandons the current travel route.
+func (game *Game) CancelTravel() {
+	game.travelPath = nil
+}
+
+// Examine describes the tile at (x, y) in the message log.
+func (game *Game) Examine(x, y int) {
+	game.record(ReplayStep{Kind: StepExamine, X: x, Y: y})
+	game.AddMessage(game.Describe(x, y))
+}
+
+// Describe returns what the player sees at (x, y), used when examining a
+// tile.
+func (game *Game) Describe(x, y int) string {
+	if !game.InBounds(x, y) {
+		return "There is nothing there."
+	}
+
+	if game.Player.X == x && game.Player.Y == y {
+		return "You see yourself, " + game.Player.Name + "."
+	}
+
+	if entity, ok := game.EntityAt(x, y); ok {
+		return "You see a " + game.EntityName(*entity) + "."
+	}
+
+	return "You see a " + game.Tiles[y][x].Name + "."
+}
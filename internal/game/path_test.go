@@ -0,0 +1,142 @@
+package game
+
+import "testing"
+
+func TestFindPath(t *testing.T) {
+	t.Run("finds shortest path within a room", func(t *testing.T) {
+		game := NewGame()
+
+		path := game.FindPath(Point{X: 12, Y: 7}, Point{X: 15, Y: 10})
+
+		// Diagonal steps make this three moves
+		if len(path) != 3 {
+			t.Fatalf("want 3 steps, got %d: %v", len(path), path)
+		}
+
+		if path[len(path)-1] != (Point{X: 15, Y: 10}) {
+			t.Errorf("want path to end at (15,10), got %v", path[len(path)-1])
+		}
+	})
+
+	t.Run("follows corridors between rooms", func(t *testing.T) {
+		game := NewGame()
+
+		path := game.FindPath(Point{X: 17, Y: 9}, Point{X: 64, Y: 16})
+		if path == nil {
+			t.Fatal("want a path between room 1 and room 3, got nil")
+		}
+
+		for _, step := range path {
+			if !game.Tiles[step.Y][step.X].Walkable {
+				t.Errorf("want every step walkable, got wall at %v", step)
+			}
+		}
+	})
+
+	tests := []struct {
+		name string
+		to   Point
+	}{
+		{name: "wall", to: Point{X: 0, Y: 0}},
+		{name: "off map", to: Point{X: -1, Y: 5}},
+		{name: "same tile", to: Point{X: 17, Y: 9}},
+	}
+
+	for _, tt := range tests {
+		t.Run("no path to "+tt.name, func(t *testing.T) {
+			game := NewGame()
+
+			if path := game.FindPath(Point{X: 17, Y: 9}, tt.to); path != nil {
+				t.Errorf("want nil path, got %v", path)
+			}
+		})
+	}
+}
+
+func TestTravel(t *testing.T) {
+	t.Run("walks route one turn per step", func(t *testing.T) {
+		game := NewGame()
+
+		if !game.TravelTo(20, 9) {
+			t.Fatal("want travel to start, got false")
+		}
+
+		for game.StepTravel() {
+		}
+
+		if game.Player.X != 20 || game.Player.Y != 9 {
+			t.Errorf("want player at (20,9), got (%d,%d)", game.Player.X, game.Player.Y)
+		}
+
+		if game.TurnCount != 3 {
+			t.Errorf("want TurnCount 3, got %d", game.TurnCount)
+		}
+	})
+
+	t.Run("unreachable target logs a message", func(t *testing.T) {
+		game := NewGame()
+
+		if game.TravelTo(0, 0) {
+			t.Error("want travel into wall to fail, got true")
+		}
+
+		if len(game.Messages) != 1 {
+			t.Errorf("want 1 message, got %d", len(game.Messages))
+		}
+	})
+
+	t.Run("interrupt stops travel", func(t *testing.T) {
+		game := NewGame()
+		game.TravelTo(20, 9)
+
+		game.StepTravel()
+		game.AddImportantMessage("Alarm!")
+
+		if game.IsTraveling() {
+			t.Error("want travel cancelled by interrupt, still traveling")
+		}
+
+		if game.StepTravel() || game.Player.X != 18 {
+			t.Errorf("want no step after the interrupt, got player at x %d", game.Player.X)
+		}
+	})
+
+	t.Run("damage stops travel", func(t *testing.T) {
+		game := NewGame()
+		game.TravelTo(20, 9)
+
+		game.StepTravel()
+		game.DamagePlayer(5, "a stray round")
+
+		if game.StepTravel() || game.Player.X != 18 {
+			t.Errorf("want travel stopped by damage, got player at x %d", game.Player.X)
+		}
+
+		if !game.TakeInterrupt() {
+			t.Error("want damage to interrupt held-key movement")
+		}
+	})
+}
+
+func TestDescribe(t *testing.T) {
+	game := NewGame()
+
+	tests := []struct {
+		name string
+		x, y int
+		want string
+	}{
+		{name: "player", x: 17, y: 9, want: "You see yourself, Decker."},
+		{name: "floor", x: 15, y: 7, want: "You see a floor."},
+		{name: "wall", x: 0, y: 0, want: "You see a wall."},
+		{name: "off map", x: -1, y: 0, want: "There is nothing there."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := game.Describe(tt.x, tt.y); got != tt.want {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package game
+
+import "fmt"
+
+// Entries of the pause menu, indexing PauseMenu.
+const (
+	pauseResume = iota
+	pauseSave
+	pauseOptions
+	pauseControls
+	pauseSaveAndQuit
+	pauseAbandon
+)
+
+// PauseMenu lists the choices offered on the pause menu.
+var PauseMenu = []string{
+	pauseResume:      "Resume",
+	pauseSave:        "Save",
+	pauseOptions:     "Options",
+	pauseControls:    "Controls",
+	pauseSaveAndQuit: "Save and quit",
+	pauseAbandon:     "Abandon run",
+}
+
+// PauseScreen holds the state of the pause menu.
+type PauseScreen struct {
+	Menu       List   // Menu lists the PauseMenu entries.
+	Abandon    Dialog // Abandon asks whether to abandon the run.
+	Confirming bool   // Confirming is true while the abandon dialog is shown.
+}
+
+// OpenPauseMenu shows the pause menu over the map with cursor selected.
+func (game *Game) OpenPauseMenu(cursor int) {
+	game.Pause = PauseScreen{
+		Menu:    NewList(PauseMenu, len(PauseMenu), game.Keymap.FreeLetters(ContextMenu)),
+		Abandon: Dialog{Question: "Abandon this run?"},
+	}
+	game.Pause.Menu.Select(cursor)
+	game.State = StatePaused
+}
+
+// handlePauseCommand moves the pause menu selection, runs the selected entry,
+// or resumes play. Returns true if the game should exit.
+func (game *Game) handlePauseCommand(command Command) bool {
+	switch game.Pause.Menu.HandleCommand(command) {
+	case WidgetSubmitted:
+		return game.selectPauseEntry()
+	case WidgetCancelled:
+		game.State = StatePlaying
+	}
+
+	return false
+}
+
+// selectPauseEntry runs the selected pause menu entry. Returns true if the
+// game should exit.
+func (game *Game) selectPauseEntry() bool {
+	switch game.Pause.Menu.Cursor {
+	case pauseResume:
+		game.State = StatePlaying
+	case pauseSave:
+		game.State = StatePlaying
+
+		if err := game.SaveGame(); err != nil {
+			game.AddImportantMessage(fmt.Sprintf("Saving failed: %v", err))
+			return false
+		}
+
+		game.AddMessage("Game saved.")
+	case pauseOptions:
+		game.OpenOptions()
+	case pauseControls:
+		game.OpenKeyBindings()
+	case pauseSaveAndQuit:
+		if err := game.SaveGame(); err != nil {
+			game.State = StatePlaying
+			game.AddImportantMessage(fmt.Sprintf("Saving failed: %v", err))
+			return false
+		}
+
+		return true
+	case pauseAbandon:
+		game.Pause.Confirming = true
+	}
+
+	return false
+}
+
+// handleAbandonPrompt answers the question whether to abandon the run.
+// Abandoning ends the run and returns to the title screen.
+func (game *Game) handleAbandonPrompt(command Command) {
+	switch game.Pause.Abandon.HandleCommand(command) {
+	case WidgetSubmitted:
+		game.EndRun(fmt.Sprintf("Abandoned the run on turn %d.", game.TurnCount))
+		game.NewRun()
+	case WidgetCancelled:
+		game.Pause.Confirming = false
+	}
+}
@@ -0,0 +1,94 @@
+package game
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPauseMenu(t *testing.T) {
+	tests := []struct {
+		name      string
+		cursor    int
+		commands  []Command
+		wantState GameState
+		wantExit  bool
+	}{
+		{name: "escape resumes", commands: []Command{CommandCancel}, wantState: StatePlaying},
+		{name: "resume", cursor: pauseResume, commands: []Command{CommandConfirm}, wantState: StatePlaying},
+		{name: "save", cursor: pauseSave, commands: []Command{CommandConfirm}, wantState: StatePlaying},
+		{name: "options", cursor: pauseOptions, commands: []Command{CommandConfirm}, wantState: StateOptions},
+		{name: "controls", cursor: pauseControls, commands: []Command{CommandConfirm}, wantState: StateKeyBindings},
+		{name: "save and quit", cursor: pauseSaveAndQuit, commands: []Command{CommandConfirm}, wantState: StatePaused, wantExit: true},
+		{name: "abandon declined", cursor: pauseAbandon, commands: []Command{CommandConfirm, CommandCancel}, wantState: StatePaused},
+		{name: "abandon confirmed", cursor: pauseAbandon, commands: []Command{CommandConfirm, CommandConfirm}, wantState: StateTitleScreen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := NewGame()
+			game.SavePath = filepath.Join(t.TempDir(), "save.json")
+			game.StartGame()
+			game.HandleCommand(CommandPause)
+			game.Pause.Menu.Cursor = tt.cursor
+
+			exit := false
+			for _, command := range tt.commands {
+				exit = game.HandleCommand(command)
+			}
+
+			if exit != tt.wantExit {
+				t.Errorf("want exit %v, got %v", tt.wantExit, exit)
+			}
+
+			if game.State != tt.wantState {
+				t.Errorf("want state %v, got %v", tt.wantState, game.State)
+			}
+		})
+	}
+}
+
+func TestPauseMenuPrompt(t *testing.T) {
+	game := NewGame()
+	game.StartGame()
+	game.OpenPauseMenu(pauseAbandon)
+
+	game.HandleCommand(CommandConfirm)
+
+	if game.InputContext() != ContextPrompt {
+		t.Errorf("want prompt context while confirming, got %v", game.InputContext())
+	}
+}
+
+func TestSaveAndQuitWithoutSavePath(t *testing.T) {
+	game := NewGame()
+	game.StartGame()
+	game.OpenPauseMenu(pauseSaveAndQuit)
+
+	if game.HandleCommand(CommandConfirm) {
+		t.Error("want the game kept open when saving fails, got exit")
+	}
+
+	if game.State != StatePlaying {
+		t.Errorf("want state %v, got %v", StatePlaying, game.State)
+	}
+}
+
+func TestAbandonDeletesSave(t *testing.T) {
+	game := NewGame()
+	game.SavePath = filepath.Join(t.TempDir(), "save.json")
+	game.StartGame()
+
+	if err := game.SaveGame(); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	game.OpenPauseMenu(pauseAbandon)
+	game.HandleCommand(CommandConfirm)
+	game.HandleCommand(CommandConfirm)
+
+	if _, err := os.Stat(game.SavePath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("want save deleted, got %v", err)
+	}
+}
@@ -1,15 +1,20 @@
 // Package game contains core game state and logic independent of rendering.
 package game
 
-import "image/color"
+// maxHealth is the player's health at the start of a run and the most
+// healing can restore.
+const maxHealth = 100
 
 // Player represents the runner controlled by the user.
 type Player struct {
-	X      int         // X is the player's horizontal position in tile coordinates
-	Y      int         // Y is the player's vertical position in tile coordinates
-	Glyph  rune        // Glyph is the rune used to render the player
-	Color  color.Color // Color is the color used to render the player
-	Name   string      // Name is the player's name
-	Level  int         // Level is the player's experience Level
-	Health int         // Health is the player's hit points
+	X         int       // X is the player's horizontal position in tile coordinates
+	Y         int       // Y is the player's vertical position in tile coordinates
+	Glyph     rune      // Glyph is the rune used to render the player
+	Color     ColorRole // Color is the palette role used to render the player
+	Name      string    // Name is the player's name
+	Archetype string    // Archetype is the player's character class, such as Decker
+	Level     int       // Level is the player's experience Level
+	Health    int       // Health is the player's hit points
+	Karma     int       // Karma is the experience the player has earned
+	Nuyen     int       // Nuyen is the money the player carries
 }
@@ -0,0 +1,458 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// prefabChance is the chance that a room is furnished with a prefab when
+// one fits.
+const prefabChance = 0.5
+
+// PrefabLegend says what a character in a prefab map places.
+type PrefabLegend struct {
+	Tile    string `json:"tile,omitempty" toml:"tile"`       // Tile is the ID of the tile; defaults to the floor.
+	Item    string `json:"item,omitempty" toml:"item"`       // Item is the ID of an item to place.
+	Monster string `json:"monster,omitempty" toml:"monster"` // Monster is the ID of a monster to place.
+	Spawn   string `json:"spawn,omitempty" toml:"spawn"`     // Spawn is a monster tag; a random monster with it is placed.
+}
+
+// PrefabDef defines a hand-authored map chunk. The map is drawn with tile
+// glyphs, characters from the legend, and spaces, which leave the level's
+// tile as it was.
+type PrefabDef struct {
+	ID     string                  `json:"id" toml:"id"`                   // ID names the prefab in other definitions.
+	Name   string                  `json:"name" toml:"name"`               // Name is shown in errors.
+	Map    string                  `json:"map" toml:"map"`                 // Map is the chunk, one line per row.
+	Legend map[string]PrefabLegend `json:"legend,omitempty" toml:"legend"` // Legend maps map characters to what they place.
+	Rotate bool                    `json:"rotate,omitempty" toml:"rotate"` // Rotate lets generators turn the prefab.
+	Mirror bool                    `json:"mirror,omitempty" toml:"mirror"` // Mirror lets generators flip the prefab.
+	Tags   []string                `json:"tags,omitempty" toml:"tags"`     // Tags group prefabs for generators, such as "corporate".
+}
+
+// prefabCell is what one prefab map character places.
+type prefabCell struct {
+	keep    bool   // keep leaves the level's tile as it was.
+	tile    Tile   // tile is the terrain placed.
+	item    string // item is the ID of an item placed, if any.
+	monster string // monster is the ID of a monster placed, if any.
+	spawn   string // spawn is the tag of a random monster placed, if any.
+}
+
+// Prefab is a prefab with its characters resolved, ready to stamp.
+type Prefab struct {
+	ID     string         // ID is the definition's ID.
+	Rotate bool           // Rotate is true if the prefab may be turned.
+	Mirror bool           // Mirror is true if the prefab may be flipped.
+	cells  [][]prefabCell // cells are the resolved characters, indexed [y][x].
+}
+
+// rows splits the map into lines, dropping the blank first and last lines
+// left by multi-line strings.
+func (def PrefabDef) rows() []string {
+	rows := strings.Split(strings.ReplaceAll(def.Map, "\r\n", "\n"), "\n")
+
+	if len(rows) > 0 && strings.TrimSpace(rows[0]) == "" {
+		rows = rows[1:]
+	}
+
+	if len(rows) > 0 && strings.TrimSpace(rows[len(rows)-1]) == "" {
+		rows = rows[:len(rows)-1]
+	}
+
+	return rows
+}
+
+// id returns the prefab's ID.
+func (def PrefabDef) id() string {
+	return def.ID
+}
+
+// validate checks the prefab's own fields. Characters are resolved against
+// the rest of the content later, by Content.Validate.
+func (def PrefabDef) validate() error {
+	if def.Name == "" {
+		return errors.New("name is required")
+	}
+
+	rows := def.rows()
+	if len(rows) == 0 {
+		return errors.New("map is required")
+	}
+
+	width := utf8.RuneCountInString(rows[0])
+	for y, row := range rows {
+		if got := utf8.RuneCountInString(row); got != width {
+			return fmt.Errorf("map row %d is %d wide, want %d", y+1, got, width)
+		}
+	}
+
+	for _, char := range slices.Sorted(maps.Keys(def.Legend)) {
+		entry := def.Legend[char]
+
+		if utf8.RuneCountInString(char) != 1 || char == " " {
+			return fmt.Errorf("legend key %q must be a single character other than a space", char)
+		}
+
+		placed := 0
+		for _, id := range []string{entry.Item, entry.Monster, entry.Spawn} {
+			if id != "" {
+				placed++
+			}
+		}
+
+		if placed > 1 {
+			return fmt.Errorf("legend %q may place only one of an item, monster, or spawn", char)
+		}
+
+		if placed == 0 && entry.Tile == "" {
+			return fmt.Errorf("legend %q places nothing", char)
+		}
+	}
+
+	return nil
+}
+
+// Prefab resolves the prefab defined as id. Returns an error naming the
+// problem if a character or ID is unknown or the prefab is not connected.
+func (content *Content) Prefab(id string) (Prefab, error) {
+	def, ok := content.Prefabs[id]
+	if !ok {
+		return Prefab{}, fmt.Errorf("%w: unknown prefab %q", ErrContentInvalid, id)
+	}
+
+	prefab, err := content.resolvePrefab(def)
+	if err != nil {
+		return Prefab{}, fmt.Errorf("%w: prefab %q: %w", ErrContentInvalid, id, err)
+	}
+
+	return prefab, nil
+}
+
+// resolvePrefab turns each character of def's map into what it places and
+// checks the result is connected.
+func (content *Content) resolvePrefab(def PrefabDef) (Prefab, error) {
+	rows := def.rows()
+	prefab := Prefab{ID: def.ID, Rotate: def.Rotate, Mirror: def.Mirror, cells: make([][]prefabCell, len(rows))}
+
+	for y, row := range rows {
+		for x, char := range []rune(row) {
+			cell, err := content.resolvePrefabCell(def, char)
+			if err != nil {
+				return Prefab{}, fmt.Errorf("map row %d column %d: %w", y+1, x+1, err)
+			}
+
+			prefab.cells[y] = append(prefab.cells[y], cell)
+		}
+	}
+
+	if err := prefab.checkConnected(); err != nil {
+		return Prefab{}, err
+	}
+
+	return prefab, nil
+}
+
+// resolvePrefabCell returns what char places: a space keeps the level's
+// tile, legend characters place what the legend says, and any other
+// character must be a tile glyph.
+func (content *Content) resolvePrefabCell(def PrefabDef, char rune) (prefabCell, error) {
+	if char == ' ' {
+		return prefabCell{keep: true}, nil
+	}
+
+	entry, ok := def.Legend[string(char)]
+	if !ok {
+		tile, ok := content.TileForGlyph(char)
+		if !ok {
+			return prefabCell{}, fmt.Errorf("%q is neither a tile glyph nor in the legend", char)
+		}
+
+		return prefabCell{tile: tile}, nil
+	}
+
+	cell := prefabCell{tile: content.Tile(TileFloor), item: entry.Item, monster: entry.Monster, spawn: entry.Spawn}
+
+	if entry.Tile != "" {
+		def, ok := content.Tiles[entry.Tile]
+		if !ok {
+			return prefabCell{}, fmt.Errorf("legend %q: unknown tile %q", char, entry.Tile)
+		}
+
+		cell.tile = def.Tile()
+	}
+
+	if entry.Item != "" {
+		if _, ok := content.Items[entry.Item]; !ok {
+			return prefabCell{}, fmt.Errorf("legend %q: unknown item %q", char, entry.Item)
+		}
+	}
+
+	if entry.Monster != "" {
+		if _, ok := content.Monsters[entry.Monster]; !ok {
+			return prefabCell{}, fmt.Errorf("legend %q: unknown monster %q", char, entry.Monster)
+		}
+	}
+
+	if entry.Spawn != "" && len(content.monstersTagged(entry.Spawn, math.MaxInt)) == 0 {
+		return prefabCell{}, fmt.Errorf("legend %q: no monster is tagged %q", char, entry.Spawn)
+	}
+
+	if (entry.Item != "" || entry.Monster != "" || entry.Spawn != "") && !cell.tile.Walkable {
+		return prefabCell{}, fmt.Errorf("legend %q places something on a solid tile", char)
+	}
+
+	return cell, nil
+}
+
+// checkConnected checks that every walkable cell can be reached from every
+// other, moving as the player does, and that at least one lies on the edge
+// of the prefab or beside a kept tile so the level can lead into it.
+func (prefab Prefab) checkConnected() error {
+	walkable := func(p Point) bool {
+		return p.Y >= 0 && p.Y < prefab.Height() && p.X >= 0 && p.X < prefab.Width() &&
+			!prefab.cells[p.Y][p.X].keep && prefab.cells[p.Y][p.X].tile.Walkable
+	}
+
+	var open []Point
+	entrance := false
+
+	for y, row := range prefab.cells {
+		for x := range row {
+			p := Point{X: x, Y: y}
+			if !walkable(p) {
+				continue
+			}
+
+			open = append(open, p)
+
+			for _, offset := range neighborOffsets {
+				q := Point{X: x + offset.X, Y: y + offset.Y}
+				if q.Y < 0 || q.Y >= prefab.Height() || q.X < 0 || q.X >= prefab.Width() || prefab.cells[q.Y][q.X].keep {
+					entrance = true
+				}
+			}
+		}
+	}
+
+	if len(open) == 0 {
+		return errors.New("map has no walkable tiles")
+	}
+
+	if !entrance {
+		return errors.New("map has no walkable tile on its edge to enter by")
+	}
+
+	reached := map[Point]bool{open[0]: true}
+	queue := []Point{open[0]}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, offset := range neighborOffsets {
+			next := Point{X: current.X + offset.X, Y: current.Y + offset.Y}
+			if reached[next] || !walkable(next) {
+				continue
+			}
+
+			reached[next] = true
+			queue = append(queue, next)
+		}
+	}
+
+	for _, p := range open {
+		if !reached[p] {
+			return fmt.Errorf("map row %d column %d cannot be reached from row %d column %d", p.Y+1, p.X+1, open[0].Y+1, open[0].X+1)
+		}
+	}
+
+	return nil
+}
+
+// Width returns the prefab's width in tiles.
+func (prefab Prefab) Width() int {
+	if len(prefab.cells) == 0 {
+		return 0
+	}
+
+	return len(prefab.cells[0])
+}
+
+// Height returns the prefab's height in tiles.
+func (prefab Prefab) Height() int {
+	return len(prefab.cells)
+}
+
+// Rotated returns the prefab turned a quarter clockwise.
+func (prefab Prefab) Rotated() Prefab {
+	rotated := prefab
+	rotated.cells = make([][]prefabCell, prefab.Width())
+
+	for x := range prefab.Width() {
+		rotated.cells[x] = make([]prefabCell, prefab.Height())
+
+		for y := range prefab.Height() {
+			rotated.cells[x][prefab.Height()-1-y] = prefab.cells[y][x]
+		}
+	}
+
+	return rotated
+}
+
+// Mirrored returns the prefab flipped left to right.
+func (prefab Prefab) Mirrored() Prefab {
+	mirrored := prefab
+	mirrored.cells = make([][]prefabCell, prefab.Height())
+
+	for y, row := range prefab.cells {
+		mirrored.cells[y] = slices.Clone(row)
+		slices.Reverse(mirrored.cells[y])
+	}
+
+	return mirrored
+}
+
+// Variants returns the prefab in every orientation it allows: as drawn,
+// turned if Rotate is set, and flipped if Mirror is set.
+func (prefab Prefab) Variants() []Prefab {
+	variants := []Prefab{prefab}
+
+	if prefab.Rotate {
+		for range 3 {
+			variants = append(variants, variants[len(variants)-1].Rotated())
+		}
+	}
+
+	if prefab.Mirror {
+		for _, variant := range slices.Clone(variants) {
+			variants = append(variants, variant.Mirrored())
+		}
+	}
+
+	return variants
+}
+
+// StampPrefab copies prefab into the level with its top-left corner at
+// (x, y), placing its tiles, items, and monsters. Cells outside the map are
+// skipped.
+func (game *Game) StampPrefab(prefab Prefab, x, y int) {
+	for dy, row := range prefab.cells {
+		for dx, cell := range row {
+			tx, ty := x+dx, y+dy
+			if cell.keep || !game.InBounds(tx, ty) {
+				continue
+			}
+
+			game.Tiles[ty][tx] = cell.tile
+
+			switch {
+			case cell.item != "":
+				game.SpawnEntity(game.Content.Items[cell.item].Entity(tx, ty))
+			case cell.monster != "":
+				game.SpawnEntity(game.Content.Monsters[cell.monster].Entity(tx, ty))
+			case cell.spawn != "":
+				if def, ok := game.randomMonster(cell.spawn); ok {
+					game.SpawnEntity(def.Entity(tx, ty))
+				}
+			}
+		}
+	}
+}
+
+// furnishRoom may stamp a prefab that fits inside the room at (x, y),
+// centered and in a random allowed orientation, leaving a ring of floor
+// around it so the prefab cannot seal off the room or the corridors that
+// reach it. Does nothing without prefabs or a random number generator.
+func (game *Game) furnishRoom(x, y, width, height int) {
+	if game.Content == nil || len(game.Content.Prefabs) == 0 || game.rng == nil {
+		return
+	}
+
+	if game.rng.Float64() >= prefabChance {
+		return
+	}
+
+	game.stampFittingPrefab(x+1, y+1, width-2, height-2, "")
+}
+
+// stampFittingPrefab stamps a random prefab tagged tag, or any prefab when
+// tag is empty, that fits inside the area at (x, y), centered and in a
+// random allowed orientation. Returns false if none fits.
+func (game *Game) stampFittingPrefab(x, y, width, height int, tag string) bool {
+	var fits []Prefab
+
+	for _, id := range slices.Sorted(maps.Keys(game.Content.Prefabs)) {
+		if tag != "" && !slices.Contains(game.Content.Prefabs[id].Tags, tag) {
+			continue
+		}
+
+		prefab, err := game.Content.Prefab(id)
+		if err != nil {
+			continue
+		}
+
+		for _, variant := range prefab.Variants() {
+			if variant.Width() <= width && variant.Height() <= height {
+				fits = append(fits, variant)
+			}
+		}
+	}
+
+	if len(fits) == 0 {
+		return false
+	}
+
+	prefab := fits[game.rng.IntN(len(fits))]
+	game.StampPrefab(prefab, x+(width-prefab.Width())/2, y+(height-prefab.Height())/2)
+
+	return true
+}
+
+// monstersTagged returns the IDs of monsters with tag that can appear at
+// depth, sorted.
+func (content *Content) monstersTagged(tag string, depth int) []string {
+	var ids []string
+
+	for _, id := range slices.Sorted(maps.Keys(content.Monsters)) {
+		def := content.Monsters[id]
+		if def.Depth <= depth && slices.Contains(def.Tags, tag) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// randomMonster picks a monster tagged tag that can appear on the current
+// level.
+func (game *Game) randomMonster(tag string) (MonsterDef, bool) {
+	ids := game.Content.monstersTagged(tag, game.Depth)
+	if len(ids) == 0 {
+		return MonsterDef{}, false
+	}
+
+	return game.Content.Monsters[ids[game.rng.IntN(len(ids))]], true
+}
+
+// randomItem picks an item tagged tag.
+func (game *Game) randomItem(tag string) (ItemDef, bool) {
+	var ids []string
+
+	for _, id := range slices.Sorted(maps.Keys(game.Content.Items)) {
+		if slices.Contains(game.Content.Items[id].Tags, tag) {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return ItemDef{}, false
+	}
+
+	return game.Content.Items[ids[game.rng.IntN(len(ids))]], true
+}
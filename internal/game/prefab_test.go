@@ -0,0 +1,298 @@
+package game
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// prefabContent returns content with a few items and monsters for prefabs
+// to place, plus prefabs.
+func prefabContent(t *testing.T, prefabs ...PrefabDef) *Content {
+	t.Helper()
+
+	content := NewContent()
+	content.Add(ContentFile{
+		Monsters: []MonsterDef{{ID: "ganger", Name: "ganger", Glyph: "g", Health: 10, Depth: 1, Tags: []string{"street"}}},
+		Items:    []ItemDef{{ID: "medkit", Name: "medkit", Glyph: "+"}},
+		Prefabs:  prefabs,
+	})
+
+	return content
+}
+
+// prefabString draws prefab with floors as ".", walls as "#", kept cells as
+// spaces, and anything placed as "*".
+func prefabString(prefab Prefab) string {
+	var builder strings.Builder
+
+	for _, row := range prefab.cells {
+		for _, cell := range row {
+			switch {
+			case cell.keep:
+				builder.WriteRune(' ')
+			case cell.item != "" || cell.monster != "" || cell.spawn != "":
+				builder.WriteRune('*')
+			default:
+				builder.WriteRune(cell.tile.Glyph)
+			}
+		}
+
+		builder.WriteRune('\n')
+	}
+
+	return builder.String()
+}
+
+func TestPrefabTransforms(t *testing.T) {
+	content := prefabContent(t, PrefabDef{
+		ID:     "nook",
+		Name:   "nook",
+		Map:    "\n###\n#+.\n",
+		Legend: map[string]PrefabLegend{"+": {Item: "medkit"}},
+		Rotate: true,
+		Mirror: true,
+	})
+
+	prefab, err := content.Prefab("nook")
+	if err != nil {
+		t.Fatalf("failed to resolve prefab: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		prefab Prefab
+		want   string
+	}{
+		{name: "as drawn", prefab: prefab, want: "###\n#*.\n"},
+		{name: "rotated", prefab: prefab.Rotated(), want: "##\n*#\n.#\n"},
+		{name: "mirrored", prefab: prefab.Mirrored(), want: "###\n.*#\n"},
+		{name: "rotated four times", prefab: prefab.Rotated().Rotated().Rotated().Rotated(), want: "###\n#*.\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := prefabString(tt.prefab); got != tt.want {
+				t.Errorf("want\n%s\ngot\n%s", tt.want, got)
+			}
+		})
+	}
+
+	if got := len(prefab.Variants()); got != 8 {
+		t.Errorf("want 8 variants, got %d", got)
+	}
+
+	prefab.Rotate, prefab.Mirror = false, false
+	if got := len(prefab.Variants()); got != 1 {
+		t.Errorf("want 1 variant when fixed, got %d", got)
+	}
+}
+
+func TestPrefabValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		def  PrefabDef
+		want string
+	}{
+		{
+			name: "ragged rows",
+			def:  PrefabDef{ID: "x", Name: "x", Map: "###\n#."},
+			want: "map row 2 is 2 wide, want 3",
+		},
+		{
+			name: "unknown character",
+			def:  PrefabDef{ID: "x", Name: "x", Map: "#.?"},
+			want: `row 1 column 3: '?' is neither a tile glyph nor in the legend`,
+		},
+		{
+			name: "unknown item",
+			def:  PrefabDef{ID: "x", Name: "x", Map: "#.$", Legend: map[string]PrefabLegend{"$": {Item: "credstick"}}},
+			want: `legend '$': unknown item "credstick"`,
+		},
+		{
+			name: "unknown spawn tag",
+			def:  PrefabDef{ID: "x", Name: "x", Map: "#.g", Legend: map[string]PrefabLegend{"g": {Spawn: "corporate"}}},
+			want: `no monster is tagged "corporate"`,
+		},
+		{
+			name: "item in a wall",
+			def:  PrefabDef{ID: "x", Name: "x", Map: "#.+", Legend: map[string]PrefabLegend{"+": {Item: "medkit", Tile: TileWall}}},
+			want: "places something on a solid tile",
+		},
+		{
+			name: "two things in one cell",
+			def:  PrefabDef{ID: "x", Name: "x", Map: "#.+", Legend: map[string]PrefabLegend{"+": {Item: "medkit", Monster: "ganger"}}},
+			want: "may place only one",
+		},
+		{
+			name: "disconnected",
+			def:  PrefabDef{ID: "x", Name: "x", Map: ".#.\n###"},
+			want: "row 1 column 3 cannot be reached from row 1 column 1",
+		},
+		{
+			name: "no entrance",
+			def:  PrefabDef{ID: "x", Name: "x", Map: "###\n#.#\n###"},
+			want: "no walkable tile on its edge",
+		},
+		{
+			name: "all walls",
+			def:  PrefabDef{ID: "x", Name: "x", Map: "##"},
+			want: "no walkable tiles",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Checked the way loading checks it: the file first, then the
+			// loaded content
+			err := ContentFile{Prefabs: []PrefabDef{tt.def}}.Validate()
+			if err == nil {
+				err = prefabContent(t, tt.def).Validate()
+			}
+
+			if !errors.Is(err, ErrContentInvalid) {
+				t.Fatalf("want %v, got %v", ErrContentInvalid, err)
+			}
+
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("want error containing %q, got %q", tt.want, err.Error())
+			}
+		})
+	}
+
+	t.Run("entrance beside a kept tile", func(t *testing.T) {
+		def := PrefabDef{ID: "x", Name: "x", Map: "#####\n#.. #\n#####"}
+		if err := prefabContent(t, def).Validate(); err != nil {
+			t.Errorf("want valid prefab, got %v", err)
+		}
+	})
+}
+
+func TestStampPrefab(t *testing.T) {
+	content := prefabContent(t, PrefabDef{
+		ID:     "den",
+		Name:   "den",
+		Map:    " ## \n#g+#\n#..#",
+		Legend: map[string]PrefabLegend{"g": {Spawn: "street"}, "+": {Item: "medkit"}},
+	})
+
+	game := NewGame()
+	game.SetContent(content)
+	game.Entities = nil
+
+	prefab, err := content.Prefab("den")
+	if err != nil {
+		t.Fatalf("failed to resolve prefab: %v", err)
+	}
+
+	game.StampPrefab(prefab, 11, 6)
+
+	if game.Tiles[6][11] != FloorTile || game.Tiles[6][12] != WallTile {
+		t.Errorf("want the kept tile left as floor and the wall placed, got %+v and %+v", game.Tiles[6][11], game.Tiles[6][12])
+	}
+
+	if entity, ok := game.EntityAt(12, 7); !ok || entity.ID != "ganger" {
+		t.Errorf("want a ganger at 12,7, got %+v", game.Entities)
+	}
+
+	if entity, ok := game.EntityAt(13, 7); !ok || entity.ID != "medkit" {
+		t.Errorf("want a medkit at 13,7, got %+v", game.Entities)
+	}
+}
+
+func TestFurnishRoomMargin(t *testing.T) {
+	content := prefabContent(t, PrefabDef{ID: "booth", Name: "booth", Map: "###\n#..\n###"})
+
+	tests := []struct {
+		name          string
+		size          int
+		wantFurnished bool
+	}{
+		{name: "room the prefab's size", size: 3},
+		{name: "room with space around the prefab", size: 5, wantFurnished: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := NewGame()
+			game.SetSeed(1)
+			game.SetContent(content)
+
+			furnished := false
+
+			for range 20 {
+				game.CreateRoom(10, 5, tt.size, tt.size)
+
+				for y := 5; y < 5+tt.size; y++ {
+					for x := 10; x < 10+tt.size; x++ {
+						edge := x == 10 || y == 5 || x == 9+tt.size || y == 4+tt.size
+						if !game.Tiles[y][x].Walkable {
+							furnished = true
+
+							if edge {
+								t.Fatalf("want floor around the prefab, got a wall at %d,%d", x, y)
+							}
+						}
+					}
+				}
+			}
+
+			if furnished != tt.wantFurnished {
+				t.Errorf("want furnished %t across 20 rooms, got %t", tt.wantFurnished, furnished)
+			}
+		})
+	}
+}
+
+func TestCreateRoomWithPrefabs(t *testing.T) {
+	content := prefabContent(t, PrefabDef{
+		ID:     "clinic",
+		Name:   "clinic",
+		Map:    "###\n#+.\n#..",
+		Legend: map[string]PrefabLegend{"+": {Item: "medkit"}},
+		Rotate: true,
+	})
+
+	level := func(seed int64) string {
+		game := NewGame()
+		game.SetSeed(seed)
+		game.SetContent(content)
+
+		var builder strings.Builder
+		for _, row := range game.Tiles {
+			for _, tile := range row {
+				builder.WriteRune(tile.Glyph)
+			}
+		}
+
+		for _, entity := range game.Entities {
+			builder.WriteRune(entity.Glyph)
+		}
+
+		return builder.String()
+	}
+
+	if first, second := level(3), level(3); first != second {
+		t.Error("want the same level for the same seed")
+	}
+
+	furnished := false
+
+	for seed := range int64(10) {
+		game := NewGame()
+		game.SetSeed(seed)
+		game.SetContent(content)
+
+		if len(game.Entities) > 0 {
+			furnished = true
+		}
+
+		if !game.Tiles[game.Player.Y][game.Player.X].Walkable {
+			t.Errorf("seed %d: want the player on a walkable tile", seed)
+		}
+	}
+
+	if !furnished {
+		t.Error("want some room furnished with the clinic across 10 seeds, got none")
+	}
+}
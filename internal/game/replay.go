@@ -0,0 +1,368 @@
+package game
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// Replay playback speeds in steps per second.
+const (
+	DefaultReplaySpeed = 10.0
+	minReplaySpeed     = 1.0
+	maxReplaySpeed     = 1000.0
+)
+
+// ReplayStepKind identifies the kind of input a replay step records.
+type ReplayStepKind string
+
+const (
+	// StepCommand is a command passed to HandleCommand.
+	StepCommand ReplayStepKind = "command"
+
+	// StepRune is a character passed to HandleRune.
+	StepRune ReplayStepKind = "rune"
+
+	// StepClick is a menu entry clicked through ClickEntry.
+	StepClick ReplayStepKind = "click"
+
+	// StepTravel is a travel route planned with TravelTo.
+	StepTravel ReplayStepKind = "travel"
+
+	// StepTravelStep is one step along a travel route taken with StepTravel.
+	StepTravelStep ReplayStepKind = "travel_step"
+
+	// StepExamine is a tile examined with Examine.
+	StepExamine ReplayStepKind = "examine"
+
+	// StepRebind is a key bound on the key binding screen with RebindSelected.
+	StepRebind ReplayStepKind = "rebind"
+
+	// StepCancelRebind is a rebind abandoned with CancelRebind.
+	StepCancelRebind ReplayStepKind = "cancel_rebind"
+)
+
+// ReplayStep is one input fed to the game. Only the fields used by its kind
+// are set.
+type ReplayStep struct {
+	Kind    ReplayStepKind `json:"kind"`              // Kind is the kind of input.
+	Command string         `json:"command,omitempty"` // Command is the command name for StepCommand.
+	Text    string         `json:"text,omitempty"`    // Text is the character for StepRune or the chord for StepRebind.
+	Index   int            `json:"index,omitempty"`   // Index is the entry clicked for StepClick.
+	X       int            `json:"x,omitempty"`       // X is the tile column for StepTravel and StepExamine.
+	Y       int            `json:"y,omitempty"`       // Y is the tile row for StepTravel and StepExamine.
+}
+
+// Replay is a recorded session: everything needed to play the same inputs
+// back on a fresh game and check it ends in the same state.
+type Replay struct {
+	Version   string          `json:"version"`             // Version is the version of the game that recorded the session.
+	Seed      int64           `json:"seed"`                // Seed is the seed the session started with.
+	Wizard    bool            `json:"wizard,omitempty"`    // Wizard is true if wizard mode was on.
+	Generator Generator       `json:"generator,omitempty"` // Generator is the level generator the session used.
+	Keymap    Keymap          `json:"keymap,omitempty"`    // Keymap is the key bindings when recording started, which decide the menu hotkeys typed.
+	History   int             `json:"history,omitempty"`   // History is the turn history snapshot interval, or 0 if it was off.
+	Save      json.RawMessage `json:"save,omitempty"`      // Save is the saved run that could be continued when recording started.
+	Steps     []ReplayStep    `json:"steps"`               // Steps are the inputs in the order they were fed to the game.
+	Hash      string          `json:"hash"`                // Hash is the StateHash when recording finished.
+}
+
+// StartRecording records every input from now on into Recording, along with
+// the seed, wizard mode, level generator, key bindings, and any save that
+// could be continued.
+func (game *Game) StartRecording(version string) error {
+	replay := &Replay{Version: version, Seed: game.Seed, Wizard: game.Wizard, Generator: game.Generator, Keymap: game.Keymap.Clone()}
+
+	if game.History != nil {
+		replay.History = game.History.Interval
+	}
+
+	if game.SavePath != "" {
+		data, err := os.ReadFile(game.SavePath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+
+		replay.Save = data
+	}
+
+	game.Recording = replay
+
+	return nil
+}
+
+// FinishRecording stores the final state hash in Recording and writes it to
+// path.
+func (game *Game) FinishRecording(path string) error {
+	if game.Recording == nil {
+		return nil
+	}
+
+	hash, err := game.StateHash()
+	if err != nil {
+		return err
+	}
+
+	game.Recording.Hash = hash
+
+	return game.Recording.SaveFile(path)
+}
+
+// record appends step to Recording when a session is being recorded.
+func (game *Game) record(step ReplayStep) {
+	if game.Recording != nil {
+		game.Recording.Steps = append(game.Recording.Steps, step)
+	}
+}
+
+// StateHash returns a SHA-256 hash of the run in progress as serialized by
+// MarshalState. The message log is left out, since messages can name files
+// that differ between machines.
+func (game *Game) StateHash() (string, error) {
+	data, err := game.MarshalState()
+	if err != nil {
+		return "", err
+	}
+
+	var save saveData
+	if err := json.Unmarshal(data, &save); err != nil {
+		return "", err
+	}
+
+	save.Messages = nil
+
+	data, err = json.Marshal(save)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// LoadReplay reads a replay from the JSON file at path.
+func LoadReplay(path string) (Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Replay{}, err
+	}
+
+	var replay Replay
+	if err := json.Unmarshal(data, &replay); err != nil {
+		return Replay{}, fmt.Errorf("%w: %s: %v", ErrReplayInvalid, path, err)
+	}
+
+	return replay, nil
+}
+
+// SaveFile writes the replay to path as JSON.
+func (replay Replay) SaveFile(path string) error {
+	data, err := json.Marshal(replay)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// NewReplayGame creates a game set up the way replay was recorded: the same
+// seed, wizard mode, level generator, key bindings, and turn history,
+// continuing the same save. It has no file paths, so playing it back never
+// touches the player's saves or scores. Call SetContent before playing it to
+// build its level.
+func NewReplayGame(replay Replay) *Game {
+	game := NewGame()
+	game.SetSeed(replay.Seed)
+	game.Wizard = replay.Wizard
+	game.Generator = replay.Generator
+	game.savedRun = replay.Save
+
+	// Replays recorded before key bindings were kept use the defaults
+	if replay.Keymap != nil {
+		game.Keymap = replay.Keymap.Clone()
+	}
+
+	if replay.History > 0 {
+		game.EnableHistory(replay.History)
+	}
+
+	return game
+}
+
+// ApplyStep feeds a recorded input to the game. Returns true if the game
+// asked to exit.
+func (game *Game) ApplyStep(step ReplayStep) (bool, error) {
+	switch step.Kind {
+	case StepCommand:
+		command, err := ParseCommand(step.Command)
+		if err != nil {
+			return false, fmt.Errorf("%w: %v", ErrReplayInvalid, err)
+		}
+
+		return game.HandleCommand(command), nil
+	case StepRune:
+		text := []rune(step.Text)
+		if len(text) != 1 {
+			return false, fmt.Errorf("%w: rune step %q is not one character", ErrReplayInvalid, step.Text)
+		}
+
+		return game.HandleRune(text[0]), nil
+	case StepClick:
+		return game.ClickEntry(step.Index), nil
+	case StepTravel:
+		game.TravelTo(step.X, step.Y)
+	case StepTravelStep:
+		game.StepTravel()
+	case StepExamine:
+		game.Examine(step.X, step.Y)
+	case StepRebind:
+		_ = game.RebindSelected(step.Text)
+	case StepCancelRebind:
+		game.CancelRebind()
+	default:
+		return false, fmt.Errorf("%w: unknown step kind %q", ErrReplayInvalid, step.Kind)
+	}
+
+	return false, nil
+}
+
+// VerifyReplay plays replay back on a fresh game using content without
+// drawing it and checks that it ends in the recorded state. A nil content
+// plays it on the built-in tiles.
+func VerifyReplay(replay Replay, content *Content) error {
+	game := NewReplayGame(replay)
+	if content == nil {
+		content = game.Content
+	}
+
+	game.SetContent(content)
+
+	for i, step := range replay.Steps {
+		exit, err := game.ApplyStep(step)
+		if err != nil {
+			return fmt.Errorf("step %d: %w", i, err)
+		}
+
+		if exit {
+			break
+		}
+	}
+
+	hash, err := game.StateHash()
+	if err != nil {
+		return err
+	}
+
+	if hash != replay.Hash {
+		return fmt.Errorf("%w: got %s, want %s", ErrReplayMismatch, hash, replay.Hash)
+	}
+
+	return nil
+}
+
+// ReplayPlayer plays a replay back step by step at an adjustable speed.
+type ReplayPlayer struct {
+	Game    *Game         // Game is the game the replay is played on.
+	Replay  Replay        // Replay is the recording being played.
+	Next    int           // Next is the index of the next step to apply.
+	Speed   float64       // Speed is the playback speed in steps per second.
+	Paused  bool          // Paused stops playback until resumed or stepped.
+	Err     error         // Err is set if a step could not be applied, which ends playback.
+	exited  bool          // exited is set once the game asks to exit.
+	elapsed time.Duration // elapsed is the playback time not yet spent on steps.
+}
+
+// NewReplayPlayer prepares replay for playback on a new game at speed steps
+// per second.
+func NewReplayPlayer(replay Replay, speed float64) *ReplayPlayer {
+	return &ReplayPlayer{
+		Game:   NewReplayGame(replay),
+		Replay: replay,
+		Speed:  min(max(speed, minReplaySpeed), maxReplaySpeed),
+	}
+}
+
+// Finished returns true once every step has been applied, the game asked to
+// exit, or a step failed.
+func (player *ReplayPlayer) Finished() bool {
+	return player.exited || player.Err != nil || player.Next >= len(player.Replay.Steps)
+}
+
+// Step applies the next step.
+func (player *ReplayPlayer) Step() {
+	if player.Finished() {
+		return
+	}
+
+	exit, err := player.Game.ApplyStep(player.Replay.Steps[player.Next])
+	player.Next++
+
+	if err != nil {
+		player.Err = fmt.Errorf("step %d: %w", player.Next-1, err)
+	}
+
+	player.exited = exit
+}
+
+// Update plays the steps due after elapsed more time has passed.
+func (player *ReplayPlayer) Update(elapsed time.Duration) {
+	if player.Paused || player.Finished() {
+		return
+	}
+
+	interval := time.Duration(float64(time.Second) / player.Speed)
+	player.elapsed += elapsed
+
+	for player.elapsed >= interval && !player.Finished() {
+		player.elapsed -= interval
+		player.Step()
+	}
+}
+
+// HandleCommand pauses, steps, or changes the speed of playback. Returns
+// true if the player asked to quit.
+func (player *ReplayPlayer) HandleCommand(command Command) bool {
+	switch command {
+	case CommandReplayPause:
+		player.Paused = !player.Paused
+	case CommandReplayStep:
+		player.Paused = true
+		player.Step()
+	case CommandReplayFaster:
+		player.Speed = min(player.Speed*2, maxReplaySpeed)
+	case CommandReplaySlower:
+		player.Speed = max(player.Speed/2, minReplaySpeed)
+	case CommandQuit:
+		return true
+	}
+
+	return false
+}
+
+// Status describes the playback position, speed, and, once finished,
+// whether the game ended in the recorded state.
+func (player *ReplayPlayer) Status() string {
+	status := fmt.Sprintf("Replay step %d/%d at %g steps/s", player.Next, len(player.Replay.Steps), player.Speed)
+
+	switch {
+	case player.Err != nil:
+		return status + ", failed: " + player.Err.Error()
+	case player.Finished():
+		hash, err := player.Game.StateHash()
+		if err == nil && hash == player.Replay.Hash {
+			return status + ", finished: state matches"
+		}
+
+		return status + ", finished: state differs"
+	case player.Paused:
+		return status + ", paused"
+	}
+
+	return status
+}
@@ -0,0 +1,262 @@
+package game
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// recordSession plays a short session on a recorded game and returns the
+// replay with its final hash.
+func recordSession(t *testing.T, game *Game) Replay {
+	t.Helper()
+
+	if err := game.StartRecording("test"); err != nil {
+		t.Fatalf("failed to start recording: %v", err)
+	}
+
+	game.HandleCommand(CommandStartGame)
+	game.HandleCommand(CommandMoveRight)
+	game.TravelTo(20, 9)
+	for game.StepTravel() {
+	}
+	game.Examine(20, 9)
+	game.HandleCommand(CommandCommandMenu)
+	game.ClickEntry(0)
+	game.HandleCommand(CommandCancel)
+
+	hash, err := game.StateHash()
+	if err != nil {
+		t.Fatalf("failed to hash state: %v", err)
+	}
+
+	game.Recording.Hash = hash
+
+	return *game.Recording
+}
+
+func TestRecording(t *testing.T) {
+	game := NewGame()
+	game.SetSeed(7)
+
+	replay := recordSession(t, game)
+
+	if replay.Seed != 7 || replay.Version != "test" {
+		t.Errorf("want seed and version recorded, got %d and %q", replay.Seed, replay.Version)
+	}
+
+	commands := 0
+	for _, step := range replay.Steps {
+		if step.Kind == StepCommand && step.Command == CommandConfirm.String() {
+			commands++
+		}
+	}
+
+	if commands != 0 {
+		t.Errorf("want commands run by a click not recorded, got %d confirm steps", commands)
+	}
+
+	if err := VerifyReplay(replay, nil); err != nil {
+		t.Errorf("want replay verified, got %v", err)
+	}
+
+	replay.Steps = replay.Steps[:2]
+
+	if err := VerifyReplay(replay, nil); !errors.Is(err, ErrReplayMismatch) {
+		t.Errorf("want %v for a shortened replay, got %v", ErrReplayMismatch, err)
+	}
+}
+
+func TestRecordingContinuesSave(t *testing.T) {
+	saved := NewGame()
+	saved.SavePath = filepath.Join(t.TempDir(), "save.json")
+	saved.StartGame()
+	saved.MovePlayer(1, 0)
+	saved.MovePlayer(1, 0)
+
+	if err := saved.SaveGame(); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	game := NewGame()
+	game.SavePath = saved.SavePath
+
+	replay := recordSession(t, game)
+
+	if replay.Save == nil {
+		t.Fatal("want the save stored in the replay, got none")
+	}
+
+	if err := VerifyReplay(replay, nil); err != nil {
+		t.Errorf("want replay of a continued run verified, got %v", err)
+	}
+}
+
+func TestReplayInvalidSave(t *testing.T) {
+	game := NewReplayGame(Replay{Save: []byte("not a save")})
+
+	restored, err := game.LoadSavedGame()
+	if restored {
+		t.Error("want restored false for an invalid save, got true")
+	}
+
+	if !errors.Is(err, ErrSaveInvalid) {
+		t.Errorf("want %v, got %v", ErrSaveInvalid, err)
+	}
+}
+
+func TestSaveAndReplayFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "replay.json")
+
+	game := NewGame()
+	if err := game.StartRecording("test"); err != nil {
+		t.Fatalf("failed to start recording: %v", err)
+	}
+
+	game.HandleCommand(CommandStartGame)
+	game.HandleRune('x')
+
+	if err := game.FinishRecording(path); err != nil {
+		t.Fatalf("failed to write replay: %v", err)
+	}
+
+	replay, err := LoadReplay(path)
+	if err != nil {
+		t.Fatalf("failed to load replay: %v", err)
+	}
+
+	if len(replay.Steps) != 2 || replay.Steps[1] != (ReplayStep{Kind: StepRune, Text: "x"}) {
+		t.Errorf("want two steps ending with a rune, got %+v", replay.Steps)
+	}
+
+	if err := VerifyReplay(replay, nil); err != nil {
+		t.Errorf("want replay verified, got %v", err)
+	}
+}
+
+func TestApplyStepInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		step ReplayStep
+	}{
+		{name: "unknown kind", step: ReplayStep{Kind: "teleport"}},
+		{name: "unknown command", step: ReplayStep{Kind: StepCommand, Command: "fly"}},
+		{name: "empty rune", step: ReplayStep{Kind: StepRune}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewGame().ApplyStep(tt.step); !errors.Is(err, ErrReplayInvalid) {
+				t.Errorf("want %v, got %v", ErrReplayInvalid, err)
+			}
+		})
+	}
+}
+
+func TestNewRunIsDeterministic(t *testing.T) {
+	first := NewGame()
+	second := NewGame()
+	first.SetSeed(3)
+	second.SetSeed(3)
+
+	first.NewRun()
+	second.NewRun()
+
+	if first.Seed != second.Seed {
+		t.Errorf("want new runs from the same seed to match, got %d and %d", first.Seed, second.Seed)
+	}
+}
+
+func TestReplayPlayer(t *testing.T) {
+	game := NewGame()
+	replay := recordSession(t, game)
+
+	t.Run("plays steps at its speed", func(t *testing.T) {
+		player := NewReplayPlayer(replay, 10)
+
+		player.Update(250 * time.Millisecond)
+
+		if player.Next != 2 {
+			t.Errorf("want 2 steps after a quarter second, got %d", player.Next)
+		}
+
+		player.Update(time.Hour)
+
+		if !player.Finished() {
+			t.Fatalf("want replay finished, got step %d of %d", player.Next, len(replay.Steps))
+		}
+
+		if got := player.Status(); got != "Replay step 9/9 at 10 steps/s, finished: state matches" {
+			t.Errorf("want finished status, got %q", got)
+		}
+	})
+
+	t.Run("pause, step, and speed", func(t *testing.T) {
+		player := NewReplayPlayer(replay, 10)
+
+		player.HandleCommand(CommandReplayPause)
+		player.Update(time.Second)
+
+		if player.Next != 0 {
+			t.Errorf("want no steps while paused, got %d", player.Next)
+		}
+
+		player.HandleCommand(CommandReplayStep)
+
+		if player.Next != 1 || !player.Paused {
+			t.Errorf("want one step and still paused, got %d and %v", player.Next, player.Paused)
+		}
+
+		player.HandleCommand(CommandReplayFaster)
+		player.HandleCommand(CommandReplayFaster)
+		player.HandleCommand(CommandReplaySlower)
+
+		if player.Speed != 20 {
+			t.Errorf("want speed 20, got %g", player.Speed)
+		}
+
+		if !player.HandleCommand(CommandQuit) {
+			t.Error("want quit to exit, got false")
+		}
+	})
+}
+
+func TestReplayKeymap(t *testing.T) {
+	// Binding A and B in menus moves the pause menu hotkeys along, so c
+	// resumes play instead of opening the options
+	game := NewGame()
+	game.Keymap[CommandMenuUp] = append(game.Keymap[CommandMenuUp], "A", "B")
+
+	if err := game.StartRecording("test"); err != nil {
+		t.Fatalf("failed to start recording: %v", err)
+	}
+
+	game.HandleCommand(CommandStartGame)
+	game.HandleCommand(CommandPause)
+	game.HandleRune('c')
+	game.HandleCommand(CommandMoveRight)
+
+	if game.State != StatePlaying {
+		t.Fatalf("want c to resume under the custom keymap, got state %v", game.State)
+	}
+
+	path := filepath.Join(t.TempDir(), "replay.json")
+	if err := game.FinishRecording(path); err != nil {
+		t.Fatalf("failed to finish recording: %v", err)
+	}
+
+	replay, err := LoadReplay(path)
+	if err != nil {
+		t.Fatalf("failed to load replay: %v", err)
+	}
+
+	if err := VerifyReplay(replay, nil); err != nil {
+		t.Errorf("want replay under a custom keymap verified, got %v", err)
+	}
+
+	replay.Keymap = nil
+	if err := VerifyReplay(replay, nil); !errors.Is(err, ErrReplayMismatch) {
+		t.Errorf("want %v under the default keymap, got %v", ErrReplayMismatch, err)
+	}
+}
@@ -0,0 +1,186 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/rand/v2"
+	"os"
+)
+
+// saveVersion is incremented whenever the save format changes incompatibly.
+const saveVersion = 1
+
+// saveData is the serialized form of a run, used for save files and debug
+// snapshots. The map is stored as rows of tile glyphs.
+type saveData struct {
+	Version   int               `json:"version"`            // Version is the save format version.
+	Seed      int64             `json:"seed"`               // Seed is the run's seed.
+	RNG       []byte            `json:"rng"`                // RNG is the random number generator state.
+	Map       []string          `json:"map"`                // Map holds one string of tile glyphs per row.
+	Player    Player            `json:"player"`             // Player is the player character.
+	TurnCount int               `json:"turn_count"`         // TurnCount is the number of turns taken.
+	Depth     int               `json:"depth"`              // Depth is the current level.
+	Messages  []Message         `json:"messages"`           // Messages is the message log.
+	Entities  []Entity          `json:"entities,omitempty"` // Entities are the monsters and items on the level.
+	Wizard    bool              `json:"wizard,omitempty"`   // Wizard is true if wizard mode was used in the run.
+	Flags     map[string]string `json:"flags,omitempty"`    // Flags are the values scripts stored.
+}
+
+// MarshalState serializes the run in progress: the map, player, entities,
+// turn, random number generator, message log, script flags, and whether
+// wizard mode was used. Preferences and screens are not included.
+func (game *Game) MarshalState() ([]byte, error) {
+	rng, err := game.rngSource.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]string, len(game.Tiles))
+	for y, row := range game.Tiles {
+		glyphs := make([]rune, len(row))
+		for x, tile := range row {
+			glyphs[x] = tile.Glyph
+		}
+
+		rows[y] = string(glyphs)
+	}
+
+	return json.Marshal(saveData{
+		Version:   saveVersion,
+		Seed:      game.Seed,
+		RNG:       rng,
+		Map:       rows,
+		Player:    game.Player,
+		TurnCount: game.TurnCount,
+		Depth:     game.Depth,
+		Messages:  game.Messages,
+		Entities:  game.Entities,
+		Wizard:    game.Wizard,
+		Flags:     game.Flags,
+	})
+}
+
+// RestoreState replaces the run in progress with one serialized by
+// MarshalState. The game is left unchanged if data is invalid.
+func (game *Game) RestoreState(data []byte) error {
+	var save saveData
+	if err := json.Unmarshal(data, &save); err != nil {
+		return fmt.Errorf("%w: %v", ErrSaveInvalid, err)
+	}
+
+	if save.Version != saveVersion {
+		return fmt.Errorf("%w: version %d, want %d", ErrSaveInvalid, save.Version, saveVersion)
+	}
+
+	if len(save.Map) == 0 {
+		return fmt.Errorf("%w: empty map", ErrSaveInvalid)
+	}
+
+	width := len([]rune(save.Map[0]))
+	tiles := make([][]Tile, len(save.Map))
+
+	for y, row := range save.Map {
+		glyphs := []rune(row)
+		if len(glyphs) != width {
+			return fmt.Errorf("%w: map row %d is %d tiles wide, want %d", ErrSaveInvalid, y, len(glyphs), width)
+		}
+
+		tiles[y] = make([]Tile, width)
+		for x, glyph := range glyphs {
+			tile, ok := game.Content.TileForGlyph(glyph)
+			if !ok {
+				return fmt.Errorf("%w: unknown tile %q at %d,%d", ErrSaveInvalid, glyph, x, y)
+			}
+
+			tiles[y][x] = tile
+		}
+	}
+
+	source := &rand.PCG{}
+	if err := source.UnmarshalBinary(save.RNG); err != nil {
+		return fmt.Errorf("%w: %v", ErrSaveInvalid, err)
+	}
+
+	game.Width = width
+	game.Height = len(tiles)
+	game.Tiles = tiles
+	game.Player = save.Player
+	game.TurnCount = save.TurnCount
+	game.Depth = save.Depth
+	game.Messages = save.Messages
+	game.Entities = save.Entities
+	game.Flags = save.Flags
+	game.Wizard = game.Wizard || save.Wizard
+	game.Seed = save.Seed
+	game.rngSource = source
+	game.rng = rand.New(source)
+	game.CameraX = game.Player.X
+	game.CameraY = game.Player.Y
+	game.CancelTravel()
+
+	return nil
+}
+
+// SaveGame writes the run in progress to SavePath.
+func (game *Game) SaveGame() error {
+	if game.SavePath == "" {
+		return ErrSaveDisabled
+	}
+
+	data, err := game.MarshalState()
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(game.SavePath, data)
+}
+
+// LoadSavedGame restores the run saved at SavePath and deletes the save, so
+// each run can only be continued once. A replay's save is continued instead
+// of SavePath when there is one. restored is false if there is no save.
+func (game *Game) LoadSavedGame() (restored bool, err error) {
+	if game.savedRun != nil {
+		data := game.savedRun
+		game.savedRun = nil
+
+		if err := game.RestoreState(data); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	if game.SavePath == "" {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(game.SavePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if err := game.RestoreState(data); err != nil {
+		return false, fmt.Errorf("%s: %w", game.SavePath, err)
+	}
+
+	return true, game.DeleteSave()
+}
+
+// DeleteSave removes the save file, if there is one.
+func (game *Game) DeleteSave() error {
+	if game.SavePath == "" {
+		return nil
+	}
+
+	if err := os.Remove(game.SavePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
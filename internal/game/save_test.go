@@ -0,0 +1,117 @@
+package game
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMarshalState(t *testing.T) {
+	game := NewGame()
+	game.SetSeed(99)
+	game.MovePlayer(1, 0)
+	game.AddMessage("Saved here.")
+	game.Player.Nuyen = 250
+	game.SpawnEntity(Entity{Kind: EntityItem, Name: "credstick", Glyph: '*', X: 12, Y: 9})
+	game.Wizard = true
+
+	data, err := game.MarshalState()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	want := game.rng.IntN(1000)
+
+	restored := NewGame()
+	if err := restored.RestoreState(data); err != nil {
+		t.Fatalf("failed to restore: %v", err)
+	}
+
+	if restored.Player != game.Player || restored.TurnCount != 1 || restored.Seed != 99 {
+		t.Errorf("want player and turn restored, got %+v on turn %d", restored.Player, restored.TurnCount)
+	}
+
+	if len(restored.Messages) != 1 || restored.Messages[0].Text != "Saved here." {
+		t.Errorf("want messages restored, got %+v", restored.Messages)
+	}
+
+	if len(restored.Entities) != 1 || restored.Entities[0] != game.Entities[0] {
+		t.Errorf("want entities restored, got %+v", restored.Entities)
+	}
+
+	if !restored.Wizard {
+		t.Error("want wizard flag restored, got false")
+	}
+
+	for y := range game.Tiles {
+		for x := range game.Tiles[y] {
+			if restored.Tiles[y][x] != game.Tiles[y][x] {
+				t.Fatalf("want tile %v at %d,%d, got %v", game.Tiles[y][x], x, y, restored.Tiles[y][x])
+			}
+		}
+	}
+
+	if got := restored.rng.IntN(1000); got != want {
+		t.Errorf("want random sequence restored, got %d instead of %d", got, want)
+	}
+}
+
+func TestRestoreStateInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "malformed", data: `{`},
+		{name: "old version", data: `{"version": 0}`},
+		{name: "empty map", data: `{"version": 1, "map": []}`},
+		{name: "ragged map", data: `{"version": 1, "map": ["###", "#"]}`},
+		{name: "unknown tile", data: `{"version": 1, "map": ["#?#"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := NewGame()
+
+			err := game.RestoreState([]byte(tt.data))
+			if !errors.Is(err, ErrSaveInvalid) {
+				t.Errorf("want %v, got %v", ErrSaveInvalid, err)
+			}
+
+			if game.Width != mapWidth {
+				t.Errorf("want game unchanged, got width %d", game.Width)
+			}
+		})
+	}
+}
+
+func TestSaveAndContinue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+
+	game := NewGame()
+	game.SavePath = path
+	game.StartGame()
+	game.MovePlayer(1, 0)
+
+	if err := game.SaveGame(); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	continued := NewGame()
+	continued.SavePath = path
+	continued.StartGame()
+
+	if continued.Player.X != 18 || continued.TurnCount != 1 {
+		t.Errorf("want saved run continued, got player at %d on turn %d", continued.Player.X, continued.TurnCount)
+	}
+
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("want save deleted after loading, got %v", err)
+	}
+}
+
+func TestSaveGameDisabled(t *testing.T) {
+	if err := NewGame().SaveGame(); !errors.Is(err, ErrSaveDisabled) {
+		t.Errorf("want %v, got %v", ErrSaveDisabled, err)
+	}
+}
@@ -0,0 +1,161 @@
+package game
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"time"
+)
+
+// scoresShown is the number of runs listed on the scores screen.
+const scoresShown = 15
+
+// RunRecord describes one finished run in the score table.
+type RunRecord struct {
+	Name      string    `json:"name"`             // Name is the runner's name.
+	Archetype string    `json:"archetype"`        // Archetype is the runner's character class.
+	Depth     int       `json:"depth"`            // Depth is the deepest level reached.
+	Karma     int       `json:"karma"`            // Karma is the experience earned.
+	Nuyen     int       `json:"nuyen"`            // Nuyen is the money carried at the end.
+	Turns     int       `json:"turns"`            // Turns is how many turns the run lasted.
+	Score     int       `json:"score"`            // Score is computed from the fields above.
+	Ending    string    `json:"ending"`           // Ending describes how the run ended.
+	Seed      int64     `json:"seed"`             // Seed is the seed the run was played with.
+	Date      time.Time `json:"date"`             // Date is when the run ended.
+	Wizard    bool      `json:"wizard,omitempty"` // Wizard is true if the run used wizard mode.
+}
+
+// ScoreTable is the local history of finished runs.
+type ScoreTable struct {
+	Runs []RunRecord `json:"runs"` // Runs holds every recorded run, oldest first.
+}
+
+// ScoresScreen holds the state of the scores screen.
+type ScoresScreen struct {
+	Filter int // Filter indexes ScoreFilters; 0 shows every archetype.
+}
+
+// Score computes a run's score. Depth counts most, then karma and nuyen, with
+// a small bonus for surviving longer.
+func Score(depth, karma, nuyen, turns int) int {
+	return depth*1000 + karma*50 + nuyen/100 + turns/10
+}
+
+// LoadScoreTable reads the score table from the JSON file at path. A missing
+// file is not an error; an empty table is returned instead.
+func LoadScoreTable(path string) (ScoreTable, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ScoreTable{}, nil
+	}
+
+	if err != nil {
+		return ScoreTable{}, err
+	}
+
+	var table ScoreTable
+	if err := json.Unmarshal(data, &table); err != nil {
+		return ScoreTable{}, fmt.Errorf("%w: %s: %v", ErrScoresParseFailed, path, err)
+	}
+
+	return table, nil
+}
+
+// SaveFile writes the score table to path as JSON. The write is atomic so a
+// crash never leaves a corrupt table behind.
+func (table ScoreTable) SaveFile(path string) error {
+	data, err := json.MarshalIndent(table, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// Top returns up to count runs with the highest scores, best first, limited
+// to archetype unless it is empty.
+func (table ScoreTable) Top(archetype string, count int) []RunRecord {
+	var runs []RunRecord
+
+	for _, run := range table.Runs {
+		if archetype == "" || run.Archetype == archetype {
+			runs = append(runs, run)
+		}
+	}
+
+	slices.SortStableFunc(runs, func(a, b RunRecord) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+
+	return runs[:min(count, len(runs))]
+}
+
+// Archetypes returns the archetypes that appear in the table in sorted order.
+func (table ScoreTable) Archetypes() []string {
+	var archetypes []string
+
+	for _, run := range table.Runs {
+		if !slices.Contains(archetypes, run.Archetype) {
+			archetypes = append(archetypes, run.Archetype)
+		}
+	}
+
+	slices.Sort(archetypes)
+
+	return archetypes
+}
+
+// ScoreFilters returns the archetype filters offered on the scores screen.
+// The first, empty filter shows every archetype.
+func (game *Game) ScoreFilters() []string {
+	return append([]string{""}, game.Scores.Archetypes()...)
+}
+
+// RecordRun adds the run in progress to the score table and saves the table
+// to ScoresPath when one is set.
+func (game *Game) RecordRun(ending string) error {
+	game.Scores.Runs = append(game.Scores.Runs, RunRecord{
+		Name:      game.Player.Name,
+		Archetype: game.Player.Archetype,
+		Depth:     game.Depth,
+		Karma:     game.Player.Karma,
+		Nuyen:     game.Player.Nuyen,
+		Turns:     game.TurnCount,
+		Score:     Score(game.Depth, game.Player.Karma, game.Player.Nuyen, game.TurnCount),
+		Ending:    ending,
+		Seed:      game.Seed,
+		Date:      time.Now(),
+		Wizard:    game.Wizard,
+	})
+
+	if game.ScoresPath == "" {
+		return nil
+	}
+
+	return game.Scores.SaveFile(game.ScoresPath)
+}
+
+// OpenScores shows the scores screen with every archetype listed.
+func (game *Game) OpenScores() {
+	game.ScoresScreen = ScoresScreen{}
+	game.State = StateScores
+}
+
+// handleScoresCommand changes the archetype filter or closes the scores
+// screen.
+func (game *Game) handleScoresCommand(command Command) {
+	filters := game.ScoreFilters()
+
+	switch command {
+	case CommandMenuUp:
+		game.ScoresScreen.Filter = wrapCursor(game.ScoresScreen.Filter, -1, len(filters))
+	case CommandMenuDown:
+		game.ScoresScreen.Filter = wrapCursor(game.ScoresScreen.Filter, 1, len(filters))
+	case CommandConfirm, CommandCancel:
+		game.State = StateTitleScreen
+	}
+}
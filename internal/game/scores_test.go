@@ -0,0 +1,162 @@
+package game
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name                       string
+		depth, karma, nuyen, turns int
+		want                       int
+	}{
+		{name: "depth only", depth: 1, want: 1000},
+		{name: "everything", depth: 3, karma: 10, nuyen: 5000, turns: 250, want: 3000 + 500 + 50 + 25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Score(tt.depth, tt.karma, tt.nuyen, tt.turns); got != tt.want {
+				t.Errorf("want %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestScoreTableTop(t *testing.T) {
+	table := ScoreTable{Runs: []RunRecord{
+		{Name: "a", Archetype: "Decker", Score: 100},
+		{Name: "b", Archetype: "Mage", Score: 300},
+		{Name: "c", Archetype: "Decker", Score: 200},
+	}}
+
+	tests := []struct {
+		name      string
+		archetype string
+		count     int
+		want      []string
+	}{
+		{name: "all archetypes", count: 10, want: []string{"b", "c", "a"}},
+		{name: "one archetype", archetype: "Decker", count: 10, want: []string{"c", "a"}},
+		{name: "limited", count: 1, want: []string{"b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs := table.Top(tt.archetype, tt.count)
+
+			if len(runs) != len(tt.want) {
+				t.Fatalf("want %d runs, got %d", len(tt.want), len(runs))
+			}
+
+			for i, run := range runs {
+				if run.Name != tt.want[i] {
+					t.Errorf("want run %d to be %s, got %s", i, tt.want[i], run.Name)
+				}
+			}
+		})
+	}
+
+	if got := table.Archetypes(); len(got) != 2 || got[0] != "Decker" || got[1] != "Mage" {
+		t.Errorf("want [Decker Mage], got %v", got)
+	}
+}
+
+func TestScoreTableFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scores", "scores.json")
+
+	table, err := LoadScoreTable(path)
+	if err != nil || len(table.Runs) != 0 {
+		t.Fatalf("want empty table for missing file, got %v, %v", table, err)
+	}
+
+	table.Runs = append(table.Runs, RunRecord{Name: "Decker", Score: 1000})
+	if err := table.SaveFile(path); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	loaded, err := LoadScoreTable(path)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+
+	if len(loaded.Runs) != 1 || loaded.Runs[0].Score != 1000 {
+		t.Errorf("want saved run, got %+v", loaded.Runs)
+	}
+
+	// Only the table remains; the temporary file was renamed away
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("failed to read directory: %v", err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("want only the score table, got %d files", len(entries))
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatalf("failed to corrupt table: %v", err)
+	}
+
+	if _, err := LoadScoreTable(path); !errors.Is(err, ErrScoresParseFailed) {
+		t.Errorf("want %v, got %v", ErrScoresParseFailed, err)
+	}
+}
+
+func TestAbandonRecordsRun(t *testing.T) {
+	game := NewGame()
+	game.ScoresPath = filepath.Join(t.TempDir(), "scores.json")
+	game.StartGame()
+	game.MovePlayer(1, 0)
+
+	game.OpenPauseMenu(pauseAbandon)
+	game.HandleCommand(CommandConfirm)
+	game.HandleCommand(CommandConfirm)
+
+	table, err := LoadScoreTable(game.ScoresPath)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+
+	if len(table.Runs) != 1 {
+		t.Fatalf("want 1 run, got %d", len(table.Runs))
+	}
+
+	run := table.Runs[0]
+	if run.Turns != 1 || run.Depth != 1 || run.Archetype != "Decker" || run.Score != Score(1, 0, 0, 1) {
+		t.Errorf("want recorded run, got %+v", run)
+	}
+}
+
+func TestScoresScreen(t *testing.T) {
+	game := NewGame()
+	game.Scores.Runs = []RunRecord{{Archetype: "Decker"}, {Archetype: "Mage"}}
+
+	game.HandleCommand(CommandScores)
+
+	if game.State != StateScores {
+		t.Fatalf("want scores screen, got state %v", game.State)
+	}
+
+	game.HandleCommand(CommandMenuDown)
+
+	if filter := game.ScoreFilters()[game.ScoresScreen.Filter]; filter != "Decker" {
+		t.Errorf("want Decker filter, got %q", filter)
+	}
+
+	game.HandleCommand(CommandMenuUp)
+	game.HandleCommand(CommandMenuUp)
+
+	if filter := game.ScoreFilters()[game.ScoresScreen.Filter]; filter != "Mage" {
+		t.Errorf("want filter to wrap to Mage, got %q", filter)
+	}
+
+	game.HandleCommand(CommandCancel)
+
+	if game.State != StateTitleScreen {
+		t.Errorf("want title screen, got state %v", game.State)
+	}
+}
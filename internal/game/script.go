@@ -0,0 +1,389 @@
+package game
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// scriptCallStackSize bounds how deeply scripts may recurse.
+const scriptCallStackSize = 64
+
+// scriptLibs are the Lua standard libraries scripts may use. The io, os,
+// package, debug, and channel libraries are left out so scripts can only
+// touch the game through the game table.
+var scriptLibs = []struct {
+	name string
+	open lua.LGFunction
+}{
+	{lua.BaseLibName, lua.OpenBase},
+	{lua.TabLibName, lua.OpenTable},
+	{lua.StringLibName, lua.OpenString},
+	{lua.MathLibName, lua.OpenMath},
+}
+
+// scriptUnsafeGlobals are base library functions removed from scripts:
+// those that load code from files or strings, and those that escape the
+// sandbox or the game's random number generator.
+var scriptUnsafeGlobals = []string{
+	"dofile", "loadfile", "load", "loadstring", "require", "module",
+	"collectgarbage", "getfenv", "setfenv", "newproxy", "print", "_printregs",
+}
+
+// ScriptTarget is what a script runs on: the tile, monster, item, or quest
+// that owns it and where it is. Scripts see it as the self table.
+type ScriptTarget struct {
+	Kind string // Kind is "tile", "monster", "item", or "quest".
+	ID   string // ID is the definition's ID.
+	Name string // Name is the definition's name.
+	X    int    // X is the target's horizontal position in tile coordinates.
+	Y    int    // Y is the target's vertical position in tile coordinates.
+}
+
+// validateScript checks that source, the script for hook, compiles. An empty
+// script is allowed.
+func validateScript(hook, source string) error {
+	if source == "" {
+		return nil
+	}
+
+	if _, err := parse.Parse(strings.NewReader(source), hook); err != nil {
+		return fmt.Errorf("%s script: %w", hook, err)
+	}
+
+	return nil
+}
+
+// RunScript runs source in a fresh sandboxed Lua state with the game table
+// and self describing target, within the step and memory limits. name
+// identifies the script in errors.
+func (game *Game) RunScript(name, source string, target ScriptTarget) error {
+	state := game.newScriptState(target)
+	defer state.Close()
+
+	budget := newScriptBudget()
+	state.SetContext(budget)
+	limitScript(state, budget)
+
+	chunk, err := parse.Parse(strings.NewReader(source), name)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrScriptFailed, err)
+	}
+
+	limitConcat(chunk)
+
+	proto, err := lua.Compile(chunk, name)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrScriptFailed, err)
+	}
+
+	state.Push(state.NewFunctionFromProto(proto))
+	if err := state.PCall(0, 0, nil); err != nil {
+		return fmt.Errorf("%w: %v", ErrScriptFailed, err)
+	}
+
+	return nil
+}
+
+// runHook runs a content script, reporting failures in the message log so a
+// broken mod does not end the run. Does nothing if source is empty.
+func (game *Game) runHook(hook, source string, target ScriptTarget) {
+	if source == "" {
+		return
+	}
+
+	name := fmt.Sprintf("%s %s %s", target.Kind, target.ID, hook)
+	if err := game.RunScript(name, source, target); err != nil {
+		game.AddImportantMessage(fmt.Sprintf("The %s script of %s failed: %v", hook, target.Name, err))
+	}
+}
+
+// newScriptState creates a Lua state with only the safe standard libraries,
+// the game table, and the self table.
+func (game *Game) newScriptState(target ScriptTarget) *lua.LState {
+	state := lua.NewState(lua.Options{SkipOpenLibs: true, CallStackSize: scriptCallStackSize})
+
+	for _, lib := range scriptLibs {
+		state.Push(state.NewFunction(lib.open))
+		state.Push(lua.LString(lib.name))
+		state.Call(1, 0)
+	}
+
+	for _, name := range scriptUnsafeGlobals {
+		state.SetGlobal(name, lua.LNil)
+	}
+
+	// math.random would use a generator outside the run's seed and break
+	// replays; game.random replaces it
+	if math, ok := state.GetGlobal(lua.MathLibName).(*lua.LTable); ok {
+		math.RawSetString("random", lua.LNil)
+		math.RawSetString("randomseed", lua.LNil)
+	}
+
+	api := state.NewTable()
+	state.SetFuncs(api, map[string]lua.LGFunction{
+		"damage":   game.luaDamage(target),
+		"heal":     game.luaHeal,
+		"spawn":    game.luaSpawn,
+		"message":  game.luaMessage,
+		"teleport": game.luaTeleport,
+		"player":   game.luaPlayer,
+		"flag":     game.luaFlag,
+		"set_flag": game.luaSetFlag,
+		"random":   game.luaRandom,
+	})
+	state.SetGlobal("game", api)
+
+	self := state.NewTable()
+	self.RawSetString("kind", lua.LString(target.Kind))
+	self.RawSetString("id", lua.LString(target.ID))
+	self.RawSetString("name", lua.LString(target.Name))
+	self.RawSetString("x", lua.LNumber(target.X))
+	self.RawSetString("y", lua.LNumber(target.Y))
+	state.SetGlobal("self", self)
+
+	return state
+}
+
+// luaDamage returns game.damage(amount [, cause]), which hurts the player.
+// The cause shown on death defaults to the script's owner.
+func (game *Game) luaDamage(target ScriptTarget) lua.LGFunction {
+	return func(state *lua.LState) int {
+		amount := state.CheckInt(1)
+		if amount < 0 {
+			state.ArgError(1, "damage must not be negative")
+		}
+
+		game.DamagePlayer(amount, state.OptString(2, target.Name))
+
+		return 0
+	}
+}
+
+// luaHeal is game.heal(amount), which restores the player's health up to
+// its maximum.
+func (game *Game) luaHeal(state *lua.LState) int {
+	amount := state.CheckInt(1)
+	if amount < 0 {
+		state.ArgError(1, "healing must not be negative")
+	}
+
+	game.Player.Health = min(game.Player.Health+amount, maxHealth)
+
+	return 0
+}
+
+// luaSpawn is game.spawn(kind, id [, x, y]), which places a defined monster
+// or item at (x, y), or next to the player when no position is given.
+// Returns true if it was placed.
+func (game *Game) luaSpawn(state *lua.LState) int {
+	kind := EntityKind(state.CheckString(1))
+	id := state.CheckString(2)
+
+	var entity Entity
+
+	switch kind {
+	case EntityMonster:
+		def, ok := game.Content.Monsters[id]
+		if !ok {
+			state.ArgError(2, fmt.Sprintf("unknown monster %q", id))
+		}
+
+		entity = def.Entity(0, 0)
+	case EntityItem:
+		def, ok := game.Content.Items[id]
+		if !ok {
+			state.ArgError(2, fmt.Sprintf("unknown item %q", id))
+		}
+
+		entity = def.Entity(0, 0)
+	default:
+		state.ArgError(1, fmt.Sprintf("unknown kind %q", kind))
+	}
+
+	x, y, ok := game.freeTileNear(game.Player.X, game.Player.Y)
+	if state.GetTop() >= 4 {
+		x, y = state.CheckInt(3), state.CheckInt(4)
+		ok = game.InBounds(x, y) && game.Tiles[y][x].Walkable
+	}
+
+	if ok {
+		entity.X, entity.Y = x, y
+		game.SpawnEntity(entity)
+	}
+
+	state.Push(lua.LBool(ok))
+
+	return 1
+}
+
+// luaMessage is game.message(text), which adds text to the message log.
+func (game *Game) luaMessage(state *lua.LState) int {
+	game.AddMessage(state.CheckString(1))
+
+	return 0
+}
+
+// luaTeleport is game.teleport(x, y), which moves the player to a walkable
+// tile. Returns true if the player moved.
+func (game *Game) luaTeleport(state *lua.LState) int {
+	x, y := state.CheckInt(1), state.CheckInt(2)
+
+	ok := game.InBounds(x, y) && game.Tiles[y][x].Walkable
+	if ok {
+		game.Player.X, game.Player.Y = x, y
+		game.CameraX, game.CameraY = x, y
+		game.CancelTravel()
+	}
+
+	state.Push(lua.LBool(ok))
+
+	return 1
+}
+
+// luaPlayer is game.player(), which returns a table describing the player:
+// x, y, health, depth, and turn.
+func (game *Game) luaPlayer(state *lua.LState) int {
+	player := state.NewTable()
+	player.RawSetString("x", lua.LNumber(game.Player.X))
+	player.RawSetString("y", lua.LNumber(game.Player.Y))
+	player.RawSetString("health", lua.LNumber(game.Player.Health))
+	player.RawSetString("depth", lua.LNumber(game.Depth))
+	player.RawSetString("turn", lua.LNumber(game.TurnCount))
+	state.Push(player)
+
+	return 1
+}
+
+// luaFlag is game.flag(name), which returns a value stored with
+// game.set_flag, or nil.
+func (game *Game) luaFlag(state *lua.LState) int {
+	value, ok := game.Flags[state.CheckString(1)]
+	if !ok {
+		state.Push(lua.LNil)
+		return 1
+	}
+
+	state.Push(lua.LString(value))
+
+	return 1
+}
+
+// luaSetFlag is game.set_flag(name, value), which stores value as a string
+// for the rest of the run; a nil value clears the flag. Flags are saved with
+// the run, so quests can remember their progress.
+func (game *Game) luaSetFlag(state *lua.LState) int {
+	name := state.CheckString(1)
+
+	if state.Get(2) == lua.LNil {
+		delete(game.Flags, name)
+		return 0
+	}
+
+	if game.Flags == nil {
+		game.Flags = map[string]string{}
+	}
+
+	game.Flags[name] = state.ToStringMeta(state.Get(2)).String()
+
+	return 0
+}
+
+// luaRandom is game.random(n), which returns a number from 1 to n drawn from
+// the run's random number generator so replays stay deterministic.
+func (game *Game) luaRandom(state *lua.LState) int {
+	n := state.CheckInt(1)
+	if n < 1 {
+		state.ArgError(1, "n must be at least 1")
+	}
+
+	state.Push(lua.LNumber(game.rng.IntN(n) + 1))
+
+	return 1
+}
+
+// attack runs the on_hit script of the monster the player bumped into.
+// Monsters without one are only announced.
+func (game *Game) attack(monster *Entity) {
+	game.emit(Event{Kind: EventAttacked, Actor: game.Player.Name, Target: monster.Name, X: monster.X, Y: monster.Y})
+
+	def, ok := game.Content.Monsters[monster.ID]
+	if !ok || def.OnHit == "" {
+		game.AddMessage(fmt.Sprintf("You attack the %s.", monster.Name))
+		return
+	}
+
+	game.runHook("on_hit", def.OnHit, ScriptTarget{Kind: "monster", ID: def.ID, Name: monster.Name, X: monster.X, Y: monster.Y})
+}
+
+// enterTile runs the tile's on_enter script for the tile the player just
+// stepped onto, then names any item lying there.
+func (game *Game) enterTile(x, y int) {
+	if def, ok := game.Content.tileDef(game.Tiles[y][x].Glyph); ok {
+		game.runHook("on_enter", def.OnEnter, ScriptTarget{Kind: "tile", ID: def.ID, Name: def.Name, X: x, Y: y})
+	}
+
+	if item, ok := game.EntityAt(x, y); ok && item.Kind == EntityItem {
+		game.AddMessage(fmt.Sprintf("You see a %s here.", item.Name))
+	}
+}
+
+// UseItem runs the on_use script of the item the player is standing on,
+// which uses the item up and takes a turn. There is no inventory yet, so an
+// item without a script cannot be used and stays where it is.
+func (game *Game) UseItem() {
+	x, y := game.Player.X, game.Player.Y
+
+	item, ok := game.EntityAt(x, y)
+	if !ok || item.Kind != EntityItem {
+		game.AddMessage("There is nothing here to use.")
+		return
+	}
+
+	def, ok := game.Content.Items[item.ID]
+	if !ok || def.OnUse == "" {
+		game.AddMessage(fmt.Sprintf("You cannot use the %s.", item.Name))
+		return
+	}
+
+	defer game.Tick()
+
+	target := ScriptTarget{Kind: "item", ID: def.ID, Name: item.Name, X: x, Y: y}
+	game.RemoveEntity(item)
+	game.emit(Event{Kind: EventItemPickedUp, Actor: game.Player.Name, Item: target.Name, X: x, Y: y})
+	game.runHook("on_use", def.OnUse, target)
+}
+
+// runQuests runs the script of every quest triggered by event. Quests that
+// do not repeat are marked done in the run's flags after their first run.
+func (game *Game) runQuests(event Event) {
+	if game.Content == nil {
+		return
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(game.Content.Quests)) {
+		quest := game.Content.Quests[id]
+		if quest.Trigger != event.Kind {
+			continue
+		}
+
+		done := "quest." + quest.ID
+		if !quest.Repeat && game.Flags[done] != "" {
+			continue
+		}
+
+		if !quest.Repeat {
+			if game.Flags == nil {
+				game.Flags = map[string]string{}
+			}
+
+			game.Flags[done] = "done"
+		}
+
+		game.runHook("script", quest.Script, ScriptTarget{Kind: "quest", ID: quest.ID, Name: quest.Name, X: event.X, Y: event.Y})
+	}
+}
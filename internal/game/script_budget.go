@@ -0,0 +1,279 @@
+package game
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/ast"
+)
+
+const (
+	// scriptMaxSteps bounds how many Lua instructions one script may run, so
+	// a runaway loop in a mod cannot hang the game. Counting instructions
+	// rather than time stops a script at the same point on every machine,
+	// which keeps replays deterministic.
+	scriptMaxSteps = 1_000_000
+
+	// scriptMaxMemory bounds how many bytes of strings one script may build.
+	// Tables and closures can only grow a little per instruction, so the
+	// step limit bounds them.
+	scriptMaxMemory = 16 << 20
+
+	// scriptConcat is the global the .. operator is rewritten to call.
+	scriptConcat = "_concat"
+)
+
+// errScriptSteps is raised in a script that runs out of steps.
+var errScriptSteps = fmt.Errorf("script ran more than %d steps", scriptMaxSteps)
+
+// scriptFormatWidth matches a string.format directive with a width or
+// precision of three or more digits, which Lua rejects and which could
+// otherwise pad a string to any size.
+var scriptFormatWidth = regexp.MustCompile(`%[-+ #0]*(\d{3,}|\d*\.\d{3,})`)
+
+// scriptBudget limits one script run to scriptMaxSteps instructions and
+// scriptMaxMemory bytes of strings. It is the Lua state's context: the VM
+// checks Done before every instruction, so counting the checks counts the
+// instructions.
+type scriptBudget struct {
+	steps  int           // steps is how many more instructions may run.
+	memory int           // memory is how many more bytes of strings may be built.
+	done   chan struct{} // done is closed once the steps run out.
+	err    error         // err is errScriptSteps once the steps run out.
+}
+
+// newScriptBudget returns a full budget for one script run.
+func newScriptBudget() *scriptBudget {
+	return &scriptBudget{steps: scriptMaxSteps, memory: scriptMaxMemory, done: make(chan struct{})}
+}
+
+// Deadline returns no deadline; the budget counts steps, not time.
+func (budget *scriptBudget) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Done spends one step and returns a channel that is closed once the steps
+// run out.
+func (budget *scriptBudget) Done() <-chan struct{} {
+	budget.steps--
+	if budget.steps == 0 {
+		budget.err = errScriptSteps
+		close(budget.done)
+	}
+
+	return budget.done
+}
+
+// Err returns errScriptSteps once the steps have run out, and nil before.
+func (budget *scriptBudget) Err() error {
+	return budget.err
+}
+
+// Value returns nil; the budget carries no values.
+func (budget *scriptBudget) Value(key any) any {
+	return nil
+}
+
+// spend takes bytes from the memory budget, raising a script error instead
+// if there are not that many left.
+func (budget *scriptBudget) spend(state *lua.LState, bytes int) {
+	if bytes > budget.memory {
+		state.RaiseError("script built more than %d bytes of strings", scriptMaxMemory)
+	}
+
+	budget.memory -= bytes
+}
+
+// limitScript installs the checked string builders in state: the function
+// the .. operator is rewritten to call, and string and table functions that
+// spend the memory budget. Builders whose size is known from their
+// arguments check it before building; the rest spend what they return.
+func limitScript(state *lua.LState, budget *scriptBudget) {
+	state.SetGlobal(scriptConcat, state.NewFunction(func(state *lua.LState) int {
+		lhs, rhs := state.CheckAny(1), state.CheckAny(2)
+		for _, value := range []lua.LValue{lhs, rhs} {
+			if !lua.LVCanConvToString(value) {
+				state.RaiseError("attempt to concatenate a %s value", value.Type())
+			}
+		}
+
+		left, right := lua.LVAsString(lhs), lua.LVAsString(rhs)
+		budget.spend(state, len(left)+len(right))
+		state.Push(lua.LString(left + right))
+
+		return 1
+	}))
+
+	if library, ok := state.GetGlobal(lua.StringLibName).(*lua.LTable); ok {
+		limitFunction(state, budget, library, "rep", func(state *lua.LState) int {
+			size, count := len(state.CheckString(1)), state.CheckInt(2)
+			if count <= 0 || size == 0 {
+				return 0
+			}
+
+			// Checked by division so a huge count cannot overflow
+			if count > scriptMaxMemory/size {
+				return scriptMaxMemory + 1
+			}
+
+			return size * count
+		})
+
+		limitFunction(state, budget, library, "format", func(state *lua.LState) int {
+			if scriptFormatWidth.MatchString(state.CheckString(1)) {
+				state.RaiseError("invalid format (width or precision too long)")
+			}
+
+			return -1
+		})
+
+		for _, name := range []string{"gsub", "lower", "upper", "reverse"} {
+			limitFunction(state, budget, library, name, func(*lua.LState) int { return -1 })
+		}
+	}
+
+	if library, ok := state.GetGlobal(lua.TabLibName).(*lua.LTable); ok {
+		limitFunction(state, budget, library, "concat", func(state *lua.LState) int {
+			table := state.CheckTable(1)
+			separator := len(state.OptString(2, ""))
+
+			size := 0
+			table.ForEach(func(_, value lua.LValue) {
+				size += len(lua.LVAsString(value)) + separator
+			})
+
+			return size
+		})
+	}
+}
+
+// limitFunction replaces the function name in library with one that spends
+// the memory budget. size returns an upper bound on the bytes the call will
+// build, spent before the call, or -1 to spend the strings it returns after
+// the call instead.
+func limitFunction(state *lua.LState, budget *scriptBudget, library *lua.LTable, name string, size func(*lua.LState) int) {
+	original, ok := library.RawGetString(name).(*lua.LFunction)
+	if !ok || original.GFunction == nil {
+		return
+	}
+
+	library.RawSetString(name, state.NewFunction(func(state *lua.LState) int {
+		bound := size(state)
+		if bound >= 0 {
+			budget.spend(state, bound)
+		}
+
+		results := original.GFunction(state)
+
+		if bound < 0 {
+			for i := 1; i <= results; i++ {
+				if text, ok := state.Get(-i).(lua.LString); ok {
+					budget.spend(state, len(text))
+				}
+			}
+		}
+
+		return results
+	}))
+}
+
+// limitConcat rewrites every .. in stmts into a call to the scriptConcat
+// function, so strings built by concatenation spend the memory budget.
+func limitConcat(stmts []ast.Stmt) {
+	for _, stmt := range stmts {
+		switch stmt := stmt.(type) {
+		case *ast.AssignStmt:
+			limitConcatExprs(stmt.Lhs)
+			limitConcatExprs(stmt.Rhs)
+		case *ast.LocalAssignStmt:
+			limitConcatExprs(stmt.Exprs)
+		case *ast.FuncCallStmt:
+			stmt.Expr = limitConcatExpr(stmt.Expr)
+		case *ast.DoBlockStmt:
+			limitConcat(stmt.Stmts)
+		case *ast.WhileStmt:
+			stmt.Condition = limitConcatExpr(stmt.Condition)
+			limitConcat(stmt.Stmts)
+		case *ast.RepeatStmt:
+			stmt.Condition = limitConcatExpr(stmt.Condition)
+			limitConcat(stmt.Stmts)
+		case *ast.IfStmt:
+			stmt.Condition = limitConcatExpr(stmt.Condition)
+			limitConcat(stmt.Then)
+			limitConcat(stmt.Else)
+		case *ast.NumberForStmt:
+			stmt.Init = limitConcatExpr(stmt.Init)
+			stmt.Limit = limitConcatExpr(stmt.Limit)
+			stmt.Step = limitConcatExpr(stmt.Step)
+			limitConcat(stmt.Stmts)
+		case *ast.GenericForStmt:
+			limitConcatExprs(stmt.Exprs)
+			limitConcat(stmt.Stmts)
+		case *ast.FuncDefStmt:
+			limitConcat(stmt.Func.Stmts)
+		case *ast.ReturnStmt:
+			limitConcatExprs(stmt.Exprs)
+		}
+	}
+}
+
+// limitConcatExprs rewrites the .. operators in each of exprs.
+func limitConcatExprs(exprs []ast.Expr) {
+	for i, expr := range exprs {
+		exprs[i] = limitConcatExpr(expr)
+	}
+}
+
+// limitConcatExpr returns expr with its .. operators rewritten into calls to
+// the scriptConcat function.
+func limitConcatExpr(expr ast.Expr) ast.Expr {
+	switch expr := expr.(type) {
+	case *ast.StringConcatOpExpr:
+		function := &ast.IdentExpr{Value: scriptConcat}
+		function.SetLine(expr.Line())
+		function.SetLastLine(expr.LastLine())
+
+		call := &ast.FuncCallExpr{
+			Func:      function,
+			Args:      []ast.Expr{limitConcatExpr(expr.Lhs), limitConcatExpr(expr.Rhs)},
+			AdjustRet: true,
+		}
+		call.SetLine(expr.Line())
+		call.SetLastLine(expr.LastLine())
+
+		return call
+	case *ast.AttrGetExpr:
+		expr.Object = limitConcatExpr(expr.Object)
+		expr.Key = limitConcatExpr(expr.Key)
+	case *ast.TableExpr:
+		for _, field := range expr.Fields {
+			field.Key = limitConcatExpr(field.Key)
+			field.Value = limitConcatExpr(field.Value)
+		}
+	case *ast.FuncCallExpr:
+		expr.Func = limitConcatExpr(expr.Func)
+		expr.Receiver = limitConcatExpr(expr.Receiver)
+		limitConcatExprs(expr.Args)
+	case *ast.LogicalOpExpr:
+		expr.Lhs = limitConcatExpr(expr.Lhs)
+		expr.Rhs = limitConcatExpr(expr.Rhs)
+	case *ast.RelationalOpExpr:
+		expr.Lhs = limitConcatExpr(expr.Lhs)
+		expr.Rhs = limitConcatExpr(expr.Rhs)
+	case *ast.ArithmeticOpExpr:
+		expr.Lhs = limitConcatExpr(expr.Lhs)
+		expr.Rhs = limitConcatExpr(expr.Rhs)
+	case *ast.UnaryMinusOpExpr:
+		expr.Expr = limitConcatExpr(expr.Expr)
+	case *ast.UnaryNotOpExpr:
+		expr.Expr = limitConcatExpr(expr.Expr)
+	case *ast.UnaryLenOpExpr:
+		expr.Expr = limitConcatExpr(expr.Expr)
+	case *ast.FunctionExpr:
+		limitConcat(expr.Stmts)
+	}
+
+	return expr
+}
@@ -0,0 +1,348 @@
+package game
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// scriptGame returns a game in play with a small set of definitions for
+// scripts to use.
+func scriptGame(t *testing.T, file ContentFile) *Game {
+	t.Helper()
+
+	if err := file.Validate(); err != nil {
+		t.Fatalf("invalid test content: %v", err)
+	}
+
+	content := NewContent()
+	content.Add(ContentFile{
+		Monsters: []MonsterDef{{ID: "ganger", Name: "ganger", Glyph: "g", Health: 10, Depth: 1}},
+		Items:    []ItemDef{{ID: "medkit", Name: "medkit", Glyph: "+"}},
+	})
+	content.Add(file)
+
+	game := NewGame()
+	game.SetContent(content)
+	game.State = StatePlaying
+
+	return game
+}
+
+func TestRunScript(t *testing.T) {
+	target := ScriptTarget{Kind: "item", ID: "medkit", Name: "medkit", X: 3, Y: 4}
+
+	tests := []struct {
+		name    string
+		script  string
+		wantErr error
+		check   func(t *testing.T, game *Game)
+	}{
+		{name: "message", script: `game.message("hello " .. self.name .. " at " .. self.x .. "," .. self.y)`, check: func(t *testing.T, game *Game) {
+			if got := game.Messages[len(game.Messages)-1].Text; got != "hello medkit at 3,4" {
+				t.Errorf("want message about self, got %q", got)
+			}
+		}},
+		{name: "damage", script: `game.damage(30)`, check: func(t *testing.T, game *Game) {
+			if game.Player.Health != 70 || game.lastDamage != "medkit" {
+				t.Errorf("want 70 health from the medkit, got %d from %q", game.Player.Health, game.lastDamage)
+			}
+		}},
+		{name: "heal is capped", script: `game.damage(10) game.heal(50)`, check: func(t *testing.T, game *Game) {
+			if game.Player.Health != maxHealth {
+				t.Errorf("want %d health, got %d", maxHealth, game.Player.Health)
+			}
+		}},
+		{name: "spawn beside the player", script: `assert(game.spawn("monster", "ganger"))`, check: func(t *testing.T, game *Game) {
+			if len(game.Entities) != 1 || game.Entities[0].ID != "ganger" {
+				t.Errorf("want a ganger spawned, got %+v", game.Entities)
+			}
+		}},
+		{name: "spawn at a position", script: `assert(game.spawn("item", "medkit", 20, 9))`, check: func(t *testing.T, game *Game) {
+			if entity, ok := game.EntityAt(20, 9); !ok || entity.ID != "medkit" {
+				t.Errorf("want a medkit at 20,9, got %+v", game.Entities)
+			}
+		}},
+		{name: "spawn in a wall", script: `assert(not game.spawn("item", "medkit", 0, 0))`},
+		{name: "spawn unknown monster", script: `game.spawn("monster", "dragon")`, wantErr: ErrScriptFailed},
+		{name: "teleport", script: `assert(game.teleport(20, 9))`, check: func(t *testing.T, game *Game) {
+			if game.Player.X != 20 || game.Player.Y != 9 {
+				t.Errorf("want player at 20,9, got %d,%d", game.Player.X, game.Player.Y)
+			}
+		}},
+		{name: "teleport into a wall", script: `assert(not game.teleport(0, 0))`},
+		{name: "player", script: `local p = game.player() assert(p.x == 17 and p.y == 9 and p.health == 100 and p.depth == 1)`},
+		{name: "flags", script: `assert(game.flag("door") == nil) game.set_flag("door", 2) assert(game.flag("door") == "2")`, check: func(t *testing.T, game *Game) {
+			if game.Flags["door"] != "2" {
+				t.Errorf("want door flag 2, got %q", game.Flags["door"])
+			}
+		}},
+		{name: "random", script: `for i = 1, 20 do local n = game.random(3) assert(n >= 1 and n <= 3) end`},
+		{name: "runtime error", script: `error("boom")`, wantErr: ErrScriptFailed},
+		{name: "syntax error", script: `game.message(`, wantErr: ErrScriptFailed},
+		{name: "bad argument", script: `game.heal(-1)`, wantErr: ErrScriptFailed},
+		{name: "runaway loop", script: `while true do end`, wantErr: ErrScriptFailed},
+		{name: "concatenation", script: `local s = "a" .. 1 .. "b" assert(s == "a1b") assert(("x"):rep(3) .. "" == "xxx")`},
+		{name: "concatenate nil", script: `local s = "a" .. nil`, wantErr: ErrScriptFailed},
+		{name: "huge repeat", script: `string.rep("x", 1e9)`, wantErr: ErrScriptFailed},
+		{name: "huge repeat as a method", script: `("x"):rep(1e9)`, wantErr: ErrScriptFailed},
+		{name: "doubling concatenation", script: `local s = "x" for i = 1, 40 do s = s .. s end`, wantErr: ErrScriptFailed},
+		{name: "huge table concat", script: `local t = {} for i = 1, 20 do t[i] = string.rep("x", 1e6) end table.concat(t)`, wantErr: ErrScriptFailed},
+		{name: "huge format width", script: `string.format("%999999999d", 1)`, wantErr: ErrScriptFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := scriptGame(t, ContentFile{})
+
+			err := game.RunScript("test", tt.script, target)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want %v, got %v", tt.wantErr, err)
+			}
+
+			if tt.check != nil {
+				tt.check(t, game)
+			}
+		})
+	}
+}
+
+func TestScriptStepLimit(t *testing.T) {
+	// A runaway script stops after the same number of steps every time
+	script := `local n = 0 while true do n = n + 1 game.set_flag("n", n) end`
+
+	var stopped []string
+	for range 2 {
+		game := scriptGame(t, ContentFile{})
+
+		err := game.RunScript("test", script, ScriptTarget{})
+		if !errors.Is(err, ErrScriptFailed) || !strings.Contains(err.Error(), "steps") {
+			t.Fatalf("want the step limit reached, got %v", err)
+		}
+
+		stopped = append(stopped, game.Flags["n"])
+	}
+
+	if stopped[0] == "" || stopped[0] != stopped[1] {
+		t.Errorf("want both runs stopped at the same iteration, got %q", stopped)
+	}
+}
+
+func TestScriptSandbox(t *testing.T) {
+	for _, name := range []string{"os", "io", "package", "debug", "require", "dofile", "loadfile", "load", "loadstring", "math.random"} {
+		t.Run(name, func(t *testing.T) {
+			game := scriptGame(t, ContentFile{})
+
+			if err := game.RunScript("test", "assert("+name+" == nil)", ScriptTarget{}); err != nil {
+				t.Errorf("want %s unavailable, got %v", name, err)
+			}
+		})
+	}
+
+	t.Run("standard libraries", func(t *testing.T) {
+		game := scriptGame(t, ContentFile{})
+
+		script := `assert(string.upper("a") == "A" and math.max(1, 2) == 2 and table.concat({"a", "b"}) == "ab")`
+		if err := game.RunScript("test", script, ScriptTarget{}); err != nil {
+			t.Errorf("want string, math, and table available, got %v", err)
+		}
+	})
+
+	t.Run("random follows the seed", func(t *testing.T) {
+		rolls := func() string {
+			game := scriptGame(t, ContentFile{})
+			game.SetSeed(7)
+
+			if err := game.RunScript("test", `for i = 1, 10 do game.set_flag("r" .. i, game.random(100)) end`, ScriptTarget{}); err != nil {
+				t.Fatalf("failed to run script: %v", err)
+			}
+
+			var builder strings.Builder
+			for i := range 10 {
+				builder.WriteString(game.Flags["r"+strconv.Itoa(i+1)] + " ")
+			}
+
+			return builder.String()
+		}
+
+		if first, second := rolls(), rolls(); first != second {
+			t.Errorf("want the same rolls for the same seed, got %q and %q", first, second)
+		}
+	})
+}
+
+func TestScriptHooks(t *testing.T) {
+	t.Run("item is used by the use command", func(t *testing.T) {
+		game := scriptGame(t, ContentFile{Items: []ItemDef{{ID: "stimpatch", Name: "stimpatch", Glyph: "!", OnUse: `game.heal(15)`}}})
+		game.Player.Health = 50
+		game.SpawnEntity(game.Content.Items["stimpatch"].Entity(18, 9))
+
+		game.MovePlayer(1, 0)
+
+		if game.Player.Health != 50 || len(game.Entities) != 1 {
+			t.Fatalf("want the stimpatch left alone when stepped on, got health %d", game.Player.Health)
+		}
+
+		if got := game.Messages[len(game.Messages)-1].Text; got != "You see a stimpatch here." {
+			t.Errorf("want the stimpatch described, got %q", got)
+		}
+
+		turn := game.TurnCount
+		game.HandleCommand(CommandUseItem)
+
+		if game.Player.Health != 65 {
+			t.Errorf("want 65 health, got %d", game.Player.Health)
+		}
+
+		if len(game.Entities) != 0 {
+			t.Errorf("want the stimpatch used up, got %+v", game.Entities)
+		}
+
+		if game.TurnCount != turn+1 {
+			t.Errorf("want using an item to take a turn, got turn %d", game.TurnCount)
+		}
+	})
+
+	t.Run("item without a script stays", func(t *testing.T) {
+		game := scriptGame(t, ContentFile{})
+		game.SpawnEntity(game.Content.Items["medkit"].Entity(18, 9))
+
+		game.MovePlayer(1, 0)
+		game.UseItem()
+
+		if len(game.Entities) != 1 {
+			t.Errorf("want the medkit left on the floor, got %+v", game.Entities)
+		}
+
+		if got := game.Messages[len(game.Messages)-1].Text; got != "You cannot use the medkit." {
+			t.Errorf("want the medkit refused, got %q", got)
+		}
+	})
+
+	t.Run("nothing to use", func(t *testing.T) {
+		game := scriptGame(t, ContentFile{})
+		turn := game.TurnCount
+
+		game.UseItem()
+
+		if game.TurnCount != turn {
+			t.Errorf("want no turn taken, got turn %d", game.TurnCount)
+		}
+	})
+
+	t.Run("monster is hit instead of moved into", func(t *testing.T) {
+		game := scriptGame(t, ContentFile{Monsters: []MonsterDef{{ID: "drone", Name: "drone", Glyph: "d", Health: 5, Depth: 1, OnHit: `game.damage(2)`}}})
+		game.SpawnEntity(game.Content.Monsters["drone"].Entity(18, 9))
+
+		game.MovePlayer(1, 0)
+
+		if game.Player.X != 17 {
+			t.Errorf("want the player to stay at x 17, got %d", game.Player.X)
+		}
+
+		if game.Player.Health != 98 {
+			t.Errorf("want 98 health after the drone zaps back, got %d", game.Player.Health)
+		}
+	})
+
+	t.Run("tile enter", func(t *testing.T) {
+		game := scriptGame(t, ContentFile{Tiles: []TileDef{{ID: "floor", Name: "floor", Glyph: ".", Color: "floor", Walkable: true, OnEnter: `game.set_flag("stepped", self.x)`}}})
+
+		game.MovePlayer(1, 0)
+
+		if game.Flags["stepped"] != "18" {
+			t.Errorf("want stepped flag 18, got %q", game.Flags["stepped"])
+		}
+	})
+
+	t.Run("broken script is reported", func(t *testing.T) {
+		game := scriptGame(t, ContentFile{Items: []ItemDef{{ID: "bomb", Name: "bomb", Glyph: "b", OnUse: `error("fizzle")`}}})
+		game.SpawnEntity(game.Content.Items["bomb"].Entity(18, 9))
+
+		game.MovePlayer(1, 0)
+		game.UseItem()
+
+		message := game.Messages[len(game.Messages)-1]
+		if !message.Important || !strings.Contains(message.Text, "fizzle") {
+			t.Errorf("want an important message about the script, got %+v", message)
+		}
+	})
+}
+
+func TestQuests(t *testing.T) {
+	game := scriptGame(t, ContentFile{Quests: []QuestDef{
+		{ID: "first_step", Name: "first step", Trigger: EventMoved, Script: `game.set_flag("first", (game.flag("first") or 0) + 1)`},
+		{ID: "every_step", Name: "every step", Trigger: EventMoved, Repeat: true, Script: `game.set_flag("every", (game.flag("every") or 0) + 1)`},
+	}})
+
+	game.MovePlayer(1, 0)
+	game.MovePlayer(-1, 0)
+	game.MovePlayer(1, 0)
+
+	if game.Flags["first"] != "1" {
+		t.Errorf("want the one-off quest run once, got %q", game.Flags["first"])
+	}
+
+	if game.Flags["every"] != "3" {
+		t.Errorf("want the repeating quest run 3 times, got %q", game.Flags["every"])
+	}
+
+	t.Run("done quests are saved", func(t *testing.T) {
+		data, err := game.MarshalState()
+		if err != nil {
+			t.Fatalf("failed to save: %v", err)
+		}
+
+		restored := scriptGame(t, ContentFile{Quests: []QuestDef{
+			{ID: "first_step", Name: "first step", Trigger: EventMoved, Script: `game.set_flag("first", "again")`},
+		}})
+
+		if err := restored.RestoreState(data); err != nil {
+			t.Fatalf("failed to restore: %v", err)
+		}
+
+		restored.MovePlayer(-1, 0)
+
+		if restored.Flags["first"] != "1" {
+			t.Errorf("want the done quest skipped, got %q", restored.Flags["first"])
+		}
+	})
+}
+
+func TestValidateScripts(t *testing.T) {
+	tests := []struct {
+		name string
+		file ContentFile
+		want string
+	}{
+		{
+			name: "item syntax error",
+			file: ContentFile{Items: []ItemDef{{ID: "x", Name: "x", Glyph: "x", OnUse: "game.heal("}}},
+			want: `item "x": on_use script:`,
+		},
+		{
+			name: "unknown quest trigger",
+			file: ContentFile{Quests: []QuestDef{{ID: "x", Name: "x", Trigger: "sneezed", Script: "x = 1"}}},
+			want: `quest "x": unknown trigger "sneezed"`,
+		},
+		{
+			name: "quest without a script",
+			file: ContentFile{Quests: []QuestDef{{ID: "x", Name: "x", Trigger: EventDied}}},
+			want: `quest "x": script is required`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.file.Validate()
+			if !errors.Is(err, ErrContentInvalid) {
+				t.Fatalf("want %v, got %v", ErrContentInvalid, err)
+			}
+
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("want error containing %q, got %q", tt.want, err.Error())
+			}
+		})
+	}
+}
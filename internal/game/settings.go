@@ -0,0 +1,188 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// Settings holds user preferences that change how the game is presented and
+// controlled without affecting game rules.
+type Settings struct {
+	KeyRepeat KeyRepeat `json:"key_repeat"` // KeyRepeat controls held-key movement.
+	Mouse     bool      `json:"mouse"`      // Mouse enables hover, click to travel, and clickable menus.
+	Gamepad   Gamepad   `json:"gamepad"`    // Gamepad controls gamepad input.
+
+	AssetsDir      string `json:"assets_dir"`      // AssetsDir is a directory whose files override the embedded assets.
+	Tileset        string `json:"tileset"`         // Tileset is the asset path of the tileset index.
+	GraphicalTiles bool   `json:"graphical_tiles"` // GraphicalTiles draws with the tileset instead of font glyphs.
+	Theme          string `json:"theme"`           // Theme names the color palette.
+
+	FontSize     float64 `json:"font_size"`     // FontSize is the font size in points, which also sets the tile size.
+	GlyphHints   bool    `json:"glyph_hints"`   // GlyphHints adds symbols to things otherwise shown only by color.
+	ScreenReader bool    `json:"screen_reader"` // ScreenReader writes a text summary of each turn to standard output.
+}
+
+const (
+	// minFontSize is the smallest font size in points.
+	minFontSize = 8
+
+	// maxFontSize is the largest font size in points.
+	maxFontSize = 48
+)
+
+// Gamepad controls gamepad input.
+type Gamepad struct {
+	Enabled  bool    `json:"enabled"`  // Enabled turns gamepad input on or off.
+	Deadzone float64 `json:"deadzone"` // Deadzone is the stick distance from center, 0 to 1, that is ignored.
+}
+
+// KeyRepeat controls how held movement keys repeat. After the first press a
+// held key waits Delay before repeating, then repeats every Interval.
+type KeyRepeat struct {
+	Enabled  bool     `json:"enabled"`  // Enabled turns held-key repeat on or off.
+	Delay    Duration `json:"delay"`    // Delay is how long a key is held before it repeats.
+	Interval Duration `json:"interval"` // Interval is the time between repeats.
+}
+
+// Duration is a time.Duration that is written to config files as a string
+// such as "250ms".
+type Duration time.Duration
+
+// DefaultSettings returns the settings used when no config file exists.
+func DefaultSettings() Settings {
+	return Settings{
+		KeyRepeat: KeyRepeat{
+			Enabled:  true,
+			Delay:    Duration(250 * time.Millisecond),
+			Interval: Duration(80 * time.Millisecond),
+		},
+		Mouse: true,
+		Gamepad: Gamepad{
+			Enabled:  true,
+			Deadzone: 0.3,
+		},
+		Tileset:  "tilesets/neon/tileset.json",
+		Theme:    DefaultTheme,
+		FontSize: 16,
+	}
+}
+
+// LoadSettings reads JSON settings from reader. Fields that are not present
+// keep their default values. Returns an error if the JSON is malformed or a
+// value is out of range.
+func LoadSettings(reader io.Reader) (Settings, error) {
+	settings := DefaultSettings()
+
+	if err := json.NewDecoder(reader).Decode(&settings); err != nil {
+		return Settings{}, fmt.Errorf("%w: %v", ErrSettingsParseFailed, err)
+	}
+
+	if err := settings.Validate(); err != nil {
+		return Settings{}, err
+	}
+
+	return settings, nil
+}
+
+// LoadSettingsFile loads settings from the JSON file at path. A missing file
+// is not an error; the default settings are returned instead.
+func LoadSettingsFile(path string) (Settings, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return DefaultSettings(), nil
+	}
+
+	if err != nil {
+		return Settings{}, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	settings, err := LoadSettings(file)
+	if err != nil {
+		return Settings{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return settings, nil
+}
+
+// SaveFile writes the settings to path as JSON, creating parent directories
+// as needed.
+func (settings Settings) SaveFile(path string) error {
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// Validate checks that every setting is within its allowed range.
+func (settings Settings) Validate() error {
+	if settings.KeyRepeat.Delay < 0 {
+		return fmt.Errorf("%w: key_repeat.delay must not be negative", ErrInvalidSetting)
+	}
+
+	if settings.KeyRepeat.Interval <= 0 {
+		return fmt.Errorf("%w: key_repeat.interval must be positive", ErrInvalidSetting)
+	}
+
+	if settings.Gamepad.Deadzone < 0 || settings.Gamepad.Deadzone >= 1 {
+		return fmt.Errorf("%w: gamepad.deadzone must be at least 0 and less than 1", ErrInvalidSetting)
+	}
+
+	if settings.FontSize < minFontSize || settings.FontSize > maxFontSize {
+		return fmt.Errorf("%w: font_size must be between %d and %d", ErrInvalidSetting, minFontSize, maxFontSize)
+	}
+
+	if _, err := LookupPalette(settings.Theme); err != nil {
+		return fmt.Errorf("%w: theme: %w", ErrInvalidSetting, err)
+	}
+
+	return nil
+}
+
+// Fires reports whether a key that has been held for held, sampled once every
+// frame, should repeat on this frame. The initial press is not a repeat.
+func (repeat KeyRepeat) Fires(held, frame time.Duration) bool {
+	if !repeat.Enabled {
+		return false
+	}
+
+	return repeat.count(held) > repeat.count(held-frame)
+}
+
+// count returns how many repeats have fired after a key has been held for
+// held.
+func (repeat KeyRepeat) count(held time.Duration) int {
+	delay := time.Duration(repeat.Delay)
+	if held < delay {
+		return 0
+	}
+
+	return 1 + int((held-delay)/time.Duration(repeat.Interval))
+}
+
+// MarshalText writes the duration in time.Duration string form.
+func (duration Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(duration).String()), nil
+}
+
+// UnmarshalText parses a duration such as "250ms" or "1.5s".
+func (duration *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	*duration = Duration(parsed)
+
+	return nil
+}
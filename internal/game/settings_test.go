@@ -0,0 +1,96 @@
+package game
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadSettings(t *testing.T) {
+	t.Run("keeps defaults for missing fields", func(t *testing.T) {
+		settings, err := LoadSettings(strings.NewReader(`{"key_repeat": {"delay": "400ms"}}`))
+		if err != nil {
+			t.Fatalf("failed to load settings: %v", err)
+		}
+
+		if settings.KeyRepeat.Delay != Duration(400*time.Millisecond) {
+			t.Errorf("want delay 400ms, got %v", time.Duration(settings.KeyRepeat.Delay))
+		}
+
+		if settings.KeyRepeat.Interval != DefaultSettings().KeyRepeat.Interval {
+			t.Errorf("want default interval, got %v", time.Duration(settings.KeyRepeat.Interval))
+		}
+
+		if !settings.KeyRepeat.Enabled {
+			t.Error("want key repeat enabled by default, got disabled")
+		}
+	})
+
+	tests := []struct {
+		name    string
+		json    string
+		wantErr error
+	}{
+		{name: "zero interval", json: `{"key_repeat": {"interval": "0s"}}`, wantErr: ErrInvalidSetting},
+		{name: "negative delay", json: `{"key_repeat": {"delay": "-1s"}}`, wantErr: ErrInvalidSetting},
+		{name: "bad duration", json: `{"key_repeat": {"delay": "soon"}}`, wantErr: ErrSettingsParseFailed},
+		{name: "font too small", json: `{"font_size": 4}`, wantErr: ErrInvalidSetting},
+		{name: "unknown theme", json: `{"theme": "sepia"}`, wantErr: ErrUnknownTheme},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadSettings(strings.NewReader(tt.json))
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("want %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadSettingsFileMissing(t *testing.T) {
+	settings, err := LoadSettingsFile(filepath.Join(t.TempDir(), "settings.json"))
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	if settings != DefaultSettings() {
+		t.Errorf("want default settings, got %+v", settings)
+	}
+}
+
+func TestKeyRepeatFires(t *testing.T) {
+	const frame = 10 * time.Millisecond
+
+	repeat := KeyRepeat{
+		Enabled:  true,
+		Delay:    Duration(100 * time.Millisecond),
+		Interval: Duration(50 * time.Millisecond),
+	}
+
+	var fired []time.Duration
+	for held := frame; held <= 250*time.Millisecond; held += frame {
+		if repeat.Fires(held, frame) {
+			fired = append(fired, held)
+		}
+	}
+
+	want := []time.Duration{100 * time.Millisecond, 150 * time.Millisecond, 200 * time.Millisecond, 250 * time.Millisecond}
+	if len(fired) != len(want) {
+		t.Fatalf("want repeats at %v, got %v", want, fired)
+	}
+
+	for i := range want {
+		if fired[i] != want[i] {
+			t.Errorf("want repeat %d at %v, got %v", i, want[i], fired[i])
+		}
+	}
+
+	repeat.Enabled = false
+	if repeat.Fires(100*time.Millisecond, frame) {
+		t.Error("want disabled repeat to never fire")
+	}
+}
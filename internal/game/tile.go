@@ -1,16 +1,17 @@
 // Package game contains core game state and logic independent of rendering.
 package game
 
-import "image/color"
-
+// FloorTile and WallTile are the built-in terrain, used when no content
+// defines the floor and wall tiles.
 var (
-	FloorTile = Tile{Glyph: '.', Color: colorGray, Walkable: true}
-	WallTile  = Tile{Glyph: '#', Color: colorGray, Walkable: false}
+	FloorTile = Tile{Name: "floor", Glyph: '.', Color: RoleFloor, Walkable: true}
+	WallTile  = Tile{Name: "wall", Glyph: '#', Color: RoleWall, Walkable: false}
 )
 
 // Tile represents a single map cell terrain in the game world.
 type Tile struct {
-	Glyph    rune        // Glyph is the rune used to render the tile.
-	Color    color.Color // Color is the color used to render the tile (color.Gray{Y: 192}).
-	Walkable bool        // Walkable indicates whether entities can move onto this tile.
+	Name     string    // Name is shown when the tile is examined.
+	Glyph    rune      // Glyph is the rune used to render the tile.
+	Color    ColorRole // Color is the palette role used to render the tile.
+	Walkable bool      // Walkable indicates whether entities can move onto this tile.
 }
@@ -0,0 +1,91 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const (
+	// TilesetLayoutCP437 is a 16x16 sheet of code page 437 characters, so any
+	// glyph with a CP437 code can be drawn without listing it in the index.
+	TilesetLayoutCP437 = "cp437"
+
+	// TilesetLayoutCustom is a sheet whose cells are all listed in the index.
+	TilesetLayoutCustom = "custom"
+)
+
+// TilesetIndex describes a sprite sheet: where the image is, how big each
+// cell is, and which cell draws each entity kind or glyph.
+type TilesetIndex struct {
+	Image      string         `json:"image"`       // Image is the PNG path, relative to the index file.
+	TileWidth  int            `json:"tile_width"`  // TileWidth is the width of one cell in pixels.
+	TileHeight int            `json:"tile_height"` // TileHeight is the height of one cell in pixels.
+	Layout     string         `json:"layout"`      // Layout is TilesetLayoutCP437 or TilesetLayoutCustom.
+	Tint       bool           `json:"tint"`        // Tint colors cells like glyphs; use for white CP437 sheets.
+	Cells      map[string]int `json:"cells"`       // Cells maps entity kinds ("player", "wall") or glyphs ("@") to cell numbers.
+}
+
+// cp437Extras maps the non-ASCII characters the game uses to their code page
+// 437 codes. ASCII characters share their codes with CP437.
+var cp437Extras = map[rune]int{
+	'░': 176, '▒': 177, '▓': 178, '│': 179, '┤': 180, '┐': 191, '└': 192,
+	'┴': 193, '┬': 194, '├': 195, '─': 196, '┼': 197, '┘': 217, '┌': 218,
+	'█': 219, '·': 250, '═': 205, '║': 186, '╔': 201, '╗': 187, '╚': 200,
+	'╝': 188, '♥': 3, '♦': 4, '♣': 5, '♠': 6, '•': 7, '≈': 247, '°': 248,
+}
+
+// LoadTilesetIndex reads a JSON tileset index from reader. Returns an error if
+// the JSON is malformed or the index is incomplete.
+func LoadTilesetIndex(reader io.Reader) (TilesetIndex, error) {
+	index := TilesetIndex{Layout: TilesetLayoutCP437, Tint: true}
+
+	if err := json.NewDecoder(reader).Decode(&index); err != nil {
+		return TilesetIndex{}, fmt.Errorf("%w: %v", ErrTilesetInvalid, err)
+	}
+
+	if index.Image == "" {
+		return TilesetIndex{}, fmt.Errorf("%w: image is required", ErrTilesetInvalid)
+	}
+
+	if index.TileWidth <= 0 || index.TileHeight <= 0 {
+		return TilesetIndex{}, fmt.Errorf("%w: tile_width and tile_height must be positive", ErrTilesetInvalid)
+	}
+
+	if index.Layout != TilesetLayoutCP437 && index.Layout != TilesetLayoutCustom {
+		return TilesetIndex{}, fmt.Errorf("%w: unknown layout %q", ErrTilesetInvalid, index.Layout)
+	}
+
+	for name, cell := range index.Cells {
+		if cell < 0 {
+			return TilesetIndex{}, fmt.Errorf("%w: cell for %q must not be negative", ErrTilesetInvalid, name)
+		}
+	}
+
+	return index, nil
+}
+
+// CellFor returns the cell that draws an entity of the given kind shown as
+// glyph. Kinds are checked first so graphical sheets can give the player and
+// terrain their own art, then the glyph, then its CP437 code.
+func (index TilesetIndex) CellFor(kind string, glyph rune) (int, bool) {
+	if cell, ok := index.Cells[kind]; ok && kind != "" {
+		return cell, true
+	}
+
+	if cell, ok := index.Cells[string(glyph)]; ok {
+		return cell, true
+	}
+
+	if index.Layout != TilesetLayoutCP437 {
+		return 0, false
+	}
+
+	if glyph >= 32 && glyph < 127 {
+		return int(glyph), true
+	}
+
+	cell, ok := cp437Extras[glyph]
+
+	return cell, ok
+}
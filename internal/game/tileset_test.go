@@ -0,0 +1,94 @@
+package game
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoadTilesetIndex(t *testing.T) {
+	t.Run("loads bundled tileset", func(t *testing.T) {
+		file, err := os.Open("../../assets/tilesets/neon/tileset.json")
+		if err != nil {
+			t.Fatalf("failed to open tileset index: %v", err)
+		}
+
+		defer func() {
+			_ = file.Close()
+		}()
+
+		index, err := LoadTilesetIndex(file)
+		if err != nil {
+			t.Fatalf("failed to load tileset index: %v", err)
+		}
+
+		for _, kind := range []string{"player", "floor", "wall"} {
+			if _, ok := index.CellFor(kind, '?'); !ok {
+				t.Errorf("want a cell for %s, got none", kind)
+			}
+		}
+	})
+
+	t.Run("defaults to tinted cp437", func(t *testing.T) {
+		index, err := LoadTilesetIndex(strings.NewReader(`{"image": "font.png", "tile_width": 8, "tile_height": 8}`))
+		if err != nil {
+			t.Fatalf("failed to load tileset index: %v", err)
+		}
+
+		if index.Layout != TilesetLayoutCP437 || !index.Tint {
+			t.Errorf("want tinted cp437 layout, got %q tint %v", index.Layout, index.Tint)
+		}
+	})
+
+	tests := []struct {
+		name string
+		json string
+	}{
+		{name: "missing image", json: `{"tile_width": 8, "tile_height": 8}`},
+		{name: "zero tile size", json: `{"image": "a.png", "tile_width": 0, "tile_height": 8}`},
+		{name: "unknown layout", json: `{"image": "a.png", "tile_width": 8, "tile_height": 8, "layout": "hex"}`},
+		{name: "negative cell", json: `{"image": "a.png", "tile_width": 8, "tile_height": 8, "cells": {"wall": -1}}`},
+		{name: "malformed", json: `{"image": `},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadTilesetIndex(strings.NewReader(tt.json))
+
+			if !errors.Is(err, ErrTilesetInvalid) {
+				t.Errorf("want %v, got %v", ErrTilesetInvalid, err)
+			}
+		})
+	}
+}
+
+func TestTilesetCellFor(t *testing.T) {
+	cp437 := TilesetIndex{Layout: TilesetLayoutCP437, Cells: map[string]int{"player": 1}}
+	custom := TilesetIndex{Layout: TilesetLayoutCustom, Cells: map[string]int{"#": 7}}
+
+	tests := []struct {
+		name   string
+		index  TilesetIndex
+		kind   string
+		glyph  rune
+		want   int
+		wantOK bool
+	}{
+		{name: "kind overrides glyph", index: cp437, kind: "player", glyph: '@', want: 1, wantOK: true},
+		{name: "ascii code", index: cp437, kind: "floor", glyph: '.', want: '.', wantOK: true},
+		{name: "box drawing code", index: cp437, kind: "wall", glyph: '█', want: 219, wantOK: true},
+		{name: "glyph entry", index: custom, kind: "wall", glyph: '#', want: 7, wantOK: true},
+		{name: "custom without entry", index: custom, kind: "floor", glyph: '.', wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cell, ok := tt.index.CellFor(tt.kind, tt.glyph)
+
+			if ok != tt.wantOK || (ok && cell != tt.want) {
+				t.Errorf("want (%d, %v), got (%d, %v)", tt.want, tt.wantOK, cell, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package game
+
+import (
+	"slices"
+	"unicode"
+)
+
+// Box-drawing characters used for panel borders.
+const (
+	boxHorizontal  = '─'
+	boxVertical    = '│'
+	boxTopLeft     = '┌'
+	boxTopRight    = '┐'
+	boxBottomLeft  = '└'
+	boxBottomRight = '┘'
+)
+
+// WidgetResult reports what a widget did with a command or key.
+type WidgetResult int
+
+const (
+	// WidgetNone means the input changed nothing the caller must act on.
+	WidgetNone WidgetResult = iota
+
+	// WidgetSubmitted means the user chose an item, entered text, or said
+	// yes.
+	WidgetSubmitted
+
+	// WidgetCancelled means the user backed out or said no.
+	WidgetCancelled
+)
+
+// Rect is an area of the screen measured in tiles.
+type Rect struct {
+	X      int // X is the left column.
+	Y      int // Y is the top row.
+	Width  int // Width is the number of columns.
+	Height int // Height is the number of rows.
+}
+
+// Contains reports whether the tile at (x, y) is inside the rectangle.
+func (rect Rect) Contains(x, y int) bool {
+	return x >= rect.X && x < rect.X+rect.Width && y >= rect.Y && y < rect.Y+rect.Height
+}
+
+// Inner returns the area inside a one tile border.
+func (rect Rect) Inner() Rect {
+	return Rect{X: rect.X + 1, Y: rect.Y + 1, Width: max(rect.Width-2, 0), Height: max(rect.Height-2, 0)}
+}
+
+// Panel is a box with a border of box-drawing characters and an optional
+// title set into the top border.
+type Panel struct {
+	Bounds Rect   // Bounds is the panel including its border.
+	Title  string // Title is shown in the top border.
+}
+
+// BorderRune returns the border character at the tile (x, y). ok is false
+// for tiles that are not on the border.
+func (panel Panel) BorderRune(x, y int) (rune, bool) {
+	bounds := panel.Bounds
+	if !bounds.Contains(x, y) {
+		return 0, false
+	}
+
+	left, right := x == bounds.X, x == bounds.X+bounds.Width-1
+	top, bottom := y == bounds.Y, y == bounds.Y+bounds.Height-1
+
+	switch {
+	case top && left:
+		return boxTopLeft, true
+	case top && right:
+		return boxTopRight, true
+	case bottom && left:
+		return boxBottomLeft, true
+	case bottom && right:
+		return boxBottomRight, true
+	case top || bottom:
+		return boxHorizontal, true
+	case left || right:
+		return boxVertical, true
+	}
+
+	return 0, false
+}
+
+// ListItem is one entry in a List.
+type ListItem struct {
+	Label  string // Label is the text shown for the item.
+	Hotkey rune   // Hotkey selects the item when typed; 0 if it has none.
+}
+
+// List is a scrollable list of items with a cursor. Items can be chosen by
+// moving the cursor and confirming, or by typing their letter hotkey.
+type List struct {
+	Items  []ListItem // Items are the entries in display order.
+	Cursor int        // Cursor is the index of the selected item.
+	Scroll int        // Scroll is the index of the first visible item.
+	Height int        // Height is the number of items visible at once.
+}
+
+// NewList creates a list of labels showing height items at a time. Items
+// are given hotkeys from hotkeys in order until they run out.
+func NewList(labels []string, height int, hotkeys []rune) List {
+	list := List{Items: make([]ListItem, len(labels)), Height: max(height, 1)}
+
+	for i, label := range labels {
+		list.Items[i].Label = label
+
+		if i < len(hotkeys) {
+			list.Items[i].Hotkey = hotkeys[i]
+		}
+	}
+
+	return list
+}
+
+// HandleCommand moves the cursor, chooses the selected item, or cancels the
+// list.
+func (list *List) HandleCommand(command Command) WidgetResult {
+	if len(list.Items) == 0 {
+		if command == CommandCancel {
+			return WidgetCancelled
+		}
+
+		return WidgetNone
+	}
+
+	switch command {
+	case CommandMenuUp:
+		list.Select(wrapCursor(list.Cursor, -1, len(list.Items)))
+	case CommandMenuDown:
+		list.Select(wrapCursor(list.Cursor, 1, len(list.Items)))
+	case CommandConfirm:
+		return WidgetSubmitted
+	case CommandCancel:
+		return WidgetCancelled
+	}
+
+	return WidgetNone
+}
+
+// HandleRune chooses the item whose hotkey is r.
+func (list *List) HandleRune(r rune) WidgetResult {
+	index := slices.IndexFunc(list.Items, func(item ListItem) bool {
+		return item.Hotkey != 0 && item.Hotkey == r
+	})
+
+	if index < 0 {
+		return WidgetNone
+	}
+
+	list.Select(index)
+
+	return WidgetSubmitted
+}
+
+// Select moves the cursor to index and scrolls so it is visible.
+func (list *List) Select(index int) {
+	list.Cursor = min(max(index, 0), max(len(list.Items)-1, 0))
+	list.Resize(list.Height)
+}
+
+// Resize changes how many items are visible and scrolls so the cursor
+// stays visible.
+func (list *List) Resize(height int) {
+	list.Height = max(height, 1)
+
+	if list.Cursor < list.Scroll {
+		list.Scroll = list.Cursor
+	}
+
+	if list.Cursor >= list.Scroll+list.Height {
+		list.Scroll = list.Cursor - list.Height + 1
+	}
+
+	list.Scroll = min(list.Scroll, max(len(list.Items)-list.Height, 0))
+}
+
+// Visible returns the range of item indexes currently shown, as [start, end).
+func (list List) Visible() (start, end int) {
+	return list.Scroll, min(list.Scroll+list.Height, len(list.Items))
+}
+
+// TextInput is a single line text field.
+type TextInput struct {
+	Prompt    string // Prompt is shown before the text.
+	Text      []rune // Text is what has been typed.
+	MaxLength int    // MaxLength limits the text length; 0 means no limit.
+}
+
+// Value returns the typed text.
+func (input TextInput) Value() string {
+	return string(input.Text)
+}
+
+// HandleRune appends a printable character to the text.
+func (input *TextInput) HandleRune(r rune) WidgetResult {
+	if !unicode.IsPrint(r) || (input.MaxLength > 0 && len(input.Text) >= input.MaxLength) {
+		return WidgetNone
+	}
+
+	input.Text = append(input.Text, r)
+
+	return WidgetNone
+}
+
+// HandleCommand deletes the last character, submits the text, or cancels
+// the field.
+func (input *TextInput) HandleCommand(command Command) WidgetResult {
+	switch command {
+	case CommandTextDelete:
+		if len(input.Text) > 0 {
+			input.Text = input.Text[:len(input.Text)-1]
+		}
+	case CommandTextSubmit:
+		return WidgetSubmitted
+	case CommandTextCancel:
+		return WidgetCancelled
+	}
+
+	return WidgetNone
+}
+
+// Dialog asks a yes or no question.
+type Dialog struct {
+	Question string // Question is what the player is asked.
+}
+
+// HandleCommand answers yes on confirm and no on cancel.
+func (dialog Dialog) HandleCommand(command Command) WidgetResult {
+	switch command {
+	case CommandConfirm:
+		return WidgetSubmitted
+	case CommandCancel:
+		return WidgetCancelled
+	}
+
+	return WidgetNone
+}
+
+// Tooltip is a short bordered note shown next to a tile.
+type Tooltip struct {
+	Text   string // Text is the note.
+	Bounds Rect   // Bounds is the tooltip including its border.
+}
+
+// NewTooltip places text in a box beside the tile at anchor, flipping to the
+// other side of the anchor when the box would leave screen.
+func NewTooltip(text string, anchor Point, screen Rect) Tooltip {
+	width := len([]rune(text)) + 2
+	height := 3
+
+	x := anchor.X + 1
+	if x+width > screen.X+screen.Width {
+		x = anchor.X - width
+	}
+
+	y := anchor.Y + 1
+	if y+height > screen.Y+screen.Height {
+		y = anchor.Y - height
+	}
+
+	x = max(x, screen.X)
+	y = max(y, screen.Y)
+
+	return Tooltip{Text: text, Bounds: Rect{X: x, Y: y, Width: width, Height: height}}
+}
+
+// FreeLetters returns the lower case letters that are not bound, without
+// modifiers, to any command in context. They can be used as list hotkeys
+// without triggering a command as well.
+func (keymap Keymap) FreeLetters(context InputContext) []rune {
+	var letters []rune
+
+	for letter := 'a'; letter <= 'z'; letter++ {
+		if _, bound := keymap.Lookup(context, string(unicode.ToUpper(letter))); bound {
+			continue
+		}
+
+		letters = append(letters, letter)
+	}
+
+	return letters
+}
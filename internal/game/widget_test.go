@@ -0,0 +1,175 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPanelBorderRune(t *testing.T) {
+	panel := Panel{Bounds: Rect{X: 1, Y: 1, Width: 4, Height: 3}}
+
+	tests := []struct {
+		name   string
+		x, y   int
+		want   rune
+		wantOK bool
+	}{
+		{name: "top left", x: 1, y: 1, want: boxTopLeft, wantOK: true},
+		{name: "top right", x: 4, y: 1, want: boxTopRight, wantOK: true},
+		{name: "bottom left", x: 1, y: 3, want: boxBottomLeft, wantOK: true},
+		{name: "bottom right", x: 4, y: 3, want: boxBottomRight, wantOK: true},
+		{name: "top edge", x: 2, y: 1, want: boxHorizontal, wantOK: true},
+		{name: "side edge", x: 1, y: 2, want: boxVertical, wantOK: true},
+		{name: "inside", x: 2, y: 2},
+		{name: "outside", x: 0, y: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := panel.BorderRune(tt.x, tt.y)
+
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("want %q %v, got %q %v", tt.want, tt.wantOK, got, ok)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	labels := []string{"one", "two", "three", "four", "five"}
+
+	t.Run("cursor wraps and scrolls", func(t *testing.T) {
+		list := NewList(labels, 2, nil)
+
+		list.HandleCommand(CommandMenuUp)
+
+		if list.Cursor != 4 {
+			t.Errorf("want cursor 4, got %d", list.Cursor)
+		}
+
+		if start, end := list.Visible(); start != 3 || end != 5 {
+			t.Errorf("want visible [3, 5), got [%d, %d)", start, end)
+		}
+
+		list.HandleCommand(CommandMenuDown)
+
+		if start, _ := list.Visible(); list.Cursor != 0 || start != 0 {
+			t.Errorf("want cursor and scroll 0, got %d and %d", list.Cursor, start)
+		}
+	})
+
+	t.Run("confirm and cancel", func(t *testing.T) {
+		list := NewList(labels, 5, nil)
+
+		if got := list.HandleCommand(CommandConfirm); got != WidgetSubmitted {
+			t.Errorf("want submitted, got %v", got)
+		}
+
+		if got := list.HandleCommand(CommandCancel); got != WidgetCancelled {
+			t.Errorf("want cancelled, got %v", got)
+		}
+	})
+
+	t.Run("hotkeys select items", func(t *testing.T) {
+		list := NewList(labels, 5, []rune("ab"))
+
+		if got := list.HandleRune('b'); got != WidgetSubmitted || list.Cursor != 1 {
+			t.Errorf("want submitted at 1, got %v at %d", got, list.Cursor)
+		}
+
+		if list.Items[2].Hotkey != 0 {
+			t.Errorf("want no hotkey once letters run out, got %q", list.Items[2].Hotkey)
+		}
+
+		if got := list.HandleRune('z'); got != WidgetNone {
+			t.Errorf("want unknown hotkey ignored, got %v", got)
+		}
+	})
+
+	t.Run("resize keeps cursor visible", func(t *testing.T) {
+		list := NewList(labels, 5, nil)
+		list.Select(4)
+
+		list.Resize(3)
+
+		if start, end := list.Visible(); start != 2 || end != 5 {
+			t.Errorf("want visible [2, 5), got [%d, %d)", start, end)
+		}
+	})
+}
+
+func TestTextInput(t *testing.T) {
+	input := TextInput{MaxLength: 3}
+
+	for _, r := range "ab\ncd" {
+		input.HandleRune(r)
+	}
+
+	if input.Value() != "abc" {
+		t.Errorf("want %q, got %q", "abc", input.Value())
+	}
+
+	input.HandleCommand(CommandTextDelete)
+
+	if input.Value() != "ab" {
+		t.Errorf("want %q after delete, got %q", "ab", input.Value())
+	}
+
+	if got := input.HandleCommand(CommandTextSubmit); got != WidgetSubmitted {
+		t.Errorf("want submitted, got %v", got)
+	}
+
+	if got := input.HandleCommand(CommandTextCancel); got != WidgetCancelled {
+		t.Errorf("want cancelled, got %v", got)
+	}
+}
+
+func TestDialog(t *testing.T) {
+	dialog := Dialog{Question: "Sure?"}
+
+	if got := dialog.HandleCommand(CommandConfirm); got != WidgetSubmitted {
+		t.Errorf("want yes submitted, got %v", got)
+	}
+
+	if got := dialog.HandleCommand(CommandCancel); got != WidgetCancelled {
+		t.Errorf("want no cancelled, got %v", got)
+	}
+}
+
+func TestNewTooltip(t *testing.T) {
+	screen := Rect{Width: 20, Height: 10}
+
+	tests := []struct {
+		name   string
+		anchor Point
+		want   Rect
+	}{
+		{name: "below right", anchor: Point{X: 2, Y: 2}, want: Rect{X: 3, Y: 3, Width: 6, Height: 3}},
+		{name: "flips left", anchor: Point{X: 18, Y: 2}, want: Rect{X: 12, Y: 3, Width: 6, Height: 3}},
+		{name: "flips up", anchor: Point{X: 2, Y: 9}, want: Rect{X: 3, Y: 6, Width: 6, Height: 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tooltip := NewTooltip("door", tt.anchor, screen)
+
+			if tooltip.Bounds != tt.want {
+				t.Errorf("want %+v, got %+v", tt.want, tooltip.Bounds)
+			}
+		})
+	}
+}
+
+func TestFreeLetters(t *testing.T) {
+	letters := DefaultKeymap().FreeLetters(ContextMenu)
+
+	for _, bound := range "kjyn" {
+		if slices.Contains(letters, bound) {
+			t.Errorf("want %q excluded as it is bound in menus", bound)
+		}
+	}
+
+	if !slices.Contains(letters, 'a') {
+		t.Error("want 'a' free in menus")
+	}
+}
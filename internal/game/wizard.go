@@ -0,0 +1,345 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// consoleLines is the number of output lines kept by the wizard console.
+const consoleLines = 12
+
+// ConsoleScreen holds the state of the wizard console.
+type ConsoleScreen struct {
+	Input  TextInput // Input is the command being typed.
+	Output []string  // Output holds the most recent commands and their results, oldest first.
+}
+
+// consoleCommand is a wizard console command.
+type consoleCommand struct {
+	usage string                                          // usage shows the command's arguments.
+	help  string                                          // help describes what the command does.
+	run   func(game *Game, args []string) (string, error) // run performs the command and describes the result.
+}
+
+// consoleCommands holds the wizard console commands by name. help is
+// handled by RunConsole, since it lists this map.
+var consoleCommands = map[string]consoleCommand{
+	"teleport": {usage: "teleport <x> <y>", help: "move to a tile", run: (*Game).consoleTeleport},
+	"spawn":    {usage: "spawn <monster|item> [hostile|friendly] <name>", help: "place an entity next to you", run: (*Game).consoleSpawn},
+	"reveal":   {usage: "reveal", help: "toggle showing the whole map", run: (*Game).consoleReveal},
+	"entities": {usage: "entities", help: "list every entity on the level", run: (*Game).consoleEntities},
+	"god":      {usage: "god", help: "toggle god mode", run: (*Game).consoleGod},
+	"set":      {usage: "set <stat> <value>", help: "set health, level, karma, nuyen, or depth", run: (*Game).consoleSet},
+	"regen":    {usage: "regen [seed] [generator]", help: "regenerate the level", run: (*Game).consoleRegen},
+	"dump":     {usage: "dump", help: "write the game state to a file", run: (*Game).consoleDump},
+}
+
+// OpenConsole shows the wizard console over the map. It does nothing unless
+// wizard mode is on.
+func (game *Game) OpenConsole() {
+	if !game.Wizard {
+		game.AddMessage("Wizard mode is off.")
+		return
+	}
+
+	game.Console.Input = TextInput{Prompt: "> "}
+	game.State = StateConsole
+}
+
+// handleConsoleCommand runs the typed console command or closes the console.
+func (game *Game) handleConsoleCommand(command Command) {
+	switch game.Console.Input.HandleCommand(command) {
+	case WidgetSubmitted:
+		line := game.Console.Input.Value()
+		game.Console.Input.Text = nil
+		game.consolePrint("> " + line)
+
+		output, err := game.RunConsole(line)
+		if err != nil {
+			game.consolePrint(err.Error())
+			return
+		}
+
+		game.consolePrint(output)
+	case WidgetCancelled:
+		game.State = StatePlaying
+	}
+}
+
+// consolePrint adds lines to the console output, dropping the oldest lines.
+func (game *Game) consolePrint(text string) {
+	if text == "" {
+		return
+	}
+
+	game.Console.Output = append(game.Console.Output, strings.Split(text, "\n")...)
+	game.Console.Output = game.Console.Output[max(len(game.Console.Output)-consoleLines, 0):]
+}
+
+// RunConsole runs a wizard console command line and describes the result.
+func (game *Game) RunConsole(line string) (string, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	if fields[0] == "help" {
+		return consoleHelp(), nil
+	}
+
+	command, ok := consoleCommands[fields[0]]
+	if !ok {
+		return "", fmt.Errorf("%w: %q, try help", ErrUnknownConsoleCommand, fields[0])
+	}
+
+	output, err := command.run(game, fields[1:])
+	if err != nil {
+		return "", fmt.Errorf("%w, usage: %s", err, command.usage)
+	}
+
+	return output, nil
+}
+
+// consoleInts parses args as exactly count integers.
+func consoleInts(args []string, count int) ([]int, error) {
+	if len(args) != count {
+		return nil, fmt.Errorf("%w: want %d numbers, got %d arguments", ErrConsoleUsage, count, len(args))
+	}
+
+	values := make([]int, count)
+	for i, arg := range args {
+		value, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a number", ErrConsoleUsage, arg)
+		}
+
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// consoleTeleport moves the player to any tile on the map, walls included.
+func (game *Game) consoleTeleport(args []string) (string, error) {
+	values, err := consoleInts(args, 2)
+	if err != nil {
+		return "", err
+	}
+
+	x, y := values[0], values[1]
+	if !game.InBounds(x, y) {
+		return "", fmt.Errorf("%w: %d,%d is off the map", ErrConsoleUsage, x, y)
+	}
+
+	game.Player.X, game.Player.Y = x, y
+	game.CameraX, game.CameraY = x, y
+	game.CancelTravel()
+
+	return fmt.Sprintf("Teleported to %d,%d.", x, y), nil
+}
+
+// consoleSpawn places a monster or item on the first free walkable tile
+// around the player. A monster may be given a disposition before its name.
+func (game *Game) consoleSpawn(args []string) (string, error) {
+	if len(args) < 2 {
+		return "", fmt.Errorf("%w: want a kind and a name", ErrConsoleUsage)
+	}
+
+	kind, args := EntityKind(args[0]), args[1:]
+
+	var disposition Disposition
+	if kind == EntityMonster && len(args) > 1 {
+		switch Disposition(args[0]) {
+		case DispositionHostile, DispositionFriendly:
+			disposition, args = Disposition(args[0]), args[1:]
+		}
+	}
+
+	name := strings.Join(args, " ")
+	entity := Entity{Kind: kind, Name: name}
+
+	// Defined monsters and items are spawned as defined; any other name
+	// gets a placeholder look so content can be tried before it is written
+	switch entity.Kind {
+	case EntityMonster:
+		def, ok := game.Content.Monster(name)
+		if !ok {
+			def = MonsterDef{Name: name, Glyph: string(unicode.ToLower([]rune(name)[0]))}
+		}
+
+		if disposition != "" {
+			def.Disposition = string(disposition)
+		}
+
+		entity = def.Entity(0, 0)
+	case EntityItem:
+		if def, ok := game.Content.Item(name); ok {
+			entity = def.Entity(0, 0)
+			break
+		}
+
+		entity.Glyph = '*'
+		entity.Color = RoleFriendly
+	default:
+		return "", fmt.Errorf("%w: unknown kind %q", ErrConsoleUsage, args[0])
+	}
+
+	x, y, ok := game.freeTileNear(game.Player.X, game.Player.Y)
+	if !ok {
+		return "", fmt.Errorf("%w: no free tile next to you", ErrConsoleUsage)
+	}
+
+	entity.X, entity.Y = x, y
+	game.SpawnEntity(entity)
+
+	return fmt.Sprintf("Spawned %s at %d,%d.", entity.Name, x, y), nil
+}
+
+// consoleEntities lists every entity on the level with its position, so
+// content can be checked without walking the map to find it.
+func (game *Game) consoleEntities(args []string) (string, error) {
+	if len(game.Entities) == 0 {
+		return "Nothing else is on this level.", nil
+	}
+
+	lines := make([]string, len(game.Entities))
+	for i, entity := range game.Entities {
+		lines[i] = fmt.Sprintf("%c %s (%s) at %d,%d", entity.Glyph, entity.Name, entity.Kind, entity.X, entity.Y)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// consoleReveal toggles showing the whole map, including tiles and entities
+// the player cannot see.
+func (game *Game) consoleReveal(args []string) (string, error) {
+	game.Revealed = !game.Revealed
+
+	return "Reveal map " + onOff(game.Revealed) + ".", nil
+}
+
+// consoleGod toggles god mode, in which the player takes no damage.
+func (game *Game) consoleGod(args []string) (string, error) {
+	game.GodMode = !game.GodMode
+
+	return "God mode " + onOff(game.GodMode) + ".", nil
+}
+
+// consoleSet changes one of the player's stats or the depth.
+func (game *Game) consoleSet(args []string) (string, error) {
+	if len(args) != 2 {
+		return "", fmt.Errorf("%w: want a stat and a value", ErrConsoleUsage)
+	}
+
+	values, err := consoleInts(args[1:], 1)
+	if err != nil {
+		return "", err
+	}
+
+	stats := map[string]*int{
+		"health": &game.Player.Health,
+		"level":  &game.Player.Level,
+		"karma":  &game.Player.Karma,
+		"nuyen":  &game.Player.Nuyen,
+		"depth":  &game.Depth,
+	}
+
+	stat, ok := stats[args[0]]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown stat %q", ErrConsoleUsage, args[0])
+	}
+
+	*stat = values[0]
+
+	return fmt.Sprintf("Set %s to %d.", args[0], values[0]), nil
+}
+
+// consoleRegen regenerates the level from a seed, or from a new random seed
+// when none is given, optionally switching to another level generator.
+func (game *Game) consoleRegen(args []string) (string, error) {
+	seed := game.rng.Int64()
+
+	if len(args) > 0 {
+		parsed, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("%w: %q is not a seed", ErrConsoleUsage, args[0])
+		}
+
+		seed = parsed
+	}
+
+	if len(args) > 1 {
+		generator, err := ParseGenerator(args[1])
+		if err != nil {
+			return "", err
+		}
+
+		game.Generator = generator
+	}
+
+	game.RegenerateLevel(seed)
+
+	return fmt.Sprintf("Regenerated the level with seed %d.", seed), nil
+}
+
+// consoleDump writes the game state as indented JSON to DumpDir.
+func (game *Game) consoleDump(args []string) (string, error) {
+	if game.DumpDir == "" {
+		return "", ErrDumpDisabled
+	}
+
+	data, err := game.MarshalState()
+	if err != nil {
+		return "", err
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, data, "", "  "); err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(game.DumpDir, 0o755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(game.DumpDir, fmt.Sprintf("state-turn-%d.json", game.TurnCount))
+	if err := writeFileAtomic(path, append(indented.Bytes(), '\n')); err != nil {
+		return "", err
+	}
+
+	return "Wrote " + path + ".", nil
+}
+
+// consoleHelp lists the console commands.
+func consoleHelp() string {
+	names := slices.Sorted(maps.Keys(consoleCommands))
+
+	lines := make([]string, 0, len(names)+1)
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("%-28s %s", consoleCommands[name].usage, consoleCommands[name].help))
+	}
+
+	lines = append(lines, fmt.Sprintf("%-28s %s", "help", "list console commands"))
+
+	return strings.Join(lines, "\n")
+}
+
+// RegenerateLevel rebuilds the current level from seed, removing its
+// entities and keeping the player.
+func (game *Game) RegenerateLevel(seed int64) {
+	game.SetSeed(seed)
+	game.Width, game.Height = mapWidth, mapHeight
+	game.Tiles = make([][]Tile, mapHeight)
+	game.initializeMap(mapWidth, mapHeight)
+	game.CancelTravel()
+
+	game.emit(Event{Kind: EventLevelEntered, Actor: game.Player.Name, X: game.Player.X, Y: game.Player.Y, Depth: game.Depth})
+}
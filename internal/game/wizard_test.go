@@ -0,0 +1,220 @@
+package game
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConsole(t *testing.T) {
+	t.Run("needs wizard mode", func(t *testing.T) {
+		game := NewGame()
+		game.StartGame()
+
+		game.HandleCommand(CommandConsole)
+
+		if game.State != StatePlaying {
+			t.Errorf("want state %v without wizard mode, got %v", StatePlaying, game.State)
+		}
+	})
+
+	t.Run("typed commands run", func(t *testing.T) {
+		game := NewGame()
+		game.Wizard = true
+		game.StartGame()
+		game.HandleCommand(CommandConsole)
+
+		if game.InputContext() != ContextText {
+			t.Fatalf("want text context in console, got %v", game.InputContext())
+		}
+
+		for _, r := range "teleport 40 8" {
+			game.HandleRune(r)
+		}
+
+		game.HandleCommand(CommandTextSubmit)
+
+		if game.Player.X != 40 || game.Player.Y != 8 {
+			t.Errorf("want player at 40,8, got %d,%d", game.Player.X, game.Player.Y)
+		}
+
+		if game.Console.Input.Value() != "" {
+			t.Errorf("want input cleared, got %q", game.Console.Input.Value())
+		}
+
+		if last := game.Console.Output[len(game.Console.Output)-1]; last != "Teleported to 40,8." {
+			t.Errorf("want result printed, got %q", last)
+		}
+
+		game.HandleCommand(CommandTextCancel)
+
+		if game.State != StatePlaying {
+			t.Errorf("want state %v after closing, got %v", StatePlaying, game.State)
+		}
+	})
+
+	t.Run("output is limited", func(t *testing.T) {
+		game := NewGame()
+
+		for range consoleLines {
+			game.consolePrint("a\nb")
+		}
+
+		if len(game.Console.Output) != consoleLines {
+			t.Errorf("want %d lines, got %d", consoleLines, len(game.Console.Output))
+		}
+	})
+}
+
+func TestRunConsole(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		wantErr error
+		check   func(t *testing.T, game *Game)
+	}{
+		{name: "unknown command", line: "fly", wantErr: ErrUnknownConsoleCommand},
+		{name: "teleport off map", line: "teleport 500 1", wantErr: ErrConsoleUsage},
+		{name: "teleport needs numbers", line: "teleport x 1", wantErr: ErrConsoleUsage},
+		{name: "teleport", line: "teleport 1 1", check: func(t *testing.T, game *Game) {
+			if game.Player.X != 1 || game.Player.Y != 1 {
+				t.Errorf("want player at 1,1, got %d,%d", game.Player.X, game.Player.Y)
+			}
+		}},
+		{name: "spawn monster", line: "spawn monster Street Samurai", check: func(t *testing.T, game *Game) {
+			if len(game.Entities) != 1 || game.Entities[0].Name != "Street Samurai" || game.Entities[0].Glyph != 's' {
+				t.Errorf("want street samurai spawned, got %+v", game.Entities)
+			}
+
+			if game.Entities[0].X == game.Player.X && game.Entities[0].Y == game.Player.Y {
+				t.Error("want monster beside the player, got the player's tile")
+			}
+		}},
+		{name: "spawn friendly monster", line: "spawn monster friendly bartender", check: func(t *testing.T, game *Game) {
+			if len(game.Entities) != 1 || game.Entities[0].Name != "bartender" || !game.Entities[0].Friendly() {
+				t.Errorf("want a friendly bartender spawned, got %+v", game.Entities)
+			}
+
+			if game.Entities[0].Color != RoleFriendly {
+				t.Errorf("want the friendly color, got %v", game.Entities[0].Color)
+			}
+		}},
+		{name: "spawn hostile by default", line: "spawn monster bartender", check: func(t *testing.T, game *Game) {
+			if len(game.Entities) != 1 || !game.Entities[0].Hostile() {
+				t.Errorf("want a hostile bartender spawned, got %+v", game.Entities)
+			}
+		}},
+		{name: "spawn unknown kind", line: "spawn vehicle car", wantErr: ErrConsoleUsage},
+		{name: "god mode", line: "god", check: func(t *testing.T, game *Game) {
+			game.DamagePlayer(1000, "a test")
+			game.Tick()
+
+			if game.Player.Health != 100 || game.State != StatePlaying {
+				t.Errorf("want no damage in god mode, got health %d", game.Player.Health)
+			}
+		}},
+		{name: "set stat", line: "set karma 7", check: func(t *testing.T, game *Game) {
+			if game.Player.Karma != 7 {
+				t.Errorf("want karma 7, got %d", game.Player.Karma)
+			}
+		}},
+		{name: "set unknown stat", line: "set luck 7", wantErr: ErrConsoleUsage},
+		{name: "regen with seed", line: "regen 1234", check: func(t *testing.T, game *Game) {
+			if game.Seed != 1234 {
+				t.Errorf("want seed 1234, got %d", game.Seed)
+			}
+		}},
+		{name: "regen caves", line: "regen 1234 caves", check: func(t *testing.T, game *Game) {
+			if game.Generator != GeneratorCaves || !game.Tiles[game.Player.Y][game.Player.X].Walkable {
+				t.Errorf("want the player on a cave floor, got generator %q", game.Generator)
+			}
+		}},
+		{name: "regen unknown generator", line: "regen 1234 maze", wantErr: ErrUnknownGenerator},
+		{name: "dump without directory", line: "dump", wantErr: ErrDumpDisabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := NewGame()
+			game.Wizard = true
+			game.StartGame()
+
+			_, err := game.RunConsole(tt.line)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want error %v, got %v", tt.wantErr, err)
+			}
+
+			if tt.check != nil {
+				tt.check(t, game)
+			}
+		})
+	}
+}
+
+func TestConsoleEntities(t *testing.T) {
+	game := NewGame()
+	game.SpawnEntity(Entity{Kind: EntityItem, Name: "medkit", Glyph: '*', X: 13, Y: 9})
+
+	output, err := game.RunConsole("entities")
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	if !strings.Contains(output, "medkit (item) at 13,9") {
+		t.Errorf("want entity listed, got %q", output)
+	}
+}
+
+func TestConsoleReveal(t *testing.T) {
+	game := NewGame()
+
+	for _, want := range []bool{true, false} {
+		if _, err := game.RunConsole("reveal"); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if game.Revealed != want {
+			t.Errorf("want revealed %t, got %t", want, game.Revealed)
+		}
+
+		if !game.Visible(0, 0) || game.Visible(-1, 0) {
+			t.Errorf("want every tile on the map visible, revealed %t", game.Revealed)
+		}
+	}
+}
+
+func TestConsoleDump(t *testing.T) {
+	game := NewGame()
+	game.DumpDir = filepath.Join(t.TempDir(), "dumps")
+
+	if _, err := game.RunConsole("dump"); err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(game.DumpDir, "state-turn-0.json"))
+	if err != nil {
+		t.Fatalf("want dump written, got %v", err)
+	}
+
+	if err := NewGame().RestoreState(data); err != nil {
+		t.Errorf("want dump to restore, got %v", err)
+	}
+}
+
+func TestWizardRunsFlagged(t *testing.T) {
+	game := NewGame()
+	game.Wizard = true
+	game.StartGame()
+
+	game.EndRun("Abandoned.")
+
+	if !game.Scores.Runs[0].Wizard {
+		t.Error("want wizard run flagged in scores, got false")
+	}
+
+	if !strings.Contains(game.MorgueText("Abandoned."), "Wizard mode") {
+		t.Error("want wizard mode noted in the morgue")
+	}
+}